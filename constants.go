@@ -3,3 +3,7 @@ package scheduler
 const _NoAvailability int64 = -1
 
 const _ScheduledForStart = 0
+
+// _BookingAttempts bounds how many times a booking is evaluated again
+// when resources are booked directly, outside the location, meanwhile.
+const _BookingAttempts = 3
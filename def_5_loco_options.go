@@ -9,24 +9,32 @@ import (
 type OptionSchedule struct {
 	WhenCanStart int64
 	Resources    ResourcesPerType
-}
+	Waste        int // quantity served over the needed quantities
 
-func (option OptionSchedule) GetCostFor(task *Run) (float32, error) {
-	var costTotal float32
+	interval *TimeInterval // location time, as it would be booked, nil if not known
+	pricing  *runPricing   // of the location, nil prices without calendars
+}
 
-	for _, resources := range option.Resources {
-		for _, resource := range resources {
-			costResource, errGetCost := calculateTaskCost(task, resource)
-			if errGetCost != nil {
-				return 0,
-					errGetCost
-			}
+// GetCostFor returns the cost of the task on the option resources, priced over the option time if known.
+func (option OptionSchedule) GetCostFor(task *Run) (Money, error) {
+	var interval *TimeInterval
 
-			costTotal = costTotal + costResource
+	if option.interval != nil {
+		interval = &TimeInterval{
+			TimeStart:     option.interval.TimeStart,
+			TimeEnd:       option.interval.TimeStart + task.EstimatedDuration,
+			SecondsOffset: option.interval.SecondsOffset,
+			TimeZone:      option.interval.TimeZone,
 		}
 	}
 
-	return costTotal,
+	cost, errGetCost := option.pricing.at(task, interval).getCost(option.Resources.getResources())
+	if errGetCost != nil {
+		return Money{},
+			errGetCost
+	}
+
+	return cost.Total,
 		nil
 }
 
@@ -71,7 +79,7 @@ func (option OptionSchedule) String(task *Run) string {
 
 	sb.WriteString(
 		fmt.Sprintf(
-			" cost: %.2f",
+			" cost: %s",
 
 			cost,
 		),
@@ -2,71 +2,114 @@ package scheduler
 
 import (
 	"sync"
+
+	goerrors "github.com/TudorHulban/go-errors"
 )
 
+// Loco is the engine over resources held per type, kept for compatibility.
 type Loco struct {
 	Name      string
 	Resources ResourcesPerType
 
-	mu sync.Mutex
+	mu     sync.Mutex
+	engine *Engine   // created at first use, keeps the holds and the location lock
+	quotes quoteBook // issued, by ID
 
 	ID             int64
 	LocationOffset int64
-}
-
-// 1. breakdown interval
-// 2. check available resources
-// 3. sort resources as per search attributes
 
-func (loc *Loco) GetSchedulingOptions(params *ParamsCanRun) (OptionsSchedule, error) {
-	intervalsSought := params.TimeInterval.BreakDown(params.TaskRun.EstimatedDuration)
+	Calendar *Calendar         // opening hours, nil is always open
+	Pricing  *PricingCalendar  // for resources without one, nil prices all time alike
+	Currency Currency          // of all costs, empty takes the one of the resources
+	Rounding RoundingMode      // of load and time charges, default half to even
+	Strategy SelectionStrategy // how resources are selected, nil is PreferredFirst
+}
 
-	resourcesNeededPerType := params.TaskRun.GetNeededResourcesPerType()
-	neededTypes := params.TaskRun.GetNeededResourceTypes()
+func (loc *Loco) getStrategy() SelectionStrategy {
+	return getStrategy(loc.Strategy, PreferredFirst{})
+}
 
-	result := make([]*OptionSchedule, 0)
+// GetEngine returns the engine over the loco resources, to book them.
+// The same engine is returned on each call, with the current loco settings, so holds
+// taken through any call are confirmed or released through any other.
+// Bookings are kept by the resources, holds by the engine.
+// Resources priced in different currencies are an error.
+func (loc *Loco) GetEngine() (*Engine, error) {
+	loc.mu.Lock()
+	defer loc.mu.Unlock()
+
+	resources := loc.Resources.getResources()
+
+	currency, errCurrency := getCurrencyOf(loc.Currency, resources)
+	if errCurrency != nil {
+		return nil,
+			goerrors.ErrValidation{
+				Caller: "GetEngine",
+				Issue:  errCurrency,
+			}
+	}
 
-	for _, interval := range intervalsSought {
-		intervalResourcesNeeded := make(ResourcesPerType)
+	if loc.engine == nil {
+		loc.engine = &Engine{}
+	}
 
-		for _, neededType := range neededTypes {
-			resourcesNeededPerCurrentType := resourcesNeededPerType[neededType]
+	loc.engine.mu.Lock()
+	defer loc.engine.mu.Unlock()
 
-			intervalResourcesPerCurrentType := make([]*ResourceScheduled, 0)
+	loc.engine.ID = loc.ID
+	loc.engine.Name = loc.Name
+	loc.engine.LocationOffset = loc.LocationOffset
+	loc.engine.Calendar = loc.Calendar
+	loc.engine.Pricing = loc.Pricing
+	loc.engine.Currency = currency
+	loc.engine.Rounding = loc.Rounding
+	loc.engine.Strategy = loc.getStrategy()
+	loc.engine.Resources = resources
 
-			var qty uint16
+	return loc.engine,
+		nil
+}
 
-			for _, resource := range loc.Resources[neededType] {
-				if isAvailable := resource.IsAvailableIn(&interval); !isAvailable {
-					continue
-				}
+// toOptionsSchedule groups the selected resources of the options per type.
+func toOptionsSchedule(options []*SchedulingOption) OptionsSchedule {
+	result := make(OptionsSchedule, len(options))
 
-				intervalResourcesPerCurrentType = append(intervalResourcesPerCurrentType, resource)
+	for ix, option := range options {
+		result[ix] = toOptionSchedule(option)
+	}
 
-				qty = qty + resource.ServedQuantity
+	return result
+}
 
-				if qty == resourcesNeededPerCurrentType {
-					break
-				}
-			}
+// toOptionSchedule groups the selected resources of the option per type.
+func toOptionSchedule(option *SchedulingOption) *OptionSchedule {
+	return &OptionSchedule{
+		WhenCanStart: option.WhenCanStart,
+		Resources:    newResourcesPerType(option.SelectedResources),
+		Waste:        option.Waste,
 
-			if qty < resourcesNeededPerCurrentType {
-				break //interval cannot provide all resources
-			}
+		interval: &option.interval,
+		pricing:  option.pricing,
+	}
+}
 
-			intervalResourcesNeeded[neededType] = intervalResourcesPerCurrentType
-		}
+// GetSchedulingOptions returns an option per start, with the resources selected by the strategy.
+func (loc *Loco) GetSchedulingOptions(params *ParamsCanRun) (OptionsSchedule, error) {
+	paramsEngine := *params
+	paramsEngine.AllPossibilities = false
 
-		result = append(
-			result,
+	engine, errEngine := loc.GetEngine()
+	if errEngine != nil {
+		return nil,
+			errEngine
+	}
 
-			&OptionSchedule{
-				WhenCanStart: interval.TimeStart,
-				Resources:    intervalResourcesNeeded,
-			},
-		)
+	options, errGet := engine.GetSchedulingOptions(&paramsEngine)
+	if errGet != nil {
+		return nil,
+			errGet
 	}
 
-	return result,
+	return toOptionsSchedule(options),
 		nil
 }
@@ -18,41 +18,41 @@ func TestSimpleLoco(t *testing.T) {
 					ResourceInfo: ResourceInfo{
 						ID:              1,
 						Name:            "Resource 1",
-						CostPerLoadUnit: map[uint8]float32{1: 2.0},
+						CostPerLoadUnit: map[uint8]Money{1: {Amount: 2}},
 						ResourceType:    1,
 						ServedQuantity:  1,
 					},
 
-					schedule: map[TimeInterval]RunID{
+					schedule: newTestSchedule(map[TimeInterval]RunID{
 						{TimeStart: now, TimeEnd: now + halfHour}:                     Maintenance,
 						{TimeStart: now + oneHour, TimeEnd: now + oneHour + halfHour}: Maintenance,
-					},
+					}),
 				},
 				{
 					ResourceInfo: ResourceInfo{
 						ID:              2,
 						Name:            "Resource 2",
-						CostPerLoadUnit: map[uint8]float32{1: 3.0},
+						CostPerLoadUnit: map[uint8]Money{1: {Amount: 3}},
 						ResourceType:    1,
 						ServedQuantity:  1,
 					},
 
-					schedule: map[TimeInterval]RunID{
+					schedule: newTestSchedule(map[TimeInterval]RunID{
 						{TimeStart: now + oneHour, TimeEnd: now + oneHour + halfHour}: Maintenance,
-					},
+					}),
 				},
 				{
 					ResourceInfo: ResourceInfo{
 						ID:              3,
 						Name:            "Resource 3",
-						CostPerLoadUnit: map[uint8]float32{1: 2.0},
+						CostPerLoadUnit: map[uint8]Money{1: {Amount: 2}},
 						ResourceType:    1,
 						ServedQuantity:  1,
 					},
 
-					schedule: map[TimeInterval]RunID{
+					schedule: newTestSchedule(map[TimeInterval]RunID{
 						{TimeStart: now, TimeEnd: now + halfHour}: Maintenance,
-					},
+					}),
 				},
 			},
 			2: []*ResourceScheduled{
@@ -60,12 +60,12 @@ func TestSimpleLoco(t *testing.T) {
 					ResourceInfo: ResourceInfo{
 						ID:              4,
 						Name:            "Resource 4",
-						CostPerLoadUnit: map[uint8]float32{1: 1.0},
+						CostPerLoadUnit: map[uint8]Money{1: {Amount: 1}},
 						ResourceType:    2,
 						ServedQuantity:  1,
 					},
 
-					schedule: map[TimeInterval]RunID{},
+					schedule: newTestSchedule(map[TimeInterval]RunID{}),
 				},
 			},
 		},
@@ -0,0 +1,352 @@
+package scheduler
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	goerrors "github.com/TudorHulban/go-errors"
+)
+
+// Currency is an ISO 4217 code, ex. "EUR".
+type Currency string
+
+// minorUnits are the decimals of the currencies not having two.
+var minorUnits = map[Currency]uint8{
+	"BHD": 3,
+	"CLP": 0,
+	"IQD": 3,
+	"ISK": 0,
+	"JOD": 3,
+	"JPY": 0,
+	"KRW": 0,
+	"KWD": 3,
+	"LYD": 3,
+	"OMR": 3,
+	"TND": 3,
+	"VND": 0,
+}
+
+// GetMinorUnits returns the decimals of the currency, two if not listed otherwise.
+func (c Currency) GetMinorUnits() uint8 {
+	if decimals, isListed := minorUnits[c]; isListed {
+		return decimals
+	}
+
+	return 2
+}
+
+func (c Currency) isValid() bool {
+	if len(c) != 3 {
+		return false
+	}
+
+	for _, letter := range c {
+		if letter < 'A' || letter > 'Z' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// RoundingMode is how amounts finer than the minor unit are rounded.
+// Costs are rounded once per charge, load and time of a cost line, after the pricing calendar factor.
+// Fees, lines and totals are exact sums of minor units.
+type RoundingMode uint8
+
+const (
+	RoundHalfEven RoundingMode = iota // to nearest, ties to the even minor unit, default
+	RoundHalfUp                       // to nearest, ties away from zero
+	RoundDown                         // toward zero
+	RoundUp                           // away from zero
+)
+
+func (r RoundingMode) String() string {
+	switch r {
+	case RoundHalfEven:
+		return "HalfEven"
+	case RoundHalfUp:
+		return "HalfUp"
+	case RoundDown:
+		return "Down"
+	case RoundUp:
+		return "Up"
+	}
+
+	return fmt.Sprintf("RoundingMode(%d)", r)
+}
+
+// round returns the amount rounded to an integer.
+func (r RoundingMode) round(amount *big.Rat) int64 {
+	quotient, remainder := new(big.Int).QuoRem(amount.Num(), amount.Denom(), new(big.Int)) // toward zero
+
+	if remainder.Sign() == 0 {
+		return quotient.Int64()
+	}
+
+	away := int64(amount.Sign())
+
+	switch r {
+	case RoundDown:
+		return quotient.Int64()
+
+	case RoundUp:
+		return quotient.Int64() + away
+	}
+
+	// compares the remainder to half the denominator
+	half := new(big.Int).Abs(remainder)
+	half.Lsh(half, 1)
+
+	switch half.Cmp(amount.Denom()) {
+	case -1:
+		return quotient.Int64()
+
+	case 1:
+		return quotient.Int64() + away
+	}
+
+	if r == RoundHalfUp || quotient.Bit(0) == 1 {
+		return quotient.Int64() + away
+	}
+
+	return quotient.Int64()
+}
+
+// decimalOf returns the shortest decimal reading back as the value, ex. 0.35 not 0.3499999940395355.
+// Zero if the value is not finite.
+func decimalOf(value float32) *big.Rat {
+	result, isParsed := new(big.Rat).SetString(
+		strconv.FormatFloat(float64(value), 'g', -1, 32),
+	)
+	if !isParsed {
+		return new(big.Rat)
+	}
+
+	return result
+}
+
+// Money is an exact amount, Amount minor units of Currency, ex. 1234 EUR is 12.34 EUR.
+// Money without currency is in the currency of its location.
+type Money struct {
+	Amount   int64
+	Currency Currency
+}
+
+func NewMoney(amount int64, currency Currency) Money {
+	return Money{
+		Amount:   amount,
+		Currency: currency,
+	}
+}
+
+// ParseMoney parses a decimal amount, ex. "-12.34", in the currency.
+// More decimals than the currency minor units is an error, not rounded.
+func ParseMoney(amount string, currency Currency) (Money, error) {
+	newError := func(issue error) error {
+		return goerrors.ErrInvalidInput{
+			Caller:     "ParseMoney",
+			InputName:  "amount",
+			InputValue: amount,
+			Issue:      issue,
+		}
+	}
+
+	whole, fraction, hasFraction := strings.Cut(amount, ".")
+
+	decimals := int(currency.GetMinorUnits())
+
+	if hasFraction && (len(fraction) == 0 || len(fraction) > decimals) {
+		return Money{},
+			newError(
+				fmt.Errorf("%s takes up to %d decimals", currency, decimals),
+			)
+	}
+
+	isNegative := strings.HasPrefix(whole, "-")
+
+	if len(whole) == ternary(isNegative, 1, 0) {
+		return Money{},
+			newError(errors.New("missing units"))
+	}
+
+	digits := strings.TrimPrefix(whole, "-") + fraction + strings.Repeat("0", decimals-len(fraction))
+
+	if strings.ContainsAny(digits, "+-") {
+		return Money{},
+			newError(errors.New("misplaced sign"))
+	}
+
+	result, errParse := strconv.ParseInt(digits, 10, 64)
+	if errParse != nil {
+		return Money{},
+			newError(errParse)
+	}
+
+	return NewMoney(
+			ternary(isNegative, -result, result),
+			currency,
+		),
+		nil
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// String formats the amount in the currency decimals, ex. "12.34 EUR".
+func (m Money) String() string {
+	if len(m.Currency) == 0 {
+		return m.formatAmount()
+	}
+
+	return m.formatAmount() + " " + string(m.Currency)
+}
+
+// formatAmount formats the amount in the currency decimals, ex. "12.34".
+func (m Money) formatAmount() string {
+	decimals := m.Currency.GetMinorUnits()
+
+	amount := strings.TrimPrefix(strconv.FormatInt(m.Amount, 10), "-")
+
+	if decimals > 0 {
+		if padding := int(decimals) + 1 - len(amount); padding > 0 {
+			amount = strings.Repeat("0", padding) + amount
+		}
+
+		amount = amount[:len(amount)-int(decimals)] + "." + amount[len(amount)-int(decimals):]
+	}
+
+	if m.Amount < 0 {
+		amount = "-" + amount
+	}
+
+	return amount
+}
+
+type moneyJSON struct {
+	Amount   string   `json:"amount"` // decimal, exact
+	Currency Currency `json:"currency,omitempty"`
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(
+		moneyJSON{
+			Amount:   m.formatAmount(),
+			Currency: m.Currency,
+		},
+	)
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var decoded moneyJSON
+
+	if errUnmarshal := json.Unmarshal(data, &decoded); errUnmarshal != nil {
+		return errUnmarshal
+	}
+
+	result, errParse := ParseMoney(decoded.Amount, decoded.Currency)
+	if errParse != nil {
+		return errParse
+	}
+
+	*m = result
+
+	return nil
+}
+
+// isCompatible returns true if the amounts share a currency, no currency matching any.
+func (m Money) isCompatible(other Money) bool {
+	return len(m.Currency) == 0 || len(other.Currency) == 0 || m.Currency == other.Currency
+}
+
+// Add returns the sum, an error for different currencies.
+func (m Money) Add(other Money) (Money, error) {
+	if !m.isCompatible(other) {
+		return Money{},
+			goerrors.ErrValidation{
+				Caller: "Add",
+				Issue: fmt.Errorf(
+					"mixed currencies %s and %s",
+					m.Currency,
+					other.Currency,
+				),
+			}
+	}
+
+	return m.add(other),
+		nil
+}
+
+// add is Add for amounts of a location, whose currency is checked on creation.
+func (m Money) add(other Money) Money {
+	return Money{
+		Amount:   m.Amount + other.Amount,
+		Currency: ternary(len(m.Currency) > 0, m.Currency, other.Currency),
+	}
+}
+
+// scale returns the amount times the exact quantity, rounded once to minor units.
+func (m Money) scale(quantity *big.Rat, rounding RoundingMode) Money {
+	return Money{
+		Amount: rounding.round(
+			new(big.Rat).Mul(new(big.Rat).SetInt64(m.Amount), quantity),
+		),
+		Currency: m.Currency,
+	}
+}
+
+// in returns the amount in the currency if it has none.
+func (m Money) in(currency Currency) Money {
+	if len(m.Currency) == 0 {
+		m.Currency = currency
+	}
+
+	return m
+}
+
+func compareMoney(a, b Money) int {
+	return cmp.Compare(a.Amount, b.Amount)
+}
+
+// getCurrency returns the currency of the amounts, the passed one unless empty.
+// Amounts in another currency are an error.
+func getCurrency(currency Currency, amounts ...Money) (Currency, error) {
+	if len(currency) > 0 && !currency.isValid() {
+		return "",
+			goerrors.ErrInvalidInput{
+				Caller:     "getCurrency",
+				InputName:  "Currency",
+				InputValue: currency,
+			}
+	}
+
+	result := Money{Currency: currency}
+
+	for _, amount := range amounts {
+		if len(amount.Currency) > 0 && !amount.Currency.isValid() {
+			return "",
+				goerrors.ErrInvalidInput{
+					Caller:     "getCurrency",
+					InputName:  "Currency",
+					InputValue: amount.Currency,
+				}
+		}
+
+		sum, errAdd := result.Add(amount)
+		if errAdd != nil {
+			return "",
+				errAdd
+		}
+
+		result.Currency = sum.Currency
+	}
+
+	return result.Currency,
+		nil
+}
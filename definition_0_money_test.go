@@ -0,0 +1,289 @@
+package scheduler
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoney(t *testing.T) {
+	t.Run(
+		"1. Parse and format",
+		func(t *testing.T) {
+			tests := []struct {
+				amount    string
+				currency  Currency
+				expected  int64
+				formatted string
+			}{
+				{"12.34", "EUR", 1234, "12.34 EUR"},
+				{"12.3", "EUR", 1230, "12.30 EUR"},
+				{"-0.05", "EUR", -5, "-0.05 EUR"},
+				{"7", "USD", 700, "7.00 USD"},
+				{"1500", "JPY", 1500, "1500 JPY"},
+				{"1.005", "KWD", 1005, "1.005 KWD"},
+			}
+
+			for _, tt := range tests {
+				money, errParse := ParseMoney(tt.amount, tt.currency)
+				require.NoError(t, errParse, tt.amount)
+				require.Equal(t,
+					NewMoney(tt.expected, tt.currency),
+					money,
+				)
+				require.Equal(t,
+					tt.formatted,
+					money.String(),
+				)
+			}
+
+			for _, amount := range []string{"1.234", "12.", "", "-", "1-2", "+-1", "abc"} {
+				_, errParse := ParseMoney(amount, "EUR")
+				require.Error(t, errParse, amount)
+			}
+
+			_, errParse := ParseMoney("1.5", "JPY")
+			require.Error(t, errParse)
+
+			require.Equal(t,
+				"-92233720368547758.08 EUR",
+				NewMoney(math.MinInt64, "EUR").String(),
+			)
+		},
+	)
+
+	t.Run(
+		"2. Rounding",
+		func(t *testing.T) {
+			tests := []struct {
+				rounding RoundingMode
+				expected []int64 // 2.5, -2.5, 2.4, 2.6
+			}{
+				{RoundHalfEven, []int64{2, -2, 2, 3}},
+				{RoundHalfUp, []int64{3, -3, 2, 3}},
+				{RoundDown, []int64{2, -2, 2, 2}},
+				{RoundUp, []int64{3, -3, 3, 3}},
+			}
+
+			for _, tt := range tests {
+				require.Equal(t,
+					tt.expected,
+					[]int64{
+						NewMoney(5, "EUR").scale(big.NewRat(1, 2), tt.rounding).Amount,
+						NewMoney(-5, "EUR").scale(big.NewRat(1, 2), tt.rounding).Amount,
+						NewMoney(24, "EUR").scale(big.NewRat(1, 10), tt.rounding).Amount,
+						NewMoney(26, "EUR").scale(big.NewRat(1, 10), tt.rounding).Amount,
+					},
+					tt.rounding.String(),
+				)
+			}
+		},
+	)
+
+	t.Run(
+		"3. Currencies not mixed",
+		func(t *testing.T) {
+			sum, errAdd := NewMoney(100, "EUR").Add(Money{Amount: 5})
+			require.NoError(t, errAdd)
+			require.Equal(t,
+				NewMoney(105, "EUR"),
+				sum,
+			)
+
+			_, errMixed := NewMoney(100, "EUR").Add(NewMoney(5, "USD"))
+			require.Error(t, errMixed)
+
+			_, errResource := NewResource(
+				&ParamsNewResource{
+					Name:         "Mixed",
+					ResourceType: 1,
+					CostPerLoadUnit: map[uint8]Money{
+						1: NewMoney(10, "EUR"),
+						2: NewMoney(10, "USD"),
+					},
+				},
+			)
+			require.Error(t, errResource)
+		},
+	)
+}
+
+func TestEngineMoney(t *testing.T) {
+	newResource := func(id int, costPerHour Money) *ResourceScheduled {
+		result := newTestResource(id, 1, 0, nil)
+		result.CostPerHour = costPerHour
+
+		return result
+	}
+
+	run := *newTestRun(1, oneHour/3, 1)
+	run.Dependencies[0].ResourceQuantity = 3
+
+	params := ParamsCanRun{
+		TimeInterval: TimeInterval{
+			TimeStart: now,
+			TimeEnd:   now + oneHour,
+		},
+		TaskRun: &run,
+	}
+
+	t.Run(
+		"1. Mixed currencies rejected",
+		func(t *testing.T) {
+			_, errMixed := NewEngine(
+				&ParamsNewEngine{
+					ID:   1,
+					Name: t.Name(),
+					Resources: []*ResourceScheduled{
+						newResource(1, NewMoney(100, "EUR")),
+						newResource(2, NewMoney(100, "USD")),
+					},
+				},
+			)
+			require.Error(t, errMixed)
+
+			_, errOther := NewEngine(
+				&ParamsNewEngine{
+					ID:       1,
+					Name:     t.Name(),
+					Currency: "USD",
+					Resources: []*ResourceScheduled{
+						newResource(1, NewMoney(100, "EUR")),
+					},
+				},
+			)
+			require.Error(t, errOther)
+
+			_, errInvalid := NewEngine(
+				&ParamsNewEngine{
+					ID:       1,
+					Name:     t.Name(),
+					Currency: "euro",
+					Resources: []*ResourceScheduled{
+						newResource(1, Money{Amount: 100}),
+					},
+				},
+			)
+			require.Error(t, errInvalid)
+
+			loco := Loco{
+				ID:   1,
+				Name: t.Name(),
+
+				Resources: ResourcesPerType{
+					1: {
+						newResource(1, NewMoney(100, "EUR")),
+						newResource(2, NewMoney(100, "USD")),
+					},
+				},
+			}
+
+			_, errLoco := loco.GetSchedulingOptions(&params)
+			require.Error(t, errLoco)
+		},
+	)
+
+	t.Run(
+		"2. Charges rounded once, totals exact",
+		func(t *testing.T) {
+			tests := []struct {
+				rounding RoundingMode
+				expected int64
+			}{
+				{RoundHalfEven, 3 * 33},
+				{RoundUp, 3 * 34},
+			}
+
+			for _, tt := range tests {
+				engine, errCr := NewEngine(
+					&ParamsNewEngine{
+						ID:       1,
+						Name:     t.Name(),
+						Currency: "EUR",
+						Rounding: tt.rounding,
+						Resources: []*ResourceScheduled{
+							newResource(1, Money{Amount: 100}),
+							newResource(2, Money{Amount: 100}),
+							newResource(3, Money{Amount: 100}),
+						},
+					},
+				)
+				require.NoError(t, errCr)
+
+				cost, errCost := engine.GetCostAt(&run, engine.Resources, &params.TimeInterval)
+				require.NoError(t, errCost)
+
+				var sumLines int64
+
+				for _, line := range cost.Lines {
+					require.Equal(t,
+						Currency("EUR"),
+						line.Cost.Currency,
+					)
+
+					sumLines = sumLines + line.Cost.Amount
+				}
+
+				require.Equal(t,
+					NewMoney(tt.expected, "EUR"),
+					cost.Total,
+				)
+				require.Equal(t,
+					sumLines,
+					cost.Total.Amount,
+				)
+
+				response, errSchedule := engine.CanSchedule(&params)
+				require.NoError(t, errSchedule)
+				require.True(t, response.WasScheduled)
+				require.Equal(t,
+					cost.Total,
+					response.Cost,
+				)
+			}
+		},
+	)
+
+	t.Run(
+		"3. Load charge exact on ties",
+		func(t *testing.T) {
+			tests := []struct {
+				rounding RoundingMode
+				load     float32
+				cost     int64
+				expected int64
+			}{
+				{RoundHalfUp, 0.35, 10, 4},   // 0.35 x 0.10, not 0.3499999940395355 x 0.10
+				{RoundHalfEven, 1.1, 15, 16}, // 1.1 x 0.15, not 1.100000023841858 x 0.15
+			}
+
+			for _, tt := range tests {
+				engine, errCr := NewEngine(
+					&ParamsNewEngine{
+						ID:       1,
+						Name:     t.Name(),
+						Currency: "EUR",
+						Rounding: tt.rounding,
+						Resources: []*ResourceScheduled{
+							newTestResource(1, 1, tt.cost, nil),
+						},
+					},
+				)
+				require.NoError(t, errCr)
+
+				run := *newTestRun(1, oneHour, 1)
+				run.Load = tt.load
+
+				cost, errCost := engine.GetCostAt(&run, engine.Resources, &params.TimeInterval)
+				require.NoError(t, errCost)
+				require.Equal(t,
+					NewMoney(tt.expected, "EUR"),
+					cost.Lines[0].CostLoad,
+					tt.rounding.String(),
+				)
+			}
+		},
+	)
+}
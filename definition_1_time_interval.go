@@ -1,9 +1,18 @@
 package scheduler
 
+import "time"
+
+// TimeInterval holds local time, UTC = local - SecondsOffset.
+// If TimeZone is set, local is the wall clock in that zone, offsets are resolved
+// for each end so an interval crossing a DST change keeps its real duration.
+// In that case SecondsOffset is informative, holding the offset at TimeStart.
+// TimeZone is a pointer, == and map keys match only the same load of a zone, see isSame.
 type TimeInterval struct {
 	TimeStart     int64
 	TimeEnd       int64
 	SecondsOffset int64
+
+	TimeZone *time.Location
 }
 
 func (interval *TimeInterval) NoIntervals(perDuration int64) int {
@@ -48,9 +57,17 @@ func (interval *TimeInterval) BreakDown(perDuration int64) []TimeInterval {
 }
 
 func (interval *TimeInterval) GetUTCTimeStart() int64 {
+	if interval.TimeZone != nil {
+		return wallClockToUTC(interval.TimeStart, interval.TimeZone)
+	}
+
 	return interval.TimeStart - interval.SecondsOffset
 }
 
 func (interval *TimeInterval) GetUTCTimeEnd() int64 {
+	if interval.TimeZone != nil {
+		return wallClockToUTC(interval.TimeEnd, interval.TimeZone)
+	}
+
 	return interval.TimeEnd - interval.SecondsOffset
 }
@@ -0,0 +1,117 @@
+package scheduler
+
+// Interval algebra works on half open intervals [TimeStart, TimeEnd).
+// Operands may carry different offsets or zones, comparisons are done in UTC
+// and results are expressed the way the receiver is.
+
+func (interval *TimeInterval) Duration() int64 {
+	return interval.GetUTCTimeEnd() - interval.GetUTCTimeStart()
+}
+
+func (interval *TimeInterval) IsEmpty() bool {
+	return interval.Duration() <= 0
+}
+
+// ToOffset returns the same moment in time expressed in the passed fixed offset.
+func (interval *TimeInterval) ToOffset(secondsOffset int64) TimeInterval {
+	return TimeInterval{
+		TimeStart:     interval.GetUTCTimeStart() + secondsOffset,
+		TimeEnd:       interval.GetUTCTimeEnd() + secondsOffset,
+		SecondsOffset: secondsOffset,
+	}
+}
+
+// Shift moves the interval by elapsed seconds.
+func (interval *TimeInterval) Shift(seconds int64) TimeInterval {
+	return interval.fromUTC(
+		interval.GetUTCTimeStart()+seconds,
+		interval.GetUTCTimeEnd()+seconds,
+	)
+}
+
+func (interval *TimeInterval) Overlaps(other *TimeInterval) bool {
+	return interval.GetUTCTimeStart() < other.GetUTCTimeEnd() &&
+		other.GetUTCTimeStart() < interval.GetUTCTimeEnd()
+}
+
+// Contains is inclusive at both ends so a zero length interval
+// can be used to check a timestamp.
+func (interval *TimeInterval) Contains(other *TimeInterval) bool {
+	return interval.GetUTCTimeStart() <= other.GetUTCTimeStart() &&
+		other.GetUTCTimeEnd() <= interval.GetUTCTimeEnd()
+}
+
+// Intersect returns false if the intervals do not overlap.
+func (interval *TimeInterval) Intersect(other *TimeInterval) (TimeInterval, bool) {
+	if !interval.Overlaps(other) {
+		return TimeInterval{},
+			false
+	}
+
+	return interval.fromUTC(
+			max(interval.GetUTCTimeStart(), other.GetUTCTimeStart()),
+			min(interval.GetUTCTimeEnd(), other.GetUTCTimeEnd()),
+		),
+		true
+}
+
+// Union returns false if the intervals neither overlap nor touch,
+// as the result would not be a single interval.
+func (interval *TimeInterval) Union(other *TimeInterval) (TimeInterval, bool) {
+	if other.GetUTCTimeStart() > interval.GetUTCTimeEnd() || interval.GetUTCTimeStart() > other.GetUTCTimeEnd() {
+		return TimeInterval{},
+			false
+	}
+
+	return interval.fromUTC(
+			min(interval.GetUTCTimeStart(), other.GetUTCTimeStart()),
+			max(interval.GetUTCTimeEnd(), other.GetUTCTimeEnd()),
+		),
+		true
+}
+
+// Subtract returns what is left of the receiver after removing other,
+// zero, one or two intervals in chronological order.
+func (interval *TimeInterval) Subtract(other *TimeInterval) []TimeInterval {
+	if !interval.Overlaps(other) {
+		if interval.IsEmpty() {
+			return nil
+		}
+
+		return []TimeInterval{*interval}
+	}
+
+	result := make([]TimeInterval, 0, 2)
+
+	if interval.GetUTCTimeStart() < other.GetUTCTimeStart() {
+		result = append(
+			result,
+			interval.fromUTC(interval.GetUTCTimeStart(), other.GetUTCTimeStart()),
+		)
+	}
+
+	if other.GetUTCTimeEnd() < interval.GetUTCTimeEnd() {
+		result = append(
+			result,
+			interval.fromUTC(other.GetUTCTimeEnd(), interval.GetUTCTimeEnd()),
+		)
+	}
+
+	return result
+}
+
+// Gap returns the interval between the two, false if they overlap or touch.
+func (interval *TimeInterval) Gap(other *TimeInterval) (TimeInterval, bool) {
+	if interval.GetUTCTimeEnd() < other.GetUTCTimeStart() {
+		return interval.fromUTC(interval.GetUTCTimeEnd(), other.GetUTCTimeStart()),
+			true
+	}
+
+	if other.GetUTCTimeEnd() < interval.GetUTCTimeStart() {
+		return interval.fromUTC(other.GetUTCTimeEnd(), interval.GetUTCTimeStart()),
+			true
+	}
+
+	return TimeInterval{},
+		false
+}
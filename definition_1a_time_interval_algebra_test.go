@@ -0,0 +1,143 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeIntervalAlgebra(t *testing.T) {
+	base := TimeInterval{
+		TimeStart: now,
+		TimeEnd:   now + 2*oneHour,
+	}
+
+	t.Run(
+		"1. Overlaps",
+		func(t *testing.T) {
+			require.True(t,
+				base.Overlaps(&TimeInterval{TimeStart: now + oneHour, TimeEnd: now + 3*oneHour}),
+			)
+			require.False(t,
+				base.Overlaps(&TimeInterval{TimeStart: now + 2*oneHour, TimeEnd: now + 3*oneHour}),
+				"touching intervals do not overlap",
+			)
+			require.True(t,
+				base.Overlaps(
+					&TimeInterval{
+						TimeStart:     now + 2*oneHour,
+						TimeEnd:       now + 3*oneHour,
+						SecondsOffset: oneHour, // UTC [now+oneHour, now+2*oneHour)
+					},
+				),
+			)
+		},
+	)
+
+	t.Run(
+		"2. Contains",
+		func(t *testing.T) {
+			require.True(t,
+				base.Contains(&TimeInterval{TimeStart: now + halfHour, TimeEnd: now + oneHour}),
+			)
+			require.True(t,
+				base.Contains(&TimeInterval{TimeStart: now + 2*oneHour, TimeEnd: now + 2*oneHour}),
+				"end timestamp is contained",
+			)
+			require.False(t,
+				base.Contains(&TimeInterval{TimeStart: now + oneHour, TimeEnd: now + 3*oneHour}),
+			)
+		},
+	)
+
+	t.Run(
+		"3. Intersect",
+		func(t *testing.T) {
+			intersection, overlaps := base.Intersect(
+				&TimeInterval{
+					TimeStart:     now + 2*oneHour,
+					TimeEnd:       now + 4*oneHour,
+					SecondsOffset: oneHour,
+				},
+			)
+			require.True(t, overlaps)
+			require.Equal(t,
+				TimeInterval{TimeStart: now + oneHour, TimeEnd: now + 2*oneHour},
+				intersection,
+			)
+
+			_, overlapsNot := base.Intersect(&TimeInterval{TimeStart: now + 3*oneHour, TimeEnd: now + 4*oneHour})
+			require.False(t, overlapsNot)
+		},
+	)
+
+	t.Run(
+		"4. Union",
+		func(t *testing.T) {
+			union, isSingle := base.Union(&TimeInterval{TimeStart: now + 2*oneHour, TimeEnd: now + 3*oneHour})
+			require.True(t, isSingle)
+			require.Equal(t,
+				TimeInterval{TimeStart: now, TimeEnd: now + 3*oneHour},
+				union,
+			)
+
+			_, isSingleNot := base.Union(&TimeInterval{TimeStart: now + 3*oneHour, TimeEnd: now + 4*oneHour})
+			require.False(t, isSingleNot)
+		},
+	)
+
+	t.Run(
+		"5. Subtract",
+		func(t *testing.T) {
+			require.Equal(t,
+				[]TimeInterval{
+					{TimeStart: now, TimeEnd: now + halfHour},
+					{TimeStart: now + oneHour, TimeEnd: now + 2*oneHour},
+				},
+				base.Subtract(&TimeInterval{TimeStart: now + halfHour, TimeEnd: now + oneHour}),
+			)
+			require.Empty(t,
+				base.Subtract(&TimeInterval{TimeStart: now - oneHour, TimeEnd: now + 3*oneHour}),
+			)
+			require.Equal(t,
+				[]TimeInterval{base},
+				base.Subtract(&TimeInterval{TimeStart: now + 3*oneHour, TimeEnd: now + 4*oneHour}),
+			)
+		},
+	)
+
+	t.Run(
+		"6. Gap",
+		func(t *testing.T) {
+			gap, hasGap := base.Gap(&TimeInterval{TimeStart: now + 3*oneHour, TimeEnd: now + 4*oneHour})
+			require.True(t, hasGap)
+			require.Equal(t,
+				TimeInterval{TimeStart: now + 2*oneHour, TimeEnd: now + 3*oneHour},
+				gap,
+			)
+
+			_, hasGapTouching := base.Gap(&TimeInterval{TimeStart: now + 2*oneHour, TimeEnd: now + 4*oneHour})
+			require.False(t, hasGapTouching)
+		},
+	)
+
+	t.Run(
+		"7. Shift and offset",
+		func(t *testing.T) {
+			require.Equal(t,
+				TimeInterval{TimeStart: now + oneHour, TimeEnd: now + 3*oneHour},
+				base.Shift(oneHour),
+			)
+
+			offseted := base.ToOffset(2 * oneHour)
+			require.Equal(t,
+				base.GetUTCTimeStart(),
+				offseted.GetUTCTimeStart(),
+			)
+			require.EqualValues(t,
+				now+2*oneHour,
+				offseted.TimeStart,
+			)
+		},
+	)
+}
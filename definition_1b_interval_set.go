@@ -0,0 +1,205 @@
+package scheduler
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// IntervalSet holds sorted, non overlapping and non touching intervals,
+// all expressed in the set offset.
+type IntervalSet struct {
+	intervals     []TimeInterval
+	SecondsOffset int64
+}
+
+// NewIntervalSet converts the passed intervals to the set offset,
+// drops the empty ones and merges what overlaps or touches.
+func NewIntervalSet(secondsOffset int64, intervals ...TimeInterval) *IntervalSet {
+	result := IntervalSet{
+		intervals:     make([]TimeInterval, 0, len(intervals)),
+		SecondsOffset: secondsOffset,
+	}
+
+	for _, interval := range intervals {
+		if interval.IsEmpty() {
+			continue
+		}
+
+		result.intervals = append(
+			result.intervals,
+			interval.ToOffset(secondsOffset),
+		)
+	}
+
+	result.normalize()
+
+	return &result
+}
+
+func (set *IntervalSet) normalize() {
+	if len(set.intervals) < 2 {
+		return
+	}
+
+	slices.SortFunc(
+		set.intervals,
+		func(a, b TimeInterval) int {
+			if a.TimeStart != b.TimeStart {
+				return ternary(a.TimeStart < b.TimeStart, -1, 1)
+			}
+
+			return ternary(a.TimeEnd < b.TimeEnd, -1, ternary(a.TimeEnd > b.TimeEnd, 1, 0))
+		},
+	)
+
+	merged := set.intervals[:1]
+
+	for _, interval := range set.intervals[1:] {
+		last := &merged[len(merged)-1]
+
+		if union, canMerge := last.Union(&interval); canMerge {
+			*last = union
+
+			continue
+		}
+
+		merged = append(merged, interval)
+	}
+
+	set.intervals = merged
+}
+
+// Intervals returns a copy of the set intervals, in chronological order.
+func (set *IntervalSet) Intervals() []TimeInterval {
+	return slices.Clone(set.intervals)
+}
+
+// IntervalsAs returns the set intervals expressed the way reference is,
+// in its zone if set, otherwise in its fixed offset.
+func (set *IntervalSet) IntervalsAs(reference *TimeInterval) []TimeInterval {
+	result := make([]TimeInterval, len(set.intervals))
+
+	for ix, interval := range set.intervals {
+		result[ix] = reference.fromUTC(interval.GetUTCTimeStart(), interval.GetUTCTimeEnd())
+	}
+
+	return result
+}
+
+func (set *IntervalSet) Len() int {
+	return len(set.intervals)
+}
+
+func (set *IntervalSet) IsEmpty() bool {
+	return len(set.intervals) == 0
+}
+
+func (set *IntervalSet) Add(intervals ...TimeInterval) {
+	for _, interval := range intervals {
+		if interval.IsEmpty() {
+			continue
+		}
+
+		set.intervals = append(
+			set.intervals,
+			interval.ToOffset(set.SecondsOffset),
+		)
+	}
+
+	set.normalize()
+}
+
+func (set *IntervalSet) Overlaps(interval *TimeInterval) bool {
+	for _, member := range set.intervals {
+		if member.Overlaps(interval) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Contains returns true if the interval is fully covered by one member of the set.
+func (set *IntervalSet) Contains(interval *TimeInterval) bool {
+	for _, member := range set.intervals {
+		if member.Contains(interval) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (set *IntervalSet) Union(other *IntervalSet) *IntervalSet {
+	return NewIntervalSet(
+		set.SecondsOffset,
+
+		append(set.Intervals(), other.intervals...)...,
+	)
+}
+
+func (set *IntervalSet) Intersect(other *IntervalSet) *IntervalSet {
+	result := NewIntervalSet(set.SecondsOffset)
+
+	for _, member := range set.intervals {
+		for _, otherMember := range other.intervals {
+			if intersection, overlaps := member.Intersect(&otherMember); overlaps {
+				result.intervals = append(result.intervals, intersection)
+			}
+		}
+	}
+
+	result.normalize()
+
+	return result
+}
+
+// Subtract returns the parts of the set not covered by other.
+func (set *IntervalSet) Subtract(other *IntervalSet) *IntervalSet {
+	remaining := set.Intervals()
+
+	for _, otherMember := range other.intervals {
+		next := make([]TimeInterval, 0, len(remaining))
+
+		for _, member := range remaining {
+			next = append(next, member.Subtract(&otherMember)...)
+		}
+
+		remaining = next
+	}
+
+	return NewIntervalSet(set.SecondsOffset, remaining...)
+}
+
+// Complement returns the parts of within not covered by the set,
+// expressed in the offset of within.
+func (set *IntervalSet) Complement(within *TimeInterval) *IntervalSet {
+	return NewIntervalSet(within.SecondsOffset, *within).
+		Subtract(set)
+}
+
+func (set *IntervalSet) String() string {
+	var sb strings.Builder
+
+	sb.WriteString("IntervalSet{")
+
+	for ix, interval := range set.intervals {
+		sb.WriteString(
+			fmt.Sprintf(
+				"[%d-%d]",
+
+				interval.TimeStart,
+				interval.TimeEnd,
+			),
+		)
+
+		if ix < len(set.intervals)-1 {
+			sb.WriteString(", ")
+		}
+	}
+
+	sb.WriteString(fmt.Sprintf("} Offset %.1fh", float64(set.SecondsOffset)/3600))
+
+	return sb.String()
+}
@@ -0,0 +1,125 @@
+package scheduler
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntervalSet(t *testing.T) {
+	set := NewIntervalSet(
+		0,
+
+		TimeInterval{TimeStart: now + 2*oneHour, TimeEnd: now + 3*oneHour},
+		TimeInterval{TimeStart: now, TimeEnd: now + oneHour},
+		TimeInterval{TimeStart: now + halfHour, TimeEnd: now + oneHour + halfHour},
+		TimeInterval{TimeStart: now + 3*oneHour, TimeEnd: now + 4*oneHour}, // touching, merged
+		TimeInterval{TimeStart: now + 5*oneHour, TimeEnd: now + 5*oneHour}, // empty, dropped
+	)
+
+	require.Equal(t,
+		[]TimeInterval{
+			{TimeStart: now, TimeEnd: now + oneHour + halfHour},
+			{TimeStart: now + 2*oneHour, TimeEnd: now + 4*oneHour},
+		},
+		set.Intervals(),
+	)
+
+	fmt.Println(
+		set,
+	)
+
+	t.Run(
+		"1. Complement",
+		func(t *testing.T) {
+			require.Equal(t,
+				[]TimeInterval{
+					{TimeStart: now - oneHour, TimeEnd: now},
+					{TimeStart: now + oneHour + halfHour, TimeEnd: now + 2*oneHour},
+					{TimeStart: now + 4*oneHour, TimeEnd: now + 5*oneHour},
+				},
+				set.Complement(
+					&TimeInterval{
+						TimeStart: now - oneHour,
+						TimeEnd:   now + 5*oneHour,
+					},
+				).Intervals(),
+			)
+		},
+	)
+
+	t.Run(
+		"2. Complement in other offset",
+		func(t *testing.T) {
+			require.Equal(t,
+				[]TimeInterval{
+					{
+						TimeStart:     now + oneHour + halfHour + oneHour,
+						TimeEnd:       now + 2*oneHour + oneHour,
+						SecondsOffset: oneHour,
+					},
+				},
+				set.Complement(
+					&TimeInterval{
+						TimeStart:     now + 2*oneHour,
+						TimeEnd:       now + 4*oneHour,
+						SecondsOffset: oneHour,
+					},
+				).Intervals(),
+			)
+		},
+	)
+
+	t.Run(
+		"3. Intersect, Subtract, Union",
+		func(t *testing.T) {
+			other := NewIntervalSet(
+				0,
+
+				TimeInterval{TimeStart: now + oneHour, TimeEnd: now + 2*oneHour + halfHour},
+			)
+
+			require.Equal(t,
+				[]TimeInterval{
+					{TimeStart: now + oneHour, TimeEnd: now + oneHour + halfHour},
+					{TimeStart: now + 2*oneHour, TimeEnd: now + 2*oneHour + halfHour},
+				},
+				set.Intersect(other).Intervals(),
+			)
+
+			require.Equal(t,
+				[]TimeInterval{
+					{TimeStart: now, TimeEnd: now + oneHour},
+					{TimeStart: now + 2*oneHour + halfHour, TimeEnd: now + 4*oneHour},
+				},
+				set.Subtract(other).Intervals(),
+			)
+
+			require.Equal(t,
+				[]TimeInterval{
+					{TimeStart: now, TimeEnd: now + 4*oneHour},
+				},
+				set.Union(other).Intervals(),
+			)
+		},
+	)
+
+	t.Run(
+		"4. Overlaps and Contains",
+		func(t *testing.T) {
+			require.True(t,
+				set.Overlaps(&TimeInterval{TimeStart: now + oneHour, TimeEnd: now + 2*oneHour}),
+			)
+			require.False(t,
+				set.Overlaps(&TimeInterval{TimeStart: now + oneHour + halfHour, TimeEnd: now + 2*oneHour}),
+			)
+			require.True(t,
+				set.Contains(&TimeInterval{TimeStart: now + 2*oneHour, TimeEnd: now + 4*oneHour}),
+			)
+			require.False(t,
+				set.Contains(&TimeInterval{TimeStart: now + oneHour, TimeEnd: now + 2*oneHour + halfHour}),
+			)
+		},
+	)
+}
@@ -0,0 +1,117 @@
+package scheduler
+
+import "time"
+
+// wallClockToUTC interprets the passed seconds as wall clock in zone.
+// Wall clock times skipped or repeated by a DST change are resolved as time.Date does.
+func wallClockToUTC(wallClock int64, zone *time.Location) int64 {
+	wall := time.Unix(wallClock, 0).UTC()
+
+	return time.Date(
+		wall.Year(),
+		wall.Month(),
+		wall.Day(),
+		wall.Hour(),
+		wall.Minute(),
+		wall.Second(),
+		0,
+		zone,
+	).Unix()
+}
+
+func utcToWallClock(utc int64, zone *time.Location) int64 {
+	return utc + secondsOffsetIn(zone, utc)
+}
+
+func secondsOffsetIn(zone *time.Location, utc int64) int64 {
+	_, offset := time.Unix(utc, 0).In(zone).Zone()
+
+	return int64(offset)
+}
+
+// getZoneName returns the name of the zone, empty for none.
+func getZoneName(zone *time.Location) string {
+	if zone == nil {
+		return ""
+	}
+
+	return zone.String()
+}
+
+// isSame returns true for the same local times in the same frame.
+// Zones compare by name, each load of a zone is a distinct pointer.
+func (interval *TimeInterval) isSame(other *TimeInterval) bool {
+	return interval.TimeStart == other.TimeStart &&
+		interval.TimeEnd == other.TimeEnd &&
+		interval.SecondsOffset == other.SecondsOffset &&
+		getZoneName(interval.TimeZone) == getZoneName(other.TimeZone)
+}
+
+// NewTimeIntervalIn returns the interval as wall clock of the passed zone.
+func NewTimeIntervalIn(zone *time.Location, start, end time.Time) TimeInterval {
+	reference := TimeInterval{
+		TimeZone: zone,
+	}
+
+	return reference.fromUTC(start.Unix(), end.Unix())
+}
+
+// GetSecondsOffsetStart returns the offset in effect at TimeStart.
+func (interval *TimeInterval) GetSecondsOffsetStart() int64 {
+	if interval.TimeZone != nil {
+		return secondsOffsetIn(interval.TimeZone, interval.GetUTCTimeStart())
+	}
+
+	return interval.SecondsOffset
+}
+
+// GetSecondsOffsetEnd returns the offset in effect at TimeEnd.
+func (interval *TimeInterval) GetSecondsOffsetEnd() int64 {
+	if interval.TimeZone != nil {
+		return secondsOffsetIn(interval.TimeZone, interval.GetUTCTimeEnd())
+	}
+
+	return interval.SecondsOffset
+}
+
+// InTimeZone returns the same moment in time as wall clock of the passed zone.
+func (interval *TimeInterval) InTimeZone(zone *time.Location) TimeInterval {
+	reference := TimeInterval{
+		TimeZone: zone,
+	}
+
+	return reference.fromUTC(interval.GetUTCTimeStart(), interval.GetUTCTimeEnd())
+}
+
+// fromUTC expresses the passed UTC moments the way the receiver is expressed,
+// in its zone if set, otherwise in its fixed offset.
+func (interval *TimeInterval) fromUTC(utcStart, utcEnd int64) TimeInterval {
+	if interval.TimeZone != nil {
+		return TimeInterval{
+			TimeStart:     utcToWallClock(utcStart, interval.TimeZone),
+			TimeEnd:       utcToWallClock(utcEnd, interval.TimeZone),
+			SecondsOffset: secondsOffsetIn(interval.TimeZone, utcStart),
+			TimeZone:      interval.TimeZone,
+		}
+	}
+
+	return TimeInterval{
+		TimeStart:     utcStart + interval.SecondsOffset,
+		TimeEnd:       utcEnd + interval.SecondsOffset,
+		SecondsOffset: interval.SecondsOffset,
+	}
+}
+
+// fromUTCTimestamp is fromUTC for a single moment.
+func (interval *TimeInterval) fromUTCTimestamp(utc int64) int64 {
+	return interval.fromUTC(utc, utc).TimeStart
+}
+
+// toUTCTimestamp converts a moment expressed the way the receiver is to UTC.
+func (interval *TimeInterval) toUTCTimestamp(timestamp int64) int64 {
+	if interval.TimeZone != nil {
+		return wallClockToUTC(timestamp, interval.TimeZone)
+	}
+
+	return timestamp - interval.SecondsOffset
+}
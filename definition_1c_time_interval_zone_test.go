@@ -0,0 +1,191 @@
+package scheduler
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeIntervalZone(t *testing.T) {
+	bucharest, errLoad := time.LoadLocation("Europe/Bucharest")
+	require.NoError(t, errLoad)
+
+	// 2026-03-29 03:00 local clocks jump to 04:00, UTC+2 becomes UTC+3.
+	crossingDST := NewTimeIntervalIn(
+		bucharest,
+
+		time.Date(2026, time.March, 29, 2, 0, 0, 0, bucharest),
+		time.Date(2026, time.March, 29, 5, 0, 0, 0, bucharest),
+	)
+
+	t.Run(
+		"1. Interval crossing DST keeps real duration",
+		func(t *testing.T) {
+			require.EqualValues(t,
+				3*oneHour,
+				crossingDST.TimeEnd-crossingDST.TimeStart,
+				"wall clock duration",
+			)
+			require.EqualValues(t,
+				2*oneHour,
+				crossingDST.Duration(),
+				"elapsed duration",
+			)
+			require.EqualValues(t,
+				2*oneHour,
+				crossingDST.GetSecondsOffsetStart(),
+			)
+			require.EqualValues(t,
+				3*oneHour,
+				crossingDST.GetSecondsOffsetEnd(),
+			)
+		},
+	)
+
+	t.Run(
+		"2. Availability against zone based booking",
+		func(t *testing.T) {
+			res, errCr := NewResource(
+				&ParamsNewResource{
+					Name:            "Resource",
+					ResourceType:    1,
+					CostPerLoadUnit: map[uint8]Money{1: {Amount: 1}},
+				},
+			)
+			require.NoError(t, errCr)
+
+			_, errAdd := res.AddRun(
+				t.Context(),
+				&ParamsRun{
+					TimeInterval: crossingDST,
+					ID:           1,
+				},
+			)
+			require.NoError(t, errAdd)
+
+			fmt.Println(
+				res.GetSchedule(),
+			)
+
+			// fixed UTC+3 search covering 04:00-06:00 local summer time.
+			searchSummerTime := TimeInterval{
+				TimeStart:     crossingDST.TimeEnd - oneHour,
+				TimeEnd:       crossingDST.TimeEnd + oneHour,
+				SecondsOffset: 3 * oneHour,
+			}
+
+			available, isAvailable := res.GetAvailability(&searchSummerTime)
+			require.False(t, isAvailable)
+			require.Equal(t,
+				[]TimeInterval{
+					{
+						TimeStart:     crossingDST.TimeEnd,
+						TimeEnd:       crossingDST.TimeEnd + oneHour,
+						SecondsOffset: 3 * oneHour,
+					},
+				},
+				available,
+			)
+
+			// same search read with a fixed UTC+2 offset would wrongly end the booking one hour later.
+			response, errGet := res.GetRun(crossingDST.TimeEnd-halfHour, 3*oneHour)
+			require.NoError(t, errGet)
+			require.EqualValues(t,
+				crossingDST.TimeEnd,
+				response.AlreadyScheduledTaskEndTime,
+			)
+		},
+	)
+
+	t.Run(
+		"3. Task and location in zones with different DST dates",
+		func(t *testing.T) {
+			newYork, errLoadNY := time.LoadLocation("America/New_York")
+			require.NoError(t, errLoadNY)
+
+			res, errCr := NewResource(
+				&ParamsNewResource{
+					Name:            "Resource",
+					ResourceType:    1,
+					CostPerLoadUnit: map[uint8]Money{1: {Amount: 1}},
+				},
+			)
+			require.NoError(t, errCr)
+
+			location, errCrLocation := NewLocation(
+				&ParamsNewLocation{
+					ID:       1,
+					Name:     "New York",
+					TimeZone: newYork,
+
+					Resources: []*ResourceScheduled{res},
+				},
+			)
+			require.NoError(t, errCrLocation)
+
+			// 2026-03-16, New York already on UTC-4, Bucharest still on UTC+2.
+			requested := NewTimeIntervalIn(
+				bucharest,
+
+				time.Date(2026, time.March, 16, 16, 0, 0, 0, bucharest),
+				time.Date(2026, time.March, 16, 17, 0, 0, 0, bucharest),
+			)
+
+			response, errSchedule := location.CanSchedule(
+				&ParamsCanRun{
+					TimeInterval: requested,
+
+					TaskRun: newTestRun(1, oneHour, 1),
+				},
+			)
+			require.NoError(t, errSchedule)
+			require.True(t, response.WasScheduled)
+
+			fmt.Println(
+				res.GetSchedule(),
+			)
+
+			// booked at 10:00 New York wall clock, six hours behind Bucharest.
+			wallNewYork := time.Date(2026, time.March, 16, 10, 0, 0, 0, time.UTC).Unix()
+
+			run, errGet := res.GetRun(wallNewYork, -4*oneHour)
+			require.NoError(t, errGet)
+			require.EqualValues(t,
+				1,
+				run.ID,
+			)
+
+			_, errGetBefore := res.GetRun(wallNewYork-halfHour, -4*oneHour)
+			require.Error(t, errGetBefore)
+		},
+	)
+
+	t.Run(
+		"4. Booking matched with the zone loaded again",
+		func(t *testing.T) {
+			res := newTestResource(1, 1, 1, nil)
+
+			_, errAdd := res.AddRun(
+				t.Context(),
+				&ParamsRun{
+					TimeInterval: crossingDST,
+					ID:           1,
+				},
+			)
+			require.NoError(t, errAdd)
+
+			bucharestAgain, errLoadAgain := time.LoadLocation("Europe/Bucharest")
+			require.NoError(t, errLoadAgain)
+
+			same := crossingDST
+			same.TimeZone = bucharestAgain
+
+			require.NotSame(t, bucharest, bucharestAgain)
+			require.False(t,
+				res.IsAvailableIn(&same),
+			)
+		},
+	)
+}
@@ -0,0 +1,166 @@
+package scheduler
+
+import (
+	"errors"
+	"time"
+
+	goerrors "github.com/TudorHulban/go-errors"
+)
+
+// DateLayout is the layout of the Calendar exception keys.
+const DateLayout = "2006-01-02"
+
+const _SecondsPerDay = int64(86400)
+
+// DailyWindow is a part of the day, in seconds from local midnight.
+// To can be 86400 for end of day.
+type DailyWindow struct {
+	From int64
+	To   int64
+}
+
+func (w DailyWindow) isValid() bool {
+	return w.From >= 0 && w.From < w.To && w.To <= _SecondsPerDay
+}
+
+// Calendar holds open time in local wall clock.
+// A day is open as per Weekly unless there is an exception for its date,
+// an exception without windows is a holiday. Closures are removed at the end.
+type Calendar struct {
+	Weekly     map[time.Weekday][]DailyWindow
+	Exceptions map[string][]DailyWindow // date as DateLayout
+	Closures   []TimeInterval
+}
+
+func NewCalendar() *Calendar {
+	return &Calendar{
+		Weekly:     make(map[time.Weekday][]DailyWindow),
+		Exceptions: make(map[string][]DailyWindow),
+	}
+}
+
+func (c *Calendar) SetWeekly(weekday time.Weekday, windows ...DailyWindow) error {
+	for _, window := range windows {
+		if !window.isValid() {
+			return goerrors.ErrInvalidInput{
+				Caller:     "SetWeekly",
+				InputName:  "DailyWindow",
+				InputValue: window,
+			}
+		}
+	}
+
+	c.Weekly[weekday] = windows
+
+	return nil
+}
+
+// SetException replaces the weekly windows for the date, no windows means closed.
+func (c *Calendar) SetException(date string, windows ...DailyWindow) error {
+	if _, errParse := time.Parse(DateLayout, date); errParse != nil {
+		return goerrors.ErrInvalidInput{
+			Caller:     "SetException",
+			InputName:  "date",
+			InputValue: date,
+			Issue:      errParse,
+		}
+	}
+
+	for _, window := range windows {
+		if !window.isValid() {
+			return goerrors.ErrInvalidInput{
+				Caller:     "SetException",
+				InputName:  "DailyWindow",
+				InputValue: window,
+			}
+		}
+	}
+
+	c.Exceptions[date] = windows
+
+	return nil
+}
+
+func (c *Calendar) AddHoliday(date string) error {
+	return c.SetException(date)
+}
+
+func (c *Calendar) AddClosure(closure TimeInterval) error {
+	if closure.IsEmpty() {
+		return goerrors.ErrInvalidInput{
+			Caller:     "AddClosure",
+			InputName:  "closure",
+			InputValue: closure,
+			Issue:      errors.New("empty interval"),
+		}
+	}
+
+	c.Closures = append(c.Closures, closure)
+
+	return nil
+}
+
+// GetOpenIntervals returns the open time within the passed interval.
+// Wall clock days are those of reference, its zone if set, otherwise its offset.
+// A nil calendar is always open.
+func (c *Calendar) GetOpenIntervals(reference *TimeInterval, within *TimeInterval) *IntervalSet {
+	if c == nil {
+		return NewIntervalSet(within.SecondsOffset, *within)
+	}
+
+	toUTC := func(wallClock int64) int64 {
+		if reference.TimeZone != nil {
+			return wallClockToUTC(wallClock, reference.TimeZone)
+		}
+
+		return wallClock - reference.SecondsOffset
+	}
+
+	wallStart := reference.fromUTCTimestamp(within.GetUTCTimeStart())
+	wallEnd := reference.fromUTCTimestamp(within.GetUTCTimeEnd())
+
+	openIntervals := make([]TimeInterval, 0)
+
+	for day := floorDay(wallStart); day < wallEnd; day = day + _SecondsPerDay {
+		date := time.Unix(day, 0).UTC()
+
+		windows, isException := c.Exceptions[date.Format(DateLayout)]
+		if !isException {
+			windows = c.Weekly[date.Weekday()]
+		}
+
+		for _, window := range windows {
+			openIntervals = append(
+				openIntervals,
+				TimeInterval{
+					TimeStart: toUTC(day + window.From),
+					TimeEnd:   toUTC(day + window.To),
+				},
+			)
+		}
+	}
+
+	// built once, adding day by day sorts the set again for every window
+	return NewIntervalSet(within.SecondsOffset, *within).
+		Intersect(NewIntervalSet(0, openIntervals...)).
+		Subtract(NewIntervalSet(within.SecondsOffset, c.Closures...))
+}
+
+// IsOpen returns true if the interval is entirely in open time.
+func (c *Calendar) IsOpen(reference *TimeInterval, interval *TimeInterval) bool {
+	if c == nil {
+		return true
+	}
+
+	return c.GetOpenIntervals(reference, interval).Contains(interval)
+}
+
+func floorDay(wallClock int64) int64 {
+	day := wallClock - wallClock%_SecondsPerDay
+
+	if wallClock < 0 && wallClock%_SecondsPerDay != 0 {
+		day = day - _SecondsPerDay
+	}
+
+	return day
+}
@@ -0,0 +1,268 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCalendarWorkingDays(t *testing.T) *Calendar {
+	calendar := NewCalendar()
+
+	for _, weekday := range []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday} {
+		require.NoError(t,
+			calendar.SetWeekly(
+				weekday,
+
+				DailyWindow{
+					From: 9 * oneHour,
+					To:   17 * oneHour,
+				},
+			),
+		)
+	}
+
+	require.NoError(t,
+		calendar.AddHoliday("2026-03-03"),
+	)
+
+	return calendar
+}
+
+func TestCalendar(t *testing.T) {
+	calendar := newTestCalendarWorkingDays(t)
+
+	monday := time.Date(2026, time.March, 2, 0, 0, 0, 0, time.UTC).Unix()
+
+	t.Run(
+		"1. Open intervals, holiday and closure",
+		func(t *testing.T) {
+			require.NoError(t,
+				calendar.AddClosure(
+					TimeInterval{
+						TimeStart: monday + 12*oneHour,
+						TimeEnd:   monday + 13*oneHour,
+					},
+				),
+			)
+			defer func() {
+				calendar.Closures = nil
+			}()
+
+			open := calendar.GetOpenIntervals(
+				&TimeInterval{},
+				&TimeInterval{
+					TimeStart: monday,
+					TimeEnd:   monday + 3*oneDay,
+				},
+			)
+
+			require.Equal(t,
+				[]TimeInterval{
+					{TimeStart: monday + 9*oneHour, TimeEnd: monday + 12*oneHour},
+					{TimeStart: monday + 13*oneHour, TimeEnd: monday + 17*oneHour},
+					{TimeStart: monday + 2*oneDay + 9*oneHour, TimeEnd: monday + 2*oneDay + 17*oneHour},
+				},
+				open.Intervals(),
+			)
+		},
+	)
+
+	t.Run(
+		"2. Wall clock of the reference offset",
+		func(t *testing.T) {
+			// opening hours in UTC+2, 09:00 local is 07:00 UTC.
+			require.True(t,
+				calendar.IsOpen(
+					&TimeInterval{SecondsOffset: 2 * oneHour},
+					&TimeInterval{
+						TimeStart: monday + 7*oneHour,
+						TimeEnd:   monday + 8*oneHour,
+					},
+				),
+			)
+			require.False(t,
+				calendar.IsOpen(
+					&TimeInterval{SecondsOffset: 2 * oneHour},
+					&TimeInterval{
+						TimeStart: monday + 14*oneHour + halfHour,
+						TimeEnd:   monday + 15*oneHour + halfHour,
+					},
+				),
+			)
+		},
+	)
+
+	t.Run(
+		"3. Nil calendar is always open",
+		func(t *testing.T) {
+			var calendarNil *Calendar
+
+			require.True(t,
+				calendarNil.IsOpen(
+					&TimeInterval{},
+					&TimeInterval{
+						TimeStart: monday,
+						TimeEnd:   monday + oneHour,
+					},
+				),
+			)
+		},
+	)
+
+	t.Run(
+		"4. Invalid input",
+		func(t *testing.T) {
+			require.Error(t,
+				calendar.SetWeekly(time.Saturday, DailyWindow{From: 10 * oneHour, To: 9 * oneHour}),
+			)
+			require.Error(t,
+				calendar.AddHoliday("03/03/2026"),
+			)
+		},
+	)
+}
+
+func TestLocationCalendar(t *testing.T) {
+	monday := time.Date(2026, time.March, 2, 0, 0, 0, 0, time.UTC).Unix()
+
+	taskRun := Run{
+		ID:                1,
+		EstimatedDuration: oneHour,
+
+		Dependencies: []RunDependency{
+			{
+				ResourceType:     1,
+				ResourceQuantity: 1,
+			},
+		},
+
+		RunLoad: RunLoad{
+			Load:     1,
+			LoadUnit: 1,
+		},
+	}
+
+	location, errCr := NewLocation(
+		&ParamsNewLocation{
+			ID:       1,
+			Name:     t.Name(),
+			Calendar: newTestCalendarWorkingDays(t),
+
+			Resources: []*ResourceScheduled{
+				newTestResource(1, 1, 2, nil),
+			},
+		},
+	)
+	require.NoError(t, errCr)
+
+	t.Run(
+		"1. Options only in opening hours",
+		func(t *testing.T) {
+			options, errGet := location.GetSchedulingOptions(
+				&ParamsCanRun{
+					TimeInterval: TimeInterval{
+						TimeStart: monday + 6*oneHour,
+						TimeEnd:   monday + 12*oneHour,
+					},
+
+					TaskRun: &taskRun,
+				},
+			)
+			require.NoError(t, errGet)
+			require.Len(t,
+				options,
+				3,
+			)
+			require.EqualValues(t,
+				monday+9*oneHour,
+				options[0].WhenCanStart,
+			)
+		},
+	)
+
+	t.Run(
+		"2. Closed at requested start",
+		func(t *testing.T) {
+			response, errSchedule := location.CanSchedule(
+				&ParamsCanRun{
+					TimeInterval: TimeInterval{
+						TimeStart: monday + 8*oneHour + halfHour,
+						TimeEnd:   monday + 10*oneHour,
+					},
+
+					TaskRun: &taskRun,
+				},
+			)
+			require.NoError(t, errSchedule)
+			require.False(t, response.WasScheduled)
+			require.EqualValues(t,
+				monday+9*oneHour,
+				response.WhenCanStart,
+			)
+		},
+	)
+
+	t.Run(
+		"3. Holiday",
+		func(t *testing.T) {
+			options, errGet := location.GetSchedulingOptions(
+				&ParamsCanRun{
+					TimeInterval: TimeInterval{
+						TimeStart: monday + oneDay,
+						TimeEnd:   monday + 2*oneDay,
+					},
+
+					TaskRun: &taskRun,
+				},
+			)
+			require.NoError(t, errGet)
+			require.Empty(t, options)
+		},
+	)
+
+	t.Run(
+		"4. Loco options only in opening hours",
+		func(t *testing.T) {
+			loco := Loco{
+				ID:       1,
+				Name:     t.Name(),
+				Calendar: newTestCalendarWorkingDays(t),
+
+				Resources: ResourcesPerType{
+					1: []*ResourceScheduled{
+						newTestResource(1, 1, 2, nil),
+					},
+				},
+			}
+
+			paramsCanRun := ParamsCanRun{
+				TimeInterval: TimeInterval{
+					TimeStart: monday + 6*oneHour,
+					TimeEnd:   monday + 12*oneHour,
+				},
+
+				TaskRun: &taskRun,
+			}
+
+			options, errGet := loco.GetSchedulingOptions(&paramsCanRun)
+			require.NoError(t, errGet)
+			require.Len(t,
+				options,
+				3,
+			)
+			require.EqualValues(t,
+				monday+9*oneHour,
+				options[0].WhenCanStart,
+			)
+
+			optionsAll, errGetAll := loco.GetAllSchedulingOptions(&paramsCanRun)
+			require.NoError(t, errGetAll)
+			require.Len(t,
+				optionsAll,
+				3,
+			)
+		},
+	)
+}
@@ -0,0 +1,181 @@
+package scheduler
+
+import (
+	"errors"
+	"math/big"
+	"slices"
+	"time"
+
+	goerrors "github.com/TudorHulban/go-errors"
+)
+
+// SeasonLayout is the layout of the Season dates, month and day of every year.
+const SeasonLayout = "01-02"
+
+// PriceWindow weights the cost of a part of the day.
+type PriceWindow struct {
+	DailyWindow
+
+	Factor float32
+}
+
+// Season weights the cost of the days from From to To, both included, every year.
+// From after To wraps over the new year.
+type Season struct {
+	From string // as SeasonLayout
+	To   string
+
+	Factor float32
+}
+
+func (s Season) contains(date time.Time) bool {
+	monthDay := date.Format(SeasonLayout)
+
+	if s.From <= s.To {
+		return s.From <= monthDay && monthDay <= s.To
+	}
+
+	return monthDay >= s.From || monthDay <= s.To
+}
+
+// PricingCalendar weights costs by time in local wall clock, a factor of one where nothing applies.
+// The factor of a moment is that of its weekly window times those of its seasons.
+type PricingCalendar struct {
+	Weekly  map[time.Weekday][]PriceWindow
+	Seasons []Season
+}
+
+func NewPricingCalendar() *PricingCalendar {
+	return &PricingCalendar{
+		Weekly: make(map[time.Weekday][]PriceWindow),
+	}
+}
+
+// SetWeekly replaces the windows of the weekday, they should not overlap.
+func (c *PricingCalendar) SetWeekly(weekday time.Weekday, windows ...PriceWindow) error {
+	for ix, window := range windows {
+		if !window.isValid() || window.Factor < 0 {
+			return goerrors.ErrInvalidInput{
+				Caller:     "SetWeekly",
+				InputName:  "PriceWindow",
+				InputValue: window,
+			}
+		}
+
+		for _, other := range windows[:ix] {
+			if window.From < other.To && other.From < window.To {
+				return goerrors.ErrInvalidInput{
+					Caller:     "SetWeekly",
+					InputName:  "PriceWindow",
+					InputValue: window,
+					Issue:      errors.New("overlapping windows"),
+				}
+			}
+		}
+	}
+
+	c.Weekly[weekday] = windows
+
+	return nil
+}
+
+func (c *PricingCalendar) AddSeason(season Season) error {
+	for _, date := range []string{season.From, season.To} {
+		if _, errParse := time.Parse(SeasonLayout, date); errParse != nil {
+			return goerrors.ErrInvalidInput{
+				Caller:     "AddSeason",
+				InputName:  "date",
+				InputValue: date,
+				Issue:      errParse,
+			}
+		}
+	}
+
+	if season.Factor < 0 {
+		return goerrors.ErrValidation{
+			Caller: "AddSeason",
+			Issue: goerrors.ErrNegativeInput{
+				InputName: "Factor",
+			},
+		}
+	}
+
+	c.Seasons = append(c.Seasons, season)
+
+	return nil
+}
+
+// GetFactor returns the exact factor over the interval, its seconds weighted by their factor
+// over its seconds, pro rata where it crosses windows. Factors are taken as the decimals they are written in.
+// Wall clock days are those of reference, its zone if set, otherwise its offset.
+// A nil calendar or an empty interval has a factor of one.
+func (c *PricingCalendar) GetFactor(reference *TimeInterval, interval *TimeInterval) *big.Rat {
+	utcStart, utcEnd := interval.GetUTCTimeStart(), interval.GetUTCTimeEnd()
+
+	if c == nil || utcEnd <= utcStart {
+		return big.NewRat(1, 1)
+	}
+
+	toUTC := func(wallClock int64) int64 {
+		if reference.TimeZone != nil {
+			return wallClockToUTC(wallClock, reference.TimeZone)
+		}
+
+		return wallClock - reference.SecondsOffset
+	}
+
+	wallStart := reference.fromUTCTimestamp(utcStart)
+	wallEnd := reference.fromUTCTimestamp(utcEnd)
+
+	one := big.NewRat(1, 1)
+	weighted := new(big.Rat)
+
+	for day := floorDay(wallStart); day < wallEnd; day = day + _SecondsPerDay {
+		date := time.Unix(day, 0).UTC()
+
+		factorSeason := big.NewRat(1, 1)
+
+		for _, season := range c.Seasons {
+			if season.contains(date) {
+				factorSeason.Mul(factorSeason, decimalOf(season.Factor))
+			}
+		}
+
+		// windows and the time between them, a factor of one
+		windows := slices.Clone(c.Weekly[date.Weekday()])
+
+		slices.SortFunc(
+			windows,
+			func(a, b PriceWindow) int {
+				return int(a.From - b.From)
+			},
+		)
+
+		var from int64
+
+		addPart := func(to int64, factor *big.Rat) {
+			partStart := max(toUTC(day+from), utcStart)
+			partEnd := min(toUTC(day+to), utcEnd)
+
+			if partEnd > partStart {
+				part := new(big.Rat).Mul(factor, factorSeason)
+
+				weighted.Add(
+					weighted,
+					part.Mul(part, big.NewRat(partEnd-partStart, 1)),
+				)
+			}
+
+			from = to
+		}
+
+		for _, window := range windows {
+			addPart(window.From, one)
+			addPart(window.To, decimalOf(window.Factor))
+		}
+
+		addPart(_SecondsPerDay, one)
+	}
+
+	return weighted.Quo(weighted, big.NewRat(utcEnd-utcStart, 1))
+}
@@ -0,0 +1,263 @@
+package scheduler
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPricingCalendar(t *testing.T) {
+	hour := func(date time.Time, hour int) int64 {
+		return date.Unix() + int64(hour)*oneHour
+	}
+
+	wednesday := time.Date(2025, time.March, 5, 0, 0, 0, 0, time.UTC)
+	saturday := time.Date(2025, time.March, 8, 0, 0, 0, 0, time.UTC)
+	wednesdaySummer := time.Date(2025, time.July, 2, 0, 0, 0, 0, time.UTC)
+	wednesdayWinter := time.Date(2025, time.January, 8, 0, 0, 0, 0, time.UTC)
+
+	newPricing := func(t *testing.T) *PricingCalendar {
+		result := NewPricingCalendar()
+
+		require.NoError(t,
+			result.SetWeekly(
+				time.Wednesday,
+				PriceWindow{
+					DailyWindow: DailyWindow{From: 8 * oneHour, To: 20 * oneHour},
+					Factor:      2,
+				},
+			),
+		)
+		require.NoError(t,
+			result.SetWeekly(
+				time.Saturday,
+				PriceWindow{
+					DailyWindow: DailyWindow{From: 0, To: _SecondsPerDay},
+					Factor:      1.5,
+				},
+			),
+		)
+		require.NoError(t,
+			result.AddSeason(
+				Season{From: "07-01", To: "08-31", Factor: 3},
+			),
+		)
+		require.NoError(t,
+			result.AddSeason(
+				Season{From: "12-15", To: "01-15", Factor: 0.5},
+			),
+		)
+
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		interval TimeInterval
+		expected *big.Rat
+	}{
+		{
+			name:     "1. Off peak",
+			interval: TimeInterval{TimeStart: hour(wednesday, 21), TimeEnd: hour(wednesday, 23)},
+			expected: big.NewRat(1, 1),
+		},
+		{
+			name:     "2. Crossing into off peak, pro rata",
+			interval: TimeInterval{TimeStart: hour(wednesday, 19), TimeEnd: hour(wednesday, 21)},
+			expected: big.NewRat(3, 2),
+		},
+		{
+			name:     "3. Weekend",
+			interval: TimeInterval{TimeStart: hour(saturday, 10), TimeEnd: hour(saturday, 12)},
+			expected: big.NewRat(3, 2),
+		},
+		{
+			name:     "4. High season peak",
+			interval: TimeInterval{TimeStart: hour(wednesdaySummer, 9), TimeEnd: hour(wednesdaySummer, 10)},
+			expected: big.NewRat(6, 1),
+		},
+		{
+			name:     "5. Season over the new year",
+			interval: TimeInterval{TimeStart: hour(wednesdayWinter, 21), TimeEnd: hour(wednesdayWinter, 22)},
+			expected: big.NewRat(1, 2),
+		},
+		{
+			name: "6. Local wall clock",
+			interval: TimeInterval{
+				TimeStart:     hour(wednesday, 21),
+				TimeEnd:       hour(wednesday, 22),
+				SecondsOffset: 2 * oneHour, // 19:00 UTC
+			},
+			expected: big.NewRat(1, 1),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name,
+			func(t *testing.T) {
+				require.Equal(t,
+					tt.expected.RatString(),
+					newPricing(t).GetFactor(&tt.interval, &tt.interval).RatString(),
+				)
+			},
+		)
+	}
+
+	t.Run(
+		"7. Nil calendar",
+		func(t *testing.T) {
+			var pricing *PricingCalendar
+
+			interval := TimeInterval{TimeStart: hour(wednesday, 9), TimeEnd: hour(wednesday, 10)}
+
+			require.Equal(t,
+				"1",
+				pricing.GetFactor(&interval, &interval).RatString(),
+			)
+		},
+	)
+
+	t.Run(
+		"8. Invalid",
+		func(t *testing.T) {
+			pricing := NewPricingCalendar()
+
+			require.Error(t,
+				pricing.SetWeekly(
+					time.Monday,
+					PriceWindow{DailyWindow: DailyWindow{From: 0, To: 10 * oneHour}, Factor: 2},
+					PriceWindow{DailyWindow: DailyWindow{From: 9 * oneHour, To: 12 * oneHour}, Factor: 3},
+				),
+			)
+			require.Error(t,
+				pricing.AddSeason(Season{From: "13-01", To: "01-15", Factor: 1}),
+			)
+			require.Error(t,
+				pricing.AddSeason(Season{From: "01-01", To: "01-15", Factor: -1}),
+			)
+		},
+	)
+
+	t.Run(
+		"9. Work moves to the cheaper window",
+		func(t *testing.T) {
+			machine := newTestResource(1, 1, 0, nil)
+			machine.CostPerHour = Money{Amount: 10}
+
+			engine, errCr := NewEngine(
+				&ParamsNewEngine{
+					ID:        1,
+					Name:      t.Name(),
+					Resources: []*ResourceScheduled{machine},
+					Pricing:   newPricing(t),
+				},
+			)
+			require.NoError(t, errCr)
+
+			run := Run{
+				ID:                1,
+				EstimatedDuration: oneHour,
+
+				Dependencies: []RunDependency{
+					{
+						ResourceType:     1,
+						ResourceQuantity: 1,
+					},
+				},
+
+				RunLoad: RunLoad{
+					Load:     1,
+					LoadUnit: 1,
+				},
+			}
+
+			params := ParamsCanRun{
+				TimeInterval: TimeInterval{
+					TimeStart: hour(wednesday, 18),
+					TimeEnd:   hour(wednesday, 21),
+				},
+				TaskRun: &run,
+			}
+
+			response, errSchedule := engine.CanSchedule(&params)
+			require.NoError(t, errSchedule)
+			require.False(t, response.WasScheduled)
+			require.Equal(t,
+				hour(wednesday, 20),
+				response.WhenCanStart,
+			)
+			require.EqualValues(t,
+				10,
+				response.Cost.Amount,
+			)
+
+			options, errGet := engine.GetSchedulingOptions(&params)
+			require.NoError(t, errGet)
+			require.Len(t,
+				options,
+				3,
+			)
+			require.EqualValues(t,
+				20,
+				options[0].Cost.Amount,
+				"peak",
+			)
+			require.EqualValues(t,
+				10,
+				options[2].Cost.Amount,
+			)
+
+			loco := Loco{
+				ID:   1,
+				Name: t.Name(),
+
+				Resources: ResourcesPerType{
+					1: {machine},
+				},
+				Pricing: newPricing(t),
+			}
+
+			optionsLoco, errGetLoco := loco.GetSchedulingOptions(&params)
+			require.NoError(t, errGetLoco)
+
+			costLoco, errCostLoco := optionsLoco[0].GetCostFor(&run)
+			require.NoError(t, errCostLoco)
+			require.EqualValues(t,
+				20,
+				costLoco.Amount,
+			)
+		},
+	)
+
+	t.Run(
+		"10. Pro rata factor not a finite decimal",
+		func(t *testing.T) {
+			// an hour of three at twice the price
+			interval := TimeInterval{TimeStart: hour(wednesday, 19), TimeEnd: hour(wednesday, 22)}
+
+			require.Equal(t,
+				"4/3",
+				newPricing(t).GetFactor(&interval, &interval).RatString(),
+			)
+
+			machine := newTestResource(1, 1, 0, nil)
+			machine.CostPerHour = Money{Amount: 300000000000}
+			machine.Pricing = newPricing(t)
+
+			run := newTestRun(1, 3*oneHour, 1)
+
+			cost, errGet := run.GetCostAt(
+				[]*ResourceScheduled{machine},
+				&interval,
+			)
+			require.NoError(t, errGet)
+			require.EqualValues(t,
+				1200000000000,
+				cost.Total.Amount,
+			)
+		},
+	)
+}
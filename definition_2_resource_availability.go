@@ -1,92 +1,119 @@
 package scheduler
 
-import "sort"
-
 // IsAvailableIn
 func (res *ResourceScheduled) IsAvailableIn(interval *TimeInterval) bool {
 	res.mu.RLock()
-	if _, exists := res.schedule[*interval]; exists {
+	defer res.mu.RUnlock()
+
+	if res.schedule.has(interval) {
 		return false
 	}
-	res.mu.RUnlock()
 
-	return true
+	return res.Shifts.IsOpen(res.getShiftsReference(), interval)
 }
 
-// GetAvailability returns:
-//   - (nil, true)   = Fully available (no busy intervals or no overlap)
-//   - (slots, false) = Partially available (returns available time slots)
-//   - (nil, false)  = Completely unavailable (requested interval is fully booked)
-func (res *ResourceScheduled) GetAvailability(searchInterval *TimeInterval) ([]TimeInterval, bool) {
-	var busyUTCIntervals []TimeInterval
-
-	for scheduledInterval := range res.schedule {
-		busyUTCIntervals = append(
-			busyUTCIntervals,
-			TimeInterval{
-				TimeStart:     scheduledInterval.GetUTCTimeStart(),
-				TimeEnd:       scheduledInterval.GetUTCTimeEnd(),
-				SecondsOffset: scheduledInterval.SecondsOffset,
-			},
-		)
+// getShiftsReference returns an empty interval in the shifts wall clock,
+// used as reference for calendar conversions.
+func (res *ResourceScheduled) getShiftsReference() *TimeInterval {
+	return &TimeInterval{
+		SecondsOffset: res.ShiftsOffset,
+		TimeZone:      res.ShiftsTimeZone,
 	}
+}
+
+// getBusy returns the bookings overlapping the search interval as an interval set in its offset.
+func (res *ResourceScheduled) getBusy(searchInterval *TimeInterval) *IntervalSet {
+	busy := make([]TimeInterval, 0)
+
+	res.schedule.visitOverlapping(
+		searchInterval,
+
+		func(entry *scheduleEntry) bool {
+			busy = append(busy, entry.TimeInterval)
 
-	sort.Slice(
-		busyUTCIntervals,
-		func(i, j int) bool {
-			return busyUTCIntervals[i].TimeStart < busyUTCIntervals[j].TimeStart
+			return true
 		},
 	)
 
-	var availableIntervals []TimeInterval
+	return NewIntervalSet(searchInterval.SecondsOffset, busy...)
+}
 
-	currentStart := searchInterval.GetUTCTimeStart()
-	searchEnd := searchInterval.GetUTCTimeEnd()
+// getUnavailable returns the busy time and, within the search interval, the time outside shifts.
+func (res *ResourceScheduled) getUnavailable(searchInterval *TimeInterval) *IntervalSet {
+	busy := res.getBusy(searchInterval)
 
-	// Check if any busy interval overlaps
-	hasOverlap := false
+	if res.Shifts == nil {
+		return busy
+	}
 
-	for _, busy := range busyUTCIntervals {
-		if busy.TimeEnd <= currentStart {
-			continue
-		}
+	offShift := NewIntervalSet(searchInterval.SecondsOffset, *searchInterval).
+		Subtract(
+			res.Shifts.GetOpenIntervals(res.getShiftsReference(), searchInterval),
+		)
 
-		if busy.TimeStart >= searchEnd {
-			break
-		}
+	return busy.Union(offShift)
+}
 
-		hasOverlap = true
+// GetAvailability returns:
+//   - (nil, true)   = Fully available (no busy intervals or no overlap)
+//   - (slots, false) = Partially available (returns available time slots)
+//   - (nil, false)  = Completely unavailable (requested interval is fully booked or off shift)
+func (res *ResourceScheduled) GetAvailability(searchInterval *TimeInterval) ([]TimeInterval, bool) {
+	res.mu.RLock()
+	defer res.mu.RUnlock()
 
-		if busy.TimeStart > currentStart {
-			availableIntervals = append(
-				availableIntervals,
-				TimeInterval{
-					TimeStart:     currentStart + searchInterval.SecondsOffset,
-					TimeEnd:       busy.TimeStart + searchInterval.SecondsOffset,
-					SecondsOffset: searchInterval.SecondsOffset,
-				},
-			)
-		}
+	return res.getAvailability(searchInterval)
+}
 
-		currentStart = max(currentStart, busy.TimeEnd)
-	}
+// getAvailability is GetAvailability for callers holding the lock.
+func (res *ResourceScheduled) getAvailability(searchInterval *TimeInterval) ([]TimeInterval, bool) {
+	unavailable := res.getUnavailable(searchInterval)
 
-	if currentStart < searchEnd {
-		availableIntervals = append(
-			availableIntervals,
-			TimeInterval{
-				TimeStart:     currentStart + searchInterval.SecondsOffset,
-				TimeEnd:       searchEnd + searchInterval.SecondsOffset,
-				SecondsOffset: searchInterval.SecondsOffset,
-			},
-		)
+	if !unavailable.Overlaps(searchInterval) {
+		return nil,
+			true // Fully available if no overlap
 	}
 
-	if !hasOverlap {
+	availableIntervals := unavailable.Complement(searchInterval).IntervalsAs(searchInterval)
+	if len(availableIntervals) == 0 {
 		return nil,
-			true // Fully available if no overlap
+			false
 	}
 
 	return availableIntervals,
 		false
 }
+
+// GetBookedSeconds returns the seconds booked within the interval, all booked seconds if nil.
+func (res *ResourceScheduled) GetBookedSeconds(interval *TimeInterval) int64 {
+	res.mu.RLock()
+	defer res.mu.RUnlock()
+
+	var result int64
+
+	if interval == nil {
+		res.schedule.ascend(
+			func(entry *scheduleEntry) bool {
+				result = result + entry.utcEnd - entry.utcStart
+
+				return true
+			},
+		)
+
+		return result
+	}
+
+	utcStart, utcEnd := interval.GetUTCTimeStart(), interval.GetUTCTimeEnd()
+
+	res.schedule.visitOverlapping(
+		interval,
+
+		func(entry *scheduleEntry) bool {
+			result = result + min(entry.utcEnd, utcEnd) - max(entry.utcStart, utcStart)
+
+			return true
+		},
+	)
+
+	return result
+}
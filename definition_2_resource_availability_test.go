@@ -3,6 +3,7 @@ package scheduler
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -21,16 +22,16 @@ func TestGetAvailability(t *testing.T) {
 				&ParamsNewResource{
 					Name:            "Low Cost",
 					ResourceType:    1,
-					CostPerLoadUnit: map[uint8]float32{1: 2.0},
+					CostPerLoadUnit: map[uint8]Money{1: {Amount: 2}},
 				},
 			)
 			require.NoError(t, errCr)
 			require.NotNil(t, resource)
 
-			resource.schedule = map[TimeInterval]RunID{
+			resource.schedule = newTestSchedule(map[TimeInterval]RunID{
 				{TimeStart: now, TimeEnd: now + oneHour}:               Maintenance,
 				{TimeStart: now + 2*oneHour, TimeEnd: now + 3*oneHour}: Maintenance,
-			}
+			})
 
 			intervals, isAvailable := resource.GetAvailability(&targetInterval)
 			require.False(t, isAvailable)
@@ -51,15 +52,15 @@ func TestGetAvailability(t *testing.T) {
 				&ParamsNewResource{
 					Name:            "High Cost",
 					ResourceType:    1,
-					CostPerLoadUnit: map[uint8]float32{1: 3.0},
+					CostPerLoadUnit: map[uint8]Money{1: {Amount: 3}},
 				},
 			)
 			require.NoError(t, errCr)
 			require.NotNil(t, resource)
 
-			resource.schedule = map[TimeInterval]RunID{
+			resource.schedule = newTestSchedule(map[TimeInterval]RunID{
 				{TimeStart: now + oneHour, TimeEnd: now + oneHour + halfHour}: Maintenance,
-			}
+			})
 
 			intervals, isAvailable := resource.GetAvailability(&targetInterval)
 			require.False(t, isAvailable)
@@ -80,15 +81,15 @@ func TestGetAvailability(t *testing.T) {
 				&ParamsNewResource{
 					Name:            "Type 2",
 					ResourceType:    1,
-					CostPerLoadUnit: map[uint8]float32{1: 1.0},
+					CostPerLoadUnit: map[uint8]Money{1: {Amount: 1}},
 				},
 			)
 			require.NoError(t, errCr)
 			require.NotNil(t, resource)
 
-			resource.schedule = map[TimeInterval]RunID{
+			resource.schedule = newTestSchedule(map[TimeInterval]RunID{
 				{TimeStart: now + oneHour, TimeEnd: now + oneHour + halfHour}: Maintenance,
-			}
+			})
 
 			intervals, isAvailable := resource.GetAvailability(&targetInterval)
 			require.False(t, isAvailable)
@@ -101,3 +102,111 @@ func TestGetAvailability(t *testing.T) {
 		},
 	)
 }
+
+func TestGetAvailabilityShifts(t *testing.T) {
+	// Monday, morning shift 06:00 - 14:00 in UTC+2.
+	monday := time.Date(2026, time.March, 2, 0, 0, 0, 0, time.UTC).Unix()
+
+	shifts := NewCalendar()
+	require.NoError(t,
+		shifts.SetWeekly(
+			time.Monday,
+
+			DailyWindow{
+				From: 6 * oneHour,
+				To:   14 * oneHour,
+			},
+		),
+	)
+
+	resource, errCr := NewResource(
+		&ParamsNewResource{
+			Name:            "Operator",
+			ResourceType:    1,
+			CostPerLoadUnit: map[uint8]Money{1: {Amount: 1}},
+
+			Shifts:       shifts,
+			ShiftsOffset: 2 * oneHour,
+		},
+	)
+	require.NoError(t, errCr)
+
+	t.Run(
+		"1. Inside shift",
+		func(t *testing.T) {
+			interval := TimeInterval{
+				TimeStart:     monday + 8*oneHour,
+				TimeEnd:       monday + 10*oneHour,
+				SecondsOffset: 2 * oneHour,
+			}
+
+			intervals, isAvailable := resource.GetAvailability(&interval)
+			require.True(t, isAvailable)
+			require.Empty(t, intervals)
+			require.True(t,
+				resource.IsAvailableIn(&interval),
+			)
+		},
+	)
+
+	t.Run(
+		"2. Shift ends within interval, other offset",
+		func(t *testing.T) {
+			// 11:00 - 15:00 UTC, shift ends 12:00 UTC.
+			interval := TimeInterval{
+				TimeStart: monday + 11*oneHour,
+				TimeEnd:   monday + 15*oneHour,
+			}
+
+			intervals, isAvailable := resource.GetAvailability(&interval)
+			require.False(t, isAvailable)
+			require.Equal(t,
+				[]TimeInterval{
+					{TimeStart: monday + 11*oneHour, TimeEnd: monday + 12*oneHour},
+				},
+				intervals,
+			)
+			require.False(t,
+				resource.IsAvailableIn(&interval),
+			)
+		},
+	)
+
+	t.Run(
+		"3. Off shift and busy",
+		func(t *testing.T) {
+			resource.schedule = newTestSchedule(map[TimeInterval]RunID{
+				{TimeStart: monday + 6*oneHour, TimeEnd: monday + 14*oneHour, SecondsOffset: 2 * oneHour}: Maintenance,
+			})
+			defer func() {
+				resource.schedule = newTestSchedule(map[TimeInterval]RunID{})
+			}()
+
+			intervals, isAvailable := resource.GetAvailability(
+				&TimeInterval{
+					TimeStart: monday,
+					TimeEnd:   monday + oneDay,
+				},
+			)
+			require.False(t, isAvailable)
+			require.Empty(t, intervals)
+		},
+	)
+
+	t.Run(
+		"4. Run off shift is rejected",
+		func(t *testing.T) {
+			_, errAdd := resource.AddRun(
+				t.Context(),
+				&ParamsRun{
+					TimeInterval: TimeInterval{
+						TimeStart: monday + 14*oneHour,
+						TimeEnd:   monday + 15*oneHour,
+					},
+					ID: 1,
+				},
+			)
+			require.Error(t, errAdd)
+		},
+	)
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"sort"
+	"maps"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	goerrors "github.com/TudorHulban/go-errors"
 )
@@ -19,10 +22,29 @@ type ResourceType uint8
 
 type ResourceInfo struct {
 	Name            string
-	CostPerLoadUnit map[uint8]float32 // load unit | cost per unit
+	CostPerLoadUnit map[uint8]Money  // load unit | cost per unit
+	CostPerHour     Money            // charged pro rata for the seconds a run holds the resource
+	CostPerRun      Money            // activation or setup fee, charged once per run
+	Pricing         *PricingCalendar // weights load and time cost by time, nil takes the location one
 	ID              int
 	ResourceType    uint8
-	ServedQuantity  uint16 // ex. apartment w 2 rooms serves 2, room serves 1
+	ServedQuantity  uint16 // ex. apartment w 2 rooms serves 2, room serves 1, zero serves 1
+}
+
+// getServedQuantity is the quantity the resource serves towards the quantity needed by a run.
+func (r ResourceInfo) getServedQuantity() uint16 {
+	return max(1, r.ServedQuantity)
+}
+
+// getPrices returns the rates and fee of the resource.
+func (r ResourceInfo) getPrices() []Money {
+	result := make([]Money, 0, len(r.CostPerLoadUnit)+2)
+
+	for _, loadUnit := range sortedKeys(r.CostPerLoadUnit) {
+		result = append(result, r.CostPerLoadUnit[loadUnit])
+	}
+
+	return append(result, r.CostPerHour, r.CostPerRun)
 }
 
 func (r ResourceInfo) String() string {
@@ -35,20 +57,50 @@ func (r ResourceInfo) String() string {
 	return sb.String()
 }
 
-// ResourceScheduled is mutex protected through Location ops.
+// ResourceScheduled guards its schedule with its own lock, exported methods are safe for concurrent use.
+// Unexported helpers working on the schedule expect the caller to hold the lock.
 type ResourceScheduled struct {
 	ResourceInfo
 
-	mu sync.RWMutex
+	// Shifts are the working windows, time outside them is unavailable.
+	// Wall clock is ShiftsTimeZone if set, otherwise ShiftsOffset. Nil shifts is always available.
+	Shifts         *Calendar
+	ShiftsTimeZone *time.Location
+	ShiftsOffset   int64
+
+	mu       sync.RWMutex
+	sequence atomic.Uint64 // orders resources sharing an ID when locking, see getSequence
+
+	schedule runSchedule
+}
+
+// resourceSequences numbers the resources in creation order.
+var resourceSequences atomic.Uint64
+
+// getSequence returns the sequence of the resource, assigned at creation
+// or at first use for resources not built with NewResource.
+func (res *ResourceScheduled) getSequence() uint64 {
+	if sequence := res.sequence.Load(); sequence != 0 {
+		return sequence
+	}
+
+	res.sequence.CompareAndSwap(0, resourceSequences.Add(1))
 
-	schedule map[TimeInterval]RunID
+	return res.sequence.Load()
 }
 
 type ParamsNewResource struct {
 	Name            string
-	CostPerLoadUnit map[uint8]float32
+	CostPerLoadUnit map[uint8]Money // empty for resources charging by the hour only
+	CostPerHour     Money
+	CostPerRun      Money
+	Pricing         *PricingCalendar // nil takes the location one
 	ID              int
 	ResourceType    uint8
+
+	Shifts         *Calendar
+	ShiftsTimeZone *time.Location
+	ShiftsOffset   int64
 }
 
 func (param *ParamsNewResource) IsValid() error {
@@ -70,7 +122,7 @@ func (param *ParamsNewResource) IsValid() error {
 		}
 	}
 
-	if param.CostPerLoadUnit == nil {
+	if len(param.CostPerLoadUnit) == 0 && param.CostPerHour.IsZero() {
 		return goerrors.ErrValidation{
 			Caller: "IsValid - ParamsNewResource",
 			Issue: goerrors.ErrNilInput{
@@ -80,7 +132,7 @@ func (param *ParamsNewResource) IsValid() error {
 	}
 
 	for _, cost := range param.CostPerLoadUnit {
-		if cost < 0 {
+		if cost.Amount < 0 {
 			return goerrors.ErrValidation{
 				Caller: "IsValid - ParamsNewResource",
 				Issue: goerrors.ErrNegativeInput{
@@ -90,6 +142,37 @@ func (param *ParamsNewResource) IsValid() error {
 		}
 	}
 
+	if param.CostPerHour.Amount < 0 {
+		return goerrors.ErrValidation{
+			Caller: "IsValid - ParamsNewResource",
+			Issue: goerrors.ErrNegativeInput{
+				InputName: "CostPerHour",
+			},
+		}
+	}
+
+	if param.CostPerRun.Amount < 0 {
+		return goerrors.ErrValidation{
+			Caller: "IsValid - ParamsNewResource",
+			Issue: goerrors.ErrNegativeInput{
+				InputName: "CostPerRun",
+			},
+		}
+	}
+
+	prices := append(
+		slices.Collect(maps.Values(param.CostPerLoadUnit)),
+		param.CostPerHour,
+		param.CostPerRun,
+	)
+
+	if _, errCurrency := getCurrency("", prices...); errCurrency != nil {
+		return goerrors.ErrValidation{
+			Caller: "IsValid - ParamsNewResource",
+			Issue:  errCurrency,
+		}
+	}
+
 	return nil
 }
 
@@ -99,57 +182,74 @@ func NewResource(params *ParamsNewResource) (*ResourceScheduled, error) {
 			errValidation
 	}
 
-	return &ResourceScheduled{
-			ResourceInfo: ResourceInfo{
-				ID:           params.ID,
-				Name:         params.Name,
-				ResourceType: params.ResourceType,
+	result := ResourceScheduled{
+		ResourceInfo: ResourceInfo{
+			ID:           params.ID,
+			Name:         params.Name,
+			ResourceType: params.ResourceType,
 
-				CostPerLoadUnit: params.CostPerLoadUnit,
-			},
-
-			schedule: make(map[TimeInterval]RunID),
+			CostPerLoadUnit: params.CostPerLoadUnit,
+			CostPerHour:     params.CostPerHour,
+			CostPerRun:      params.CostPerRun,
+			Pricing:         params.Pricing,
 		},
+
+		Shifts:         params.Shifts,
+		ShiftsTimeZone: params.ShiftsTimeZone,
+		ShiftsOffset:   params.ShiftsOffset,
+	}
+
+	result.getSequence()
+
+	return &result,
 		nil
 }
 
 func (res *ResourceScheduled) GetSchedule() string {
-	if len(res.schedule) == 0 {
-		return "Schedule: (empty)"
-	}
+	res.mu.RLock()
+	defer res.mu.RUnlock()
 
-	// Extract and sort intervals
-	intervals := make([]TimeInterval, 0, len(res.schedule))
-	for interval := range res.schedule {
-		intervals = append(intervals, interval)
+	if res.schedule.Len() == 0 {
+		return "Schedule: (empty)"
 	}
 
-	sort.Slice(
-		intervals,
-		func(i, j int) bool {
-			return intervals[i].TimeStart < intervals[j].TimeStart
-		},
-	)
-
 	var sb strings.Builder
 	sb.WriteString("Schedule:\n")
 
-	for _, interval := range intervals {
-		taskID := res.schedule[interval]
+	res.schedule.ascend(
+		func(entry *scheduleEntry) bool {
+			interval := entry.TimeInterval
 
-		sb.WriteString(
-			fmt.Sprintf(
-				"- [%d-%d] (UTC %d-%d) Offset %.1fh → Task %d\n",
+			offsetStart := interval.GetSecondsOffsetStart()
+			offsetEnd := interval.GetSecondsOffsetEnd()
 
-				interval.TimeStart,
-				interval.TimeEnd,
-				interval.GetUTCTimeStart(),
-				interval.GetUTCTimeEnd(),
-				float64(interval.SecondsOffset)/3600,
-				taskID,
-			),
-		)
-	}
+			offset := ternary(
+				offsetStart == offsetEnd,
+
+				fmt.Sprintf("%.1fh", float64(offsetStart)/3600),
+				fmt.Sprintf("%.1fh/%.1fh", float64(offsetStart)/3600, float64(offsetEnd)/3600),
+			)
+
+			if interval.TimeZone != nil {
+				offset = offset + " " + interval.TimeZone.String()
+			}
+
+			sb.WriteString(
+				fmt.Sprintf(
+					"- [%d-%d] (UTC %d-%d) Offset %s → Task %d\n",
+
+					interval.TimeStart,
+					interval.TimeEnd,
+					entry.utcStart,
+					entry.utcEnd,
+					offset,
+					entry.RunID,
+				),
+			)
+
+			return true
+		},
+	)
 
 	return sb.String()
 }
@@ -192,28 +292,25 @@ func (res *ResourceScheduled) AddRun(_ context.Context, params *ParamsRun) ([]Ti
 		}
 	}
 
-	for interval := range res.schedule {
-		if res.schedule[interval] == params.ID {
-			return nil,
-				fmt.Errorf(
-					"run ID %d already exists",
-					params.ID,
-				)
-		}
+	res.mu.Lock()
+	defer res.mu.Unlock()
+
+	if res.schedule.hasRun(params.ID) {
+		return nil,
+			fmt.Errorf(
+				"run ID %d already exists",
+				params.ID,
+			)
 	}
 
-	overlaps, available := res.GetAvailability(&params.TimeInterval)
+	overlaps, available := res.getAvailability(&params.TimeInterval)
 	if !available {
 		return overlaps,
 			errors.New("requested time slot is busy")
 	}
 
 	// Add the run
-	res.schedule[TimeInterval{
-		TimeStart:     params.TimeStart,
-		TimeEnd:       params.TimeEnd,
-		SecondsOffset: params.SecondsOffset,
-	}] = params.ID
+	res.schedule.insert(params.TimeInterval, params.ID)
 
 	return nil, nil
 }
@@ -223,21 +320,30 @@ type ResponseGetRun struct {
 	AlreadyScheduledTaskEndTime int64
 }
 
+// GetRun returns the run booked at the instant atTimestamp is in the fixed offset,
+// whatever offset the run was booked in. The end is returned in the same offset.
 func (res *ResourceScheduled) GetRun(atTimestamp, offset int64) (*ResponseGetRun, error) {
-	for interval, runID := range res.schedule {
-		offsetDifference := interval.SecondsOffset - offset
+	res.mu.RLock()
+	defer res.mu.RUnlock()
 
-		scheduleStartUTC := interval.TimeStart + offsetDifference
-		scheduleEndUTC := interval.TimeEnd + offsetDifference
-		atTimestampUTC := atTimestamp + offsetDifference
+	var result *ResponseGetRun
 
-		if scheduleEndUTC >= atTimestampUTC && scheduleStartUTC <= atTimestampUTC {
-			return &ResponseGetRun{
-					ID:                          runID,
-					AlreadyScheduledTaskEndTime: scheduleEndUTC,
-				},
-				nil
-		}
+	res.schedule.visitAt(
+		atTimestamp-offset,
+
+		func(entry *scheduleEntry) bool {
+			result = &ResponseGetRun{
+				ID:                          entry.RunID,
+				AlreadyScheduledTaskEndTime: entry.utcEnd + offset,
+			}
+
+			return false
+		},
+	)
+
+	if result != nil {
+		return result,
+			nil
 	}
 
 	return nil,
@@ -246,26 +352,25 @@ func (res *ResourceScheduled) GetRun(atTimestamp, offset int64) (*ResponseGetRun
 		)
 }
 
-// removeRun should be called through Location which is mutex protected.
+// removeRun removes all bookings of the run.
 func (res *ResourceScheduled) removeRun(runID RunID) error {
-	for interval, id := range res.schedule {
-		if id == runID {
-			delete(res.schedule, interval)
+	res.mu.Lock()
+	defer res.mu.Unlock()
 
-			return nil
-		}
+	if res.schedule.removeRuns(runID) == 0 {
+		return fmt.Errorf("run %d not found in schedule", runID)
 	}
 
-	return fmt.Errorf("run %d not found in schedule", runID)
+	return nil
 }
 
-func (res *ResourceScheduled) GetRunCost(run *Run) (float32, error) {
-	cost, exists := res.CostPerLoadUnit[run.LoadUnit]
-	if !exists {
-		return 0,
+func (res *ResourceScheduled) GetRunCost(run *Run) (Money, error) {
+	line, isPriced := (&runPricing{run: run}).getCostLine(res)
+	if !isPriced {
+		return Money{},
 			errors.New("unsupported load unit")
 	}
 
-	return cost * run.Load,
+	return line.Cost,
 		nil
 }
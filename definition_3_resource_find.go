@@ -7,6 +7,8 @@ type paramsFindAvailableTime struct {
 	SecondsOffsetTask     int64
 	SecondsOffsetLocation int64
 
+	OpenTimeIntervals *IntervalSet // nil if always open
+
 	IsLatest bool
 }
 
@@ -16,14 +18,26 @@ func (res *ResourceScheduled) findAvailableTime(params *paramsFindAvailableTime)
 		return _NoAvailability
 	}
 
-	offsetDifference := params.SecondsOffsetTask - params.SecondsOffsetLocation
+	// task time to location time: UTC is task time minus the task offset, plus the location offset.
+	offsetDifference := params.SecondsOffsetLocation - params.SecondsOffsetTask
+
+	searchInterval := TimeInterval{
+		TimeStart:     params.TimeStart + offsetDifference,
+		TimeEnd:       params.MaximumTimeStart + offsetDifference + params.SecondsDuration,
+		SecondsOffset: params.SecondsOffsetLocation,
+	}
+
+	intervals, available := res.GetAvailability(&searchInterval)
+
+	if params.OpenTimeIntervals != nil {
+		intervals, available = restrictToOpenTime(
+			params.OpenTimeIntervals,
+			&searchInterval,
+			intervals,
+			available,
+		)
+	}
 
-	intervals, available := res.GetAvailability(
-		&TimeInterval{
-			TimeStart: params.TimeStart + offsetDifference,
-			TimeEnd:   params.MaximumTimeStart + offsetDifference + params.SecondsDuration,
-		},
-	)
 	if available {
 		return params.TimeStart // Immediate availability
 	}
@@ -36,7 +50,7 @@ func (res *ResourceScheduled) findAvailableTime(params *paramsFindAvailableTime)
 		for i := len(intervals) - 1; i >= 0; i-- {
 			interval := intervals[i]
 
-			if interval.TimeEnd-interval.TimeStart >= params.SecondsDuration {
+			if interval.Duration() >= params.SecondsDuration {
 				startTaskTime := min(
 					interval.TimeEnd-offsetDifference-params.SecondsDuration,
 					params.MaximumTimeStart,
@@ -50,7 +64,7 @@ func (res *ResourceScheduled) findAvailableTime(params *paramsFindAvailableTime)
 	}
 
 	for _, interval := range intervals {
-		if interval.TimeEnd-interval.TimeStart >= params.SecondsDuration {
+		if interval.Duration() >= params.SecondsDuration {
 			startTaskTime := interval.TimeStart - offsetDifference
 
 			if startTaskTime >= params.TimeStart && startTaskTime <= params.MaximumTimeStart {
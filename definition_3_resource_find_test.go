@@ -94,6 +94,22 @@ func TestFindAvailableTime(t *testing.T) {
 
 			expectedResult: _NoAvailability,
 		},
+		{
+			// task 10000 at UTC+2 is 2800 UTC, busy the first hour of the request
+			name: "7. Timezone conversion, busy in location time",
+			schedule: map[TimeInterval]RunID{
+				{TimeStart: now - 2*oneHour, TimeEnd: now - oneHour}: Maintenance,
+			},
+			params: paramsFindAvailableTime{
+				TimeStart:             now,
+				MaximumTimeStart:      now + 2*oneHour,
+				SecondsDuration:       oneHour,
+				SecondsOffsetTask:     2 * oneHour, // UTC+2
+				SecondsOffsetLocation: 0,           // UTC
+			},
+
+			expectedResult: now + oneHour,
+		},
 	}
 
 	for _, tt := range tests {
@@ -101,7 +117,7 @@ func TestFindAvailableTime(t *testing.T) {
 			tt.name,
 			func(t *testing.T) {
 				res := ResourceScheduled{
-					schedule: tt.schedule,
+					schedule: newTestSchedule(tt.schedule),
 				}
 
 				result := res.findAvailableTime(&tt.params)
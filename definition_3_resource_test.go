@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -25,8 +26,8 @@ func TestErrorsResource(t *testing.T) {
 		func(t *testing.T) {
 			res, errCr := NewResource(
 				&ParamsNewResource{
-					CostPerLoadUnit: map[uint8]float32{
-						1: 0.1,
+					CostPerLoadUnit: map[uint8]Money{
+						1: NewMoney(10, "EUR"),
 					},
 					ResourceType: 1,
 				},
@@ -49,14 +50,115 @@ func TestErrorsResource(t *testing.T) {
 			require.Nil(t, res)
 		},
 	)
+
+	t.Run(
+		"4. negative hourly rate or fee",
+		func(t *testing.T) {
+			_, errHourly := NewResource(
+				&ParamsNewResource{
+					Name:         "res 1",
+					ResourceType: 1,
+					CostPerHour:  NewMoney(-1, "EUR"),
+				},
+			)
+			require.Error(t, errHourly)
+
+			_, errFee := NewResource(
+				&ParamsNewResource{
+					Name:         "res 1",
+					ResourceType: 1,
+					CostPerHour:  NewMoney(100, "EUR"),
+					CostPerRun:   NewMoney(-1, "EUR"),
+				},
+			)
+			require.Error(t, errFee)
+		},
+	)
+
+	t.Run(
+		"5. rates in different currencies",
+		func(t *testing.T) {
+			res, errCr := NewResource(
+				&ParamsNewResource{
+					Name: "res 1",
+					CostPerLoadUnit: map[uint8]Money{
+						1: NewMoney(10, "EUR"),
+					},
+					CostPerHour:  NewMoney(100, "USD"),
+					ResourceType: 1,
+				},
+			)
+			require.Error(t, errCr)
+			require.Nil(t, res)
+		},
+	)
+}
+
+func TestNewResourcePricing(t *testing.T) {
+	res, errCr := NewResource(
+		&ParamsNewResource{
+			Name:         "res",
+			CostPerHour:  NewMoney(120, "EUR"),
+			CostPerRun:   NewMoney(50, "EUR"),
+			ResourceType: 1,
+		},
+	)
+	require.NoError(t, errCr)
+
+	cost, errGet := newTestRun(1, oneHour, 1).GetCost(
+		[]*ResourceScheduled{res},
+	)
+	require.NoError(t, errGet)
+	require.True(t,
+		cost.Lines[0].CostLoad.IsZero(),
+		"charges by the hour only",
+	)
+	require.Equal(t,
+		NewMoney(120+50, "EUR"),
+		cost.Total,
+	)
+
+	pricing := NewPricingCalendar()
+	require.NoError(t,
+		pricing.SetWeekly(
+			time.Thursday, // now is on 1 January 1970
+			PriceWindow{
+				DailyWindow: DailyWindow{From: 0, To: _SecondsPerDay},
+				Factor:      2,
+			},
+		),
+	)
+
+	resCalendar, errCrCalendar := NewResource(
+		&ParamsNewResource{
+			Name:         "res",
+			CostPerHour:  NewMoney(120, "EUR"),
+			Pricing:      pricing,
+			ResourceType: 1,
+		},
+	)
+	require.NoError(t, errCrCalendar)
+
+	costAt, errGetAt := newTestRun(1, oneHour, 1).GetCostAt(
+		[]*ResourceScheduled{resCalendar},
+		&TimeInterval{
+			TimeStart: now,
+			TimeEnd:   now + oneHour,
+		},
+	)
+	require.NoError(t, errGetAt)
+	require.Equal(t,
+		NewMoney(240, "EUR"),
+		costAt.Total,
+	)
 }
 
 func TestLifeCycleResource(t *testing.T) {
 	res, errCr := NewResource(
 		&ParamsNewResource{
 			Name: "res",
-			CostPerLoadUnit: map[uint8]float32{
-				1: 0.1,
+			CostPerLoadUnit: map[uint8]Money{
+				1: NewMoney(10, "EUR"),
 			},
 			ResourceType: 1,
 		},
@@ -100,9 +202,9 @@ func TestLifeCycleResource(t *testing.T) {
 	)
 	require.NoError(t, errAddTask)
 	require.Empty(t, overlapAddTask)
-	require.Len(t,
-		res.schedule,
+	require.Equal(t,
 		1,
+		res.schedule.Len(),
 	)
 
 	fmt.Println(
@@ -172,3 +274,45 @@ func TestLifeCycleResource(t *testing.T) {
 		res.GetSchedule(),
 	)
 }
+
+// TestGetRunAcrossOffsets books the same local times from two offsets.
+// Comparing local times, as before the interval algebra, matched both runs
+// and returned the end shifted the wrong way.
+func TestGetRunAcrossOffsets(t *testing.T) {
+	res := newTestResource(1, 1, 1, map[TimeInterval]RunID{
+		{TimeStart: 1000, TimeEnd: 2000, SecondsOffset: 7200}: 1, // UTC -6200 to -5200
+		{TimeStart: 1000, TimeEnd: 2000}:                      2,
+	})
+
+	for range 10 {
+		response, errGet := res.GetRun(1500, 0)
+		require.NoError(t, errGet)
+		require.EqualValues(t,
+			2,
+			response.ID,
+		)
+	}
+
+	response, errGet := res.GetRun(-5700, 0)
+	require.NoError(t, errGet)
+	require.EqualValues(t,
+		1,
+		response.ID,
+	)
+	require.EqualValues(t,
+		-5200,
+		response.AlreadyScheduledTaskEndTime,
+		"end in the offset asked, local end 2000 at offset 7200",
+	)
+
+	responseLocal, errGetLocal := res.GetRun(1500, 7200)
+	require.NoError(t, errGetLocal)
+	require.EqualValues(t,
+		1,
+		responseLocal.ID,
+	)
+	require.EqualValues(t,
+		2000,
+		responseLocal.AlreadyScheduledTaskEndTime,
+	)
+}
@@ -0,0 +1,396 @@
+package scheduler
+
+// runSchedule is the schedule of a resource, an AVL interval tree ordered by UTC start
+// and augmented with the maximum UTC end of each subtree, plus an index by run ID.
+// Lookup, insert and delete are O(log n), an overlap query is O(log n + k).
+// Queries skip the bookings of expired holds.
+// The zero value is an empty schedule.
+type runSchedule struct {
+	root  *scheduleNode
+	byRun map[RunID][]*scheduleEntry
+
+	sequence uint64
+	size     int
+}
+
+type scheduleEntry struct {
+	TimeInterval
+
+	RunID RunID
+
+	expired func() bool // of a hold, true once past its expiry, nil for runs
+
+	utcStart int64
+	utcEnd   int64
+	sequence uint64 // insertion order, breaks ties between equal starts
+}
+
+type scheduleNode struct {
+	entry *scheduleEntry
+
+	left  *scheduleNode
+	right *scheduleNode
+
+	maxUTCEnd int64
+	height    int
+}
+
+func (s *runSchedule) Len() int {
+	return s.size
+}
+
+func (s *runSchedule) insert(interval TimeInterval, runID RunID) {
+	s.insertHeld(interval, runID, nil)
+}
+
+// insertHeld inserts the booking of a hold, ignored by queries once expired
+// even before its location releases it.
+// The bookings of expired holds the interval overlaps are removed first.
+func (s *runSchedule) insertHeld(interval TimeInterval, runID RunID, expired func() bool) {
+	if s.byRun == nil {
+		s.byRun = make(map[RunID][]*scheduleEntry)
+	}
+
+	s.removeExpired(&interval)
+
+	s.sequence++
+
+	entry := scheduleEntry{
+		TimeInterval: interval,
+		RunID:        runID,
+
+		expired: expired,
+
+		utcStart: interval.GetUTCTimeStart(),
+		utcEnd:   interval.GetUTCTimeEnd(),
+		sequence: s.sequence,
+	}
+
+	s.root = s.root.insert(&entry)
+	s.byRun[runID] = append(s.byRun[runID], &entry)
+	s.size++
+}
+
+// isActive is false for the booking of an expired hold.
+func (e *scheduleEntry) isActive() bool {
+	return e.expired == nil || !e.expired()
+}
+
+// activeOnly skips in visits the bookings of expired holds.
+func activeOnly(visit func(*scheduleEntry) bool) func(*scheduleEntry) bool {
+	return func(entry *scheduleEntry) bool {
+		if !entry.isActive() {
+			return true
+		}
+
+		return visit(entry)
+	}
+}
+
+func (s *runSchedule) hasRun(runID RunID) bool {
+	return len(s.byRun[runID]) > 0
+}
+
+// getRunIntervals returns the intervals of the run bookings in insertion order.
+func (s *runSchedule) getRunIntervals(runID RunID) []TimeInterval {
+	result := make([]TimeInterval, len(s.byRun[runID]))
+
+	for ix, entry := range s.byRun[runID] {
+		result[ix] = entry.TimeInterval
+	}
+
+	return result
+}
+
+// removeRun removes the first booking of the run, false if there is none.
+func (s *runSchedule) removeRun(runID RunID) bool {
+	entries := s.byRun[runID]
+	if len(entries) == 0 {
+		return false
+	}
+
+	s.root = s.root.delete(entries[0])
+	s.size--
+
+	if len(entries) == 1 {
+		delete(s.byRun, runID)
+
+		return true
+	}
+
+	s.byRun[runID] = entries[1:]
+
+	return true
+}
+
+// removeExpired removes the bookings of expired holds overlapping the interval.
+func (s *runSchedule) removeExpired(interval *TimeInterval) {
+	var expired []RunID
+
+	s.root.visitIntersecting(
+		interval.GetUTCTimeStart(),
+		interval.GetUTCTimeEnd(),
+		false,
+
+		func(entry *scheduleEntry) bool {
+			if !entry.isActive() {
+				expired = append(expired, entry.RunID)
+			}
+
+			return true
+		},
+	)
+
+	for _, runID := range expired {
+		s.removeRuns(runID)
+	}
+}
+
+// removeRuns removes all bookings of the run, returns how many were removed.
+func (s *runSchedule) removeRuns(runID RunID) int {
+	var result int
+
+	for s.removeRun(runID) {
+		result++
+	}
+
+	return result
+}
+
+// has returns true if the exact interval is booked.
+func (s *runSchedule) has(interval *TimeInterval) bool {
+	var result bool
+
+	s.root.visitIntersecting(
+		interval.GetUTCTimeStart(),
+		interval.GetUTCTimeEnd(),
+		true,
+
+		activeOnly(
+			func(entry *scheduleEntry) bool {
+				result = entry.isSame(interval)
+
+				return !result
+			},
+		),
+	)
+
+	return result
+}
+
+// visitOverlapping visits in UTC start order the bookings overlapping the half open interval,
+// stops when visit returns false.
+func (s *runSchedule) visitOverlapping(interval *TimeInterval, visit func(*scheduleEntry) bool) {
+	s.root.visitIntersecting(
+		interval.GetUTCTimeStart(),
+		interval.GetUTCTimeEnd(),
+		false,
+
+		activeOnly(visit),
+	)
+}
+
+// visitAt visits in UTC start order the bookings containing the UTC timestamp, ends included.
+func (s *runSchedule) visitAt(utc int64, visit func(*scheduleEntry) bool) {
+	s.root.visitIntersecting(utc, utc, true, activeOnly(visit))
+}
+
+// ascend visits all bookings in UTC start order, stops when visit returns false.
+func (s *runSchedule) ascend(visit func(*scheduleEntry) bool) {
+	s.root.ascend(activeOnly(visit))
+}
+
+// clone returns an independent copy of the bookings.
+func (s *runSchedule) clone() runSchedule {
+	var result runSchedule
+
+	s.ascend(
+		func(entry *scheduleEntry) bool {
+			result.insertHeld(entry.TimeInterval, entry.RunID, entry.expired)
+
+			return true
+		},
+	)
+
+	return result
+}
+
+func compareScheduleEntries(a, b *scheduleEntry) int {
+	if a.utcStart != b.utcStart {
+		return ternary(a.utcStart < b.utcStart, -1, 1)
+	}
+
+	if a.sequence != b.sequence {
+		return ternary(a.sequence < b.sequence, -1, 1)
+	}
+
+	return 0
+}
+
+func (n *scheduleNode) getHeight() int {
+	if n == nil {
+		return 0
+	}
+
+	return n.height
+}
+
+func (n *scheduleNode) update() {
+	n.height = 1 + ternary(
+		n.left.getHeight() > n.right.getHeight(),
+
+		n.left.getHeight(),
+		n.right.getHeight(),
+	)
+
+	n.maxUTCEnd = n.entry.utcEnd
+
+	if n.left != nil {
+		n.maxUTCEnd = max(n.maxUTCEnd, n.left.maxUTCEnd)
+	}
+
+	if n.right != nil {
+		n.maxUTCEnd = max(n.maxUTCEnd, n.right.maxUTCEnd)
+	}
+}
+
+func (n *scheduleNode) rotateRight() *scheduleNode {
+	pivot := n.left
+
+	n.left = pivot.right
+	pivot.right = n
+
+	n.update()
+	pivot.update()
+
+	return pivot
+}
+
+func (n *scheduleNode) rotateLeft() *scheduleNode {
+	pivot := n.right
+
+	n.right = pivot.left
+	pivot.left = n
+
+	n.update()
+	pivot.update()
+
+	return pivot
+}
+
+func (n *scheduleNode) balance() *scheduleNode {
+	n.update()
+
+	switch factor := n.left.getHeight() - n.right.getHeight(); {
+	case factor > 1:
+		if n.left.left.getHeight() < n.left.right.getHeight() {
+			n.left = n.left.rotateLeft()
+		}
+
+		return n.rotateRight()
+
+	case factor < -1:
+		if n.right.right.getHeight() < n.right.left.getHeight() {
+			n.right = n.right.rotateRight()
+		}
+
+		return n.rotateLeft()
+	}
+
+	return n
+}
+
+func (n *scheduleNode) insert(entry *scheduleEntry) *scheduleNode {
+	if n == nil {
+		node := scheduleNode{
+			entry: entry,
+		}
+
+		node.update()
+
+		return &node
+	}
+
+	if compareScheduleEntries(entry, n.entry) < 0 {
+		n.left = n.left.insert(entry)
+	} else {
+		n.right = n.right.insert(entry)
+	}
+
+	return n.balance()
+}
+
+func (n *scheduleNode) delete(entry *scheduleEntry) *scheduleNode {
+	if n == nil {
+		return nil
+	}
+
+	switch comparison := compareScheduleEntries(entry, n.entry); {
+	case comparison < 0:
+		n.left = n.left.delete(entry)
+
+	case comparison > 0:
+		n.right = n.right.delete(entry)
+
+	default:
+		if n.left == nil {
+			return n.right
+		}
+
+		if n.right == nil {
+			return n.left
+		}
+
+		successor := n.right
+		for successor.left != nil {
+			successor = successor.left
+		}
+
+		n.entry = successor.entry
+		n.right = n.right.delete(successor.entry)
+	}
+
+	return n.balance()
+}
+
+// visitIntersecting visits in order the entries intersecting [from, to),
+// or [from, to] if inclusive. Returns false if visiting was stopped.
+func (n *scheduleNode) visitIntersecting(from, to int64, inclusive bool, visit func(*scheduleEntry) bool) bool {
+	if n == nil {
+		return true
+	}
+
+	// nothing in the subtree ends after from.
+	if n.maxUTCEnd < from || (!inclusive && n.maxUTCEnd == from) {
+		return true
+	}
+
+	if !n.left.visitIntersecting(from, to, inclusive, visit) {
+		return false
+	}
+
+	startsBeforeTo := ternary(inclusive, n.entry.utcStart <= to, n.entry.utcStart < to)
+
+	// the entry and the right subtree start after to.
+	if !startsBeforeTo {
+		return true
+	}
+
+	endsAfterFrom := ternary(inclusive, n.entry.utcEnd >= from, n.entry.utcEnd > from)
+
+	if endsAfterFrom && !visit(n.entry) {
+		return false
+	}
+
+	return n.right.visitIntersecting(from, to, inclusive, visit)
+}
+
+func (n *scheduleNode) ascend(visit func(*scheduleEntry) bool) bool {
+	if n == nil {
+		return true
+	}
+
+	return n.left.ascend(visit) &&
+		visit(n.entry) &&
+		n.right.ascend(visit)
+}
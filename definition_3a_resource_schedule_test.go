@@ -0,0 +1,345 @@
+package scheduler
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newTestSchedule builds a schedule out of a fixture map.
+func newTestSchedule(entries map[TimeInterval]RunID) runSchedule {
+	var result runSchedule
+
+	for interval, runID := range entries {
+		result.insert(interval, runID)
+	}
+
+	return result
+}
+
+func checkScheduleNode(t *testing.T, node *scheduleNode) (int, int64) {
+	if node == nil {
+		return 0, 0
+	}
+
+	heightLeft, maxEndLeft := checkScheduleNode(t, node.left)
+	heightRight, maxEndRight := checkScheduleNode(t, node.right)
+
+	require.LessOrEqual(t,
+		heightLeft-heightRight,
+		1,
+	)
+	require.GreaterOrEqual(t,
+		heightLeft-heightRight,
+		-1,
+	)
+
+	if node.left != nil {
+		require.Negative(t,
+			compareScheduleEntries(node.left.entry, node.entry),
+		)
+	}
+
+	if node.right != nil {
+		require.Positive(t,
+			compareScheduleEntries(node.right.entry, node.entry),
+		)
+	}
+
+	maxEnd := max(node.entry.utcEnd, max(maxEndLeft, maxEndRight))
+
+	require.Equal(t,
+		maxEnd,
+		node.maxUTCEnd,
+	)
+
+	return 1 + ternary(heightLeft > heightRight, heightLeft, heightRight),
+		maxEnd
+}
+
+func TestRunSchedule(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+
+	var schedule runSchedule
+
+	bookings := make(map[RunID]TimeInterval)
+
+	for id := RunID(1); id <= 500; id++ {
+		start := now + random.Int63n(100)*halfHour
+
+		interval := TimeInterval{
+			TimeStart:     start,
+			TimeEnd:       start + (1+random.Int63n(4))*halfHour,
+			SecondsOffset: ternary(id%2 == 0, int64(0), oneHour),
+		}
+
+		schedule.insert(interval, id)
+		bookings[id] = interval
+	}
+
+	checkScheduleNode(t, schedule.root)
+
+	for id := RunID(1); id <= 500; id = id + 3 {
+		require.True(t,
+			schedule.removeRun(id),
+		)
+
+		delete(bookings, id)
+	}
+
+	require.False(t,
+		schedule.removeRun(1),
+	)
+	require.Equal(t,
+		len(bookings),
+		schedule.Len(),
+	)
+
+	checkScheduleNode(t, schedule.root)
+
+	t.Run(
+		"1. Ascending UTC start",
+		func(t *testing.T) {
+			var previous int64
+
+			schedule.ascend(
+				func(entry *scheduleEntry) bool {
+					require.GreaterOrEqual(t,
+						entry.utcStart,
+						previous,
+					)
+
+					previous = entry.utcStart
+
+					return true
+				},
+			)
+		},
+	)
+
+	t.Run(
+		"2. Overlap queries match a linear scan",
+		func(t *testing.T) {
+			for range 100 {
+				start := now + random.Int63n(100)*halfHour - oneHour
+
+				search := TimeInterval{
+					TimeStart: start,
+					TimeEnd:   start + random.Int63n(6)*halfHour,
+				}
+
+				expected := make(map[RunID]bool)
+
+				for id, interval := range bookings {
+					if interval.Overlaps(&search) {
+						expected[id] = true
+					}
+				}
+
+				got := make(map[RunID]bool)
+
+				schedule.visitOverlapping(
+					&search,
+
+					func(entry *scheduleEntry) bool {
+						got[entry.RunID] = true
+
+						return true
+					},
+				)
+
+				require.Equal(t,
+					expected,
+					got,
+					search,
+				)
+			}
+		},
+	)
+
+	t.Run(
+		"3. Run index",
+		func(t *testing.T) {
+			require.True(t,
+				schedule.hasRun(2),
+			)
+			require.False(t,
+				schedule.hasRun(4),
+			)
+
+			intervals := schedule.getRunIntervals(2)
+			require.Equal(t,
+				[]TimeInterval{bookings[2]},
+				intervals,
+			)
+			require.True(t,
+				schedule.has(&intervals[0]),
+			)
+		},
+	)
+}
+
+// scheduleLinear is the previous map based schedule, kept for benchmark comparison.
+type scheduleLinear map[TimeInterval]RunID
+
+func (s scheduleLinear) getAvailability(searchInterval *TimeInterval) ([]TimeInterval, bool) {
+	busy := make([]TimeInterval, 0, len(s))
+
+	for interval := range s {
+		busy = append(busy, interval)
+	}
+
+	set := NewIntervalSet(searchInterval.SecondsOffset, busy...)
+
+	if !set.Overlaps(searchInterval) {
+		return nil,
+			true
+	}
+
+	return set.Complement(searchInterval).IntervalsAs(searchInterval),
+		false
+}
+
+func (s scheduleLinear) hasRun(runID RunID) bool {
+	for _, id := range s {
+		if id == runID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s scheduleLinear) getRun(atUTC int64) (RunID, bool) {
+	at := TimeInterval{
+		TimeStart: atUTC,
+		TimeEnd:   atUTC,
+	}
+
+	for interval, runID := range s {
+		if interval.Contains(&at) {
+			return runID,
+				true
+		}
+	}
+
+	return 0,
+		false
+}
+
+func newBenchmarkSchedules(size int) (*ResourceScheduled, scheduleLinear) {
+	resource := ResourceScheduled{}
+	linear := make(scheduleLinear, size)
+
+	for ix := range size {
+		interval := TimeInterval{
+			TimeStart: now + int64(ix)*oneHour,
+			TimeEnd:   now + int64(ix)*oneHour + halfHour,
+		}
+
+		resource.schedule.insert(interval, RunID(ix+1))
+		linear[interval] = RunID(ix + 1)
+	}
+
+	return &resource, linear
+}
+
+func BenchmarkGetAvailability(b *testing.B) {
+	for _, size := range []int{100, 1000, 10000} {
+		resource, linear := newBenchmarkSchedules(size)
+
+		search := TimeInterval{
+			TimeStart: now + int64(size/2)*oneHour,
+			TimeEnd:   now + int64(size/2)*oneHour + oneDay,
+		}
+
+		b.Run(
+			fmt.Sprintf("tree %d", size),
+			func(b *testing.B) {
+				for b.Loop() {
+					resource.GetAvailability(&search)
+				}
+			},
+		)
+
+		b.Run(
+			fmt.Sprintf("linear %d", size),
+			func(b *testing.B) {
+				for b.Loop() {
+					linear.getAvailability(&search)
+				}
+			},
+		)
+	}
+}
+
+func BenchmarkGetRun(b *testing.B) {
+	for _, size := range []int{100, 1000, 10000} {
+		resource, linear := newBenchmarkSchedules(size)
+
+		at := now + int64(size/2)*oneHour + 60
+
+		b.Run(
+			fmt.Sprintf("tree %d", size),
+			func(b *testing.B) {
+				for b.Loop() {
+					_, _ = resource.GetRun(at, 0)
+				}
+			},
+		)
+
+		b.Run(
+			fmt.Sprintf("linear %d", size),
+			func(b *testing.B) {
+				for b.Loop() {
+					_, _ = linear.getRun(at)
+				}
+			},
+		)
+	}
+}
+
+func BenchmarkAddRun(b *testing.B) {
+	for _, size := range []int{100, 1000, 10000} {
+		resource, linear := newBenchmarkSchedules(size)
+
+		interval := TimeInterval{
+			TimeStart: now + int64(size/2)*oneHour + halfHour,
+			TimeEnd:   now + int64(size/2)*oneHour + oneHour,
+		}
+
+		b.Run(
+			fmt.Sprintf("tree %d", size),
+			func(b *testing.B) {
+				for b.Loop() {
+					_, _ = resource.AddRun(
+						b.Context(),
+						&ParamsRun{
+							TimeInterval: interval,
+							ID:           RunID(size + 1),
+						},
+					)
+
+					resource.schedule.removeRun(RunID(size + 1))
+				}
+			},
+		)
+
+		b.Run(
+			fmt.Sprintf("linear %d", size),
+			func(b *testing.B) {
+				for b.Loop() {
+					if !linear.hasRun(RunID(size + 1)) {
+						if _, available := linear.getAvailability(&interval); available {
+							linear[interval] = RunID(size + 1)
+						}
+					}
+
+					delete(linear, interval)
+				}
+			},
+		)
+	}
+}
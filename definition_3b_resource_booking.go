@@ -0,0 +1,83 @@
+package scheduler
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+// ErrRunConflict is returned when a run cannot be booked as the resource is not available.
+type ErrRunConflict struct {
+	Interval   TimeInterval
+	RunID      RunID
+	ResourceID int
+}
+
+func (e ErrRunConflict) Error() string {
+	return fmt.Sprintf(
+		"run %d conflicts on resource %d in [%d-%d]",
+
+		e.RunID,
+		e.ResourceID,
+		e.Interval.TimeStart,
+		e.Interval.TimeEnd,
+	)
+}
+
+// lockResources write locks the distinct resources, always in the same order
+// so concurrent callers cannot deadlock. Returns the unlock.
+func lockResources(resources []*ResourceScheduled) func() {
+	ordered := slices.Clone(resources)
+
+	slices.SortFunc(
+		ordered,
+		func(a, b *ResourceScheduled) int {
+			if a.ID != b.ID {
+				return cmp.Compare(a.ID, b.ID)
+			}
+
+			return cmp.Compare(a.getSequence(), b.getSequence())
+		},
+	)
+
+	ordered = slices.Compact(ordered)
+
+	for _, resource := range ordered {
+		resource.mu.Lock()
+	}
+
+	return func() {
+		for _, resource := range slices.Backward(ordered) {
+			resource.mu.Unlock()
+		}
+	}
+}
+
+// bookResources books the interval for the run on all resources or on none.
+// Availability is checked again under the resource locks, so a booking cannot overlap
+// one committed meanwhile by another caller.
+func bookResources(resources []*ResourceScheduled, interval *TimeInterval, runID RunID) error {
+	return bookResourcesHeld(resources, interval, runID, nil)
+}
+
+// bookResourcesHeld books as bookResources, for a hold if expired is not nil.
+func bookResourcesHeld(resources []*ResourceScheduled, interval *TimeInterval, runID RunID, expired func() bool) error {
+	unlock := lockResources(resources)
+	defer unlock()
+
+	for _, resource := range resources {
+		if _, available := resource.getAvailability(interval); !available {
+			return ErrRunConflict{
+				Interval:   *interval,
+				RunID:      runID,
+				ResourceID: resource.ID,
+			}
+		}
+	}
+
+	for _, resource := range resources {
+		resource.schedule.insertHeld(*interval, runID, expired)
+	}
+
+	return nil
+}
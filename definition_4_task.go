@@ -1,9 +1,12 @@
 package scheduler
 
 type RunDependency struct {
-	PreferredResourceID int
+	PreferredResourceID int // zero for no preference
 	ResourceType        uint8
 	ResourceQuantity    uint8
+
+	PreferenceRequired bool  // only options with the preferred resource, otherwise preferred ones rank higher
+	PreferencePenalty  Money // added to the ranking cost of options without the preferred resource
 }
 
 type RunLoad struct {
@@ -0,0 +1,531 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	goerrors "github.com/TudorHulban/go-errors"
+)
+
+// _MaxOccurrences caps expansion of a rule, protects against runaway rules.
+const _MaxOccurrences = 10000
+
+type Frequency uint8
+
+const (
+	FrequencyDaily Frequency = iota + 1
+	FrequencyWeekly
+	FrequencyMonthly
+)
+
+func (f Frequency) String() string {
+	switch f {
+	case FrequencyDaily:
+		return "DAILY"
+	case FrequencyWeekly:
+		return "WEEKLY"
+	case FrequencyMonthly:
+		return "MONTHLY"
+	}
+
+	return "UNKNOWN"
+}
+
+// RecurrenceDate is wall clock in the representation of the first occurrence,
+// unless IsUTC, case in which it is compared against UTC.
+type RecurrenceDate struct {
+	Timestamp int64
+	IsUTC     bool
+}
+
+func (d RecurrenceDate) matches(occurrence *TimeInterval) bool {
+	if d.IsUTC {
+		return occurrence.GetUTCTimeStart() == d.Timestamp
+	}
+
+	return occurrence.TimeStart == d.Timestamp
+}
+
+func (d RecurrenceDate) precedes(occurrence *TimeInterval) bool {
+	if d.IsUTC {
+		return d.Timestamp < occurrence.GetUTCTimeStart()
+	}
+
+	return d.Timestamp < occurrence.TimeStart
+}
+
+// Recurrence is the subset of RFC 5545 recurrence used for runs:
+// FREQ, INTERVAL, BYDAY, COUNT, UNTIL and EXDATE.
+// Weeks start on Monday.
+type Recurrence struct {
+	Frequency Frequency
+	Interval  int
+	ByDay     []time.Weekday
+	Count     int
+	Until     *RecurrenceDate
+	ExDates   []RecurrenceDate
+}
+
+var _weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// ParseRecurrence accepts a RRULE, optionally prefixed with "RRULE:",
+// and optionally followed on new lines by EXDATE properties, with an optional TZID.
+// Ex.
+//
+//	RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=10
+//	EXDATE:20260406T090000
+func ParseRecurrence(rule string) (*Recurrence, error) {
+	result := Recurrence{
+		Interval: 1,
+	}
+
+	var hasRule bool
+
+	for _, line := range strings.Split(strings.ReplaceAll(rule, "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		if strings.HasPrefix(strings.ToUpper(line), "EXDATE") {
+			exDates, errParse := parseExDates(line)
+			if errParse != nil {
+				return nil,
+					errParse
+			}
+
+			result.ExDates = append(result.ExDates, exDates...)
+
+			continue
+		}
+
+		if errParse := result.parseRule(strings.TrimPrefix(line, "RRULE:")); errParse != nil {
+			return nil,
+				errParse
+		}
+
+		hasRule = true
+	}
+
+	if !hasRule || result.Frequency == 0 {
+		return nil,
+			goerrors.ErrValidation{
+				Caller: "ParseRecurrence",
+				Issue: goerrors.ErrNilInput{
+					InputName: "FREQ",
+				},
+			}
+	}
+
+	if result.Count > 0 && result.Until != nil {
+		return nil,
+			goerrors.ErrInvalidInput{
+				Caller:    "ParseRecurrence",
+				InputName: "COUNT",
+				Issue:     errors.New("COUNT and UNTIL are mutually exclusive"),
+			}
+	}
+
+	return &result,
+		nil
+}
+
+func (r *Recurrence) parseRule(rule string) error {
+	for _, part := range strings.Split(rule, ";") {
+		name, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			return goerrors.ErrInvalidInput{
+				Caller:     "ParseRecurrence",
+				InputName:  "RRULE",
+				InputValue: part,
+			}
+		}
+
+		switch strings.ToUpper(name) {
+		case "FREQ":
+			switch strings.ToUpper(value) {
+			case "DAILY":
+				r.Frequency = FrequencyDaily
+			case "WEEKLY":
+				r.Frequency = FrequencyWeekly
+			case "MONTHLY":
+				r.Frequency = FrequencyMonthly
+			default:
+				return goerrors.ErrInvalidInput{
+					Caller:     "ParseRecurrence",
+					InputName:  "FREQ",
+					InputValue: value,
+					Issue:      errors.New("supported: DAILY, WEEKLY, MONTHLY"),
+				}
+			}
+
+		case "INTERVAL":
+			interval, errConv := strconv.Atoi(value)
+			if errConv != nil || interval <= 0 {
+				return goerrors.ErrInvalidInput{
+					Caller:     "ParseRecurrence",
+					InputName:  "INTERVAL",
+					InputValue: value,
+				}
+			}
+
+			r.Interval = interval
+
+		case "COUNT":
+			count, errConv := strconv.Atoi(value)
+			if errConv != nil || count <= 0 {
+				return goerrors.ErrInvalidInput{
+					Caller:     "ParseRecurrence",
+					InputName:  "COUNT",
+					InputValue: value,
+				}
+			}
+
+			r.Count = count
+
+		case "UNTIL":
+			until, errParse := parseRecurrenceDate(value)
+			if errParse != nil {
+				return errParse
+			}
+
+			if len(value) == len("20060102") {
+				until.Timestamp = until.Timestamp + 86400 - 1 // whole day included
+			}
+
+			r.Until = until
+
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				weekday, exists := _weekdays[strings.ToUpper(strings.TrimSpace(day))]
+				if !exists {
+					return goerrors.ErrInvalidInput{
+						Caller:     "ParseRecurrence",
+						InputName:  "BYDAY",
+						InputValue: day,
+						Issue:      errors.New("ordinal weekdays are not supported"),
+					}
+				}
+
+				if !slices.Contains(r.ByDay, weekday) {
+					r.ByDay = append(r.ByDay, weekday)
+				}
+			}
+
+		case "WKST":
+			if strings.ToUpper(value) != "MO" {
+				return goerrors.ErrInvalidInput{
+					Caller:     "ParseRecurrence",
+					InputName:  "WKST",
+					InputValue: value,
+					Issue:      errors.New("weeks start on Monday"),
+				}
+			}
+
+		default:
+			return goerrors.ErrInvalidInput{
+				Caller:     "ParseRecurrence",
+				InputName:  name,
+				InputValue: value,
+				Issue:      errors.New("unsupported rule part"),
+			}
+		}
+	}
+
+	if r.Frequency == FrequencyMonthly && len(r.ByDay) > 0 {
+		return goerrors.ErrInvalidInput{
+			Caller:    "ParseRecurrence",
+			InputName: "BYDAY",
+			Issue:     errors.New("BYDAY is not supported with MONTHLY"),
+		}
+	}
+
+	return nil
+}
+
+// parseExDates parses EXDATE[;params]:value[,value].
+// With a TZID parameter the wall clock values are of that zone, kept as UTC.
+func parseExDates(line string) ([]RecurrenceDate, error) {
+	property, values, found := strings.Cut(line, ":")
+	if !found {
+		return nil,
+			goerrors.ErrInvalidInput{
+				Caller:     "ParseRecurrence",
+				InputName:  "EXDATE",
+				InputValue: line,
+			}
+	}
+
+	var zone *time.Location
+
+	for _, param := range strings.Split(property, ";")[1:] {
+		name, value, _ := strings.Cut(param, "=")
+		if !strings.EqualFold(name, "TZID") {
+			continue
+		}
+
+		loaded, errLoad := time.LoadLocation(value)
+		if errLoad != nil {
+			return nil,
+				goerrors.ErrInvalidInput{
+					Caller:     "ParseRecurrence",
+					InputName:  "TZID",
+					InputValue: value,
+					Issue:      errLoad,
+				}
+		}
+
+		zone = loaded
+	}
+
+	result := make([]RecurrenceDate, 0)
+
+	for _, value := range strings.Split(values, ",") {
+		date, errParse := parseRecurrenceDate(strings.TrimSpace(value))
+		if errParse != nil {
+			return nil,
+				errParse
+		}
+
+		if zone != nil && !date.IsUTC {
+			date.Timestamp = wallClockToUTC(date.Timestamp, zone)
+			date.IsUTC = true
+		}
+
+		result = append(result, *date)
+	}
+
+	return result,
+		nil
+}
+
+// parseRecurrenceDate accepts 20060102, 20060102T150405 and 20060102T150405Z.
+func parseRecurrenceDate(value string) (*RecurrenceDate, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405", "20060102"} {
+		parsed, errParse := time.Parse(layout, value)
+		if errParse != nil {
+			continue
+		}
+
+		return &RecurrenceDate{
+				Timestamp: parsed.Unix(),
+				IsUTC:     strings.HasSuffix(layout, "Z"),
+			},
+			nil
+	}
+
+	return nil,
+		goerrors.ErrInvalidInput{
+			Caller:     "ParseRecurrence",
+			InputName:  "date",
+			InputValue: value,
+		}
+}
+
+func (r *Recurrence) String() string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("RRULE:FREQ=%s", r.Frequency))
+
+	if r.Interval > 1 {
+		sb.WriteString(fmt.Sprintf(";INTERVAL=%d", r.Interval))
+	}
+
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+
+		for ix, weekday := range r.ByDay {
+			days[ix] = strings.ToUpper(weekday.String()[:2])
+		}
+
+		sb.WriteString(";BYDAY=" + strings.Join(days, ","))
+	}
+
+	if r.Count > 0 {
+		sb.WriteString(fmt.Sprintf(";COUNT=%d", r.Count))
+	}
+
+	if r.Until != nil {
+		sb.WriteString(";UNTIL=" + r.Until.String())
+	}
+
+	if len(r.ExDates) > 0 {
+		exDates := make([]string, len(r.ExDates))
+
+		for ix, exDate := range r.ExDates {
+			exDates[ix] = exDate.String()
+		}
+
+		sb.WriteString("\nEXDATE:" + strings.Join(exDates, ","))
+	}
+
+	return sb.String()
+}
+
+func (d RecurrenceDate) String() string {
+	return time.Unix(d.Timestamp, 0).UTC().Format(
+		ternary(d.IsUTC, "20060102T150405Z", "20060102T150405"),
+	)
+}
+
+type ParamsExpand struct {
+	First TimeInterval // first occurrence, DTSTART
+
+	// Horizon stops expansion, as wall clock of the first occurrence.
+	// Needed when the rule has neither COUNT nor UNTIL.
+	Horizon int64
+}
+
+// Expand returns the occurrences in chronological order, first one included if it matches the rule.
+// Occurrences keep the wall clock time and duration of the first one, also across DST changes
+// when the first occurrence carries a time zone.
+func (r *Recurrence) Expand(params *ParamsExpand) ([]TimeInterval, error) {
+	if r.Count == 0 && r.Until == nil && params.Horizon == 0 {
+		return nil,
+			goerrors.ErrValidation{
+				Caller: "Expand",
+				Issue: goerrors.ErrNilInput{
+					InputName: "COUNT, UNTIL or Horizon",
+				},
+			}
+	}
+
+	if params.First.TimeEnd < params.First.TimeStart {
+		return nil,
+			goerrors.ErrInvalidInput{
+				Caller:     "Expand",
+				InputName:  "First",
+				InputValue: params.First,
+			}
+	}
+
+	interval := max(int64(r.Interval), 1)
+	wallDuration := params.First.TimeEnd - params.First.TimeStart
+	wallStart := time.Unix(params.First.TimeStart, 0).UTC()
+
+	result := make([]TimeInterval, 0)
+
+	var generated int
+
+	// emit returns false when expansion should stop.
+	emit := func(candidate time.Time) bool {
+		occurrence := TimeInterval{
+			TimeStart:     candidate.Unix(),
+			TimeEnd:       candidate.Unix() + wallDuration,
+			SecondsOffset: params.First.SecondsOffset,
+			TimeZone:      params.First.TimeZone,
+		}
+
+		if r.Until != nil && r.Until.precedes(&occurrence) {
+			return false
+		}
+
+		if params.Horizon > 0 && occurrence.TimeStart > params.Horizon {
+			return false
+		}
+
+		generated++
+
+		excluded := slices.ContainsFunc(
+			r.ExDates,
+			func(exDate RecurrenceDate) bool {
+				return exDate.matches(&occurrence)
+			},
+		)
+
+		if !excluded {
+			result = append(result, occurrence)
+		}
+
+		return (r.Count == 0 || generated < r.Count) && generated < _MaxOccurrences
+	}
+
+	switch r.Frequency {
+	case FrequencyDaily:
+		for period := int64(0); ; period++ {
+			candidate := wallStart.AddDate(0, 0, int(period*interval))
+
+			if len(r.ByDay) > 0 && !slices.Contains(r.ByDay, candidate.Weekday()) {
+				if period > 7*_MaxOccurrences {
+					break
+				}
+
+				continue
+			}
+
+			if !emit(candidate) {
+				break
+			}
+		}
+
+	case FrequencyWeekly:
+		byDay := r.ByDay
+		if len(byDay) == 0 {
+			byDay = []time.Weekday{wallStart.Weekday()}
+		}
+
+		weekStart := wallStart.AddDate(0, 0, -daysSinceMonday(wallStart.Weekday()))
+
+	weeks:
+		for period := int64(0); ; period++ {
+			week := weekStart.AddDate(0, 0, int(7*period*interval))
+
+			for day := 0; day < 7; day++ {
+				candidate := week.AddDate(0, 0, day)
+
+				if candidate.Before(wallStart) || !slices.Contains(byDay, candidate.Weekday()) {
+					continue
+				}
+
+				if !emit(candidate) {
+					break weeks
+				}
+			}
+		}
+
+	case FrequencyMonthly:
+		for period := int64(0); ; period++ {
+			candidate := wallStart.AddDate(0, int(period*interval), 0)
+
+			// months without the day of DTSTART are skipped, RFC 5545.
+			if candidate.Day() != wallStart.Day() {
+				if period*interval > 12*_MaxOccurrences {
+					break
+				}
+
+				continue
+			}
+
+			if !emit(candidate) {
+				break
+			}
+		}
+
+	default:
+		return nil,
+			goerrors.ErrInvalidInput{
+				Caller:     "Expand",
+				InputName:  "Frequency",
+				InputValue: r.Frequency,
+			}
+	}
+
+	return result,
+		nil
+}
+
+func daysSinceMonday(weekday time.Weekday) int {
+	return (int(weekday) + 6) % 7
+}
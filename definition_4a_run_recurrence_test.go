@@ -0,0 +1,224 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRecurrence(t *testing.T) {
+	recurrence, errParse := ParseRecurrence(
+		"RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=10\nEXDATE:20260406T090000,20260408T090000Z",
+	)
+	require.NoError(t, errParse)
+	require.Equal(t,
+		FrequencyWeekly,
+		recurrence.Frequency,
+	)
+	require.Equal(t,
+		2,
+		recurrence.Interval,
+	)
+	require.Equal(t,
+		[]time.Weekday{time.Monday, time.Wednesday},
+		recurrence.ByDay,
+	)
+	require.Equal(t,
+		10,
+		recurrence.Count,
+	)
+	require.Len(t,
+		recurrence.ExDates,
+		2,
+	)
+	require.True(t, recurrence.ExDates[1].IsUTC)
+
+	for _, rule := range []string{
+		"",
+		"FREQ=YEARLY",
+		"FREQ=DAILY;COUNT=0",
+		"FREQ=DAILY;COUNT=2;UNTIL=20260401",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=MONTHLY;BYDAY=MO",
+		"FREQ=DAILY;BYSETPOS=1",
+	} {
+		_, errParseInvalid := ParseRecurrence(rule)
+		require.Error(t, errParseInvalid, rule)
+	}
+}
+
+func TestExpandRecurrence(t *testing.T) {
+	// Monday 2026-03-02 09:00 - 10:00, wall clock.
+	first := TimeInterval{
+		TimeStart: time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC).Unix(),
+		TimeEnd:   time.Date(2026, time.March, 2, 10, 0, 0, 0, time.UTC).Unix(),
+	}
+
+	wallClock := func(month time.Month, day, hour int) int64 {
+		return time.Date(2026, month, day, hour, 0, 0, 0, time.UTC).Unix()
+	}
+
+	tests := []struct {
+		name     string
+		rule     string
+		horizon  int64
+		expected []int64
+	}{
+		{
+			name: "1. Daily with count",
+			rule: "FREQ=DAILY;COUNT=3",
+			expected: []int64{
+				wallClock(time.March, 2, 9),
+				wallClock(time.March, 3, 9),
+				wallClock(time.March, 4, 9),
+			},
+		},
+		{
+			name: "2. Every other week on Monday and Wednesday, exdate counted",
+			rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=4\nEXDATE:20260304T090000",
+			expected: []int64{
+				wallClock(time.March, 2, 9),
+				wallClock(time.March, 16, 9),
+				wallClock(time.March, 18, 9),
+			},
+		},
+		{
+			name: "3. Weekly until date, inclusive",
+			rule: "FREQ=WEEKLY;UNTIL=20260316",
+			expected: []int64{
+				wallClock(time.March, 2, 9),
+				wallClock(time.March, 9, 9),
+				wallClock(time.March, 16, 9),
+			},
+		},
+		{
+			name:    "4. Daily on weekdays up to horizon",
+			rule:    "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR",
+			horizon: wallClock(time.March, 9, 9),
+			expected: []int64{
+				wallClock(time.March, 2, 9),
+				wallClock(time.March, 3, 9),
+				wallClock(time.March, 4, 9),
+				wallClock(time.March, 5, 9),
+				wallClock(time.March, 6, 9),
+				wallClock(time.March, 9, 9),
+			},
+		},
+		{
+			name: "5. Monthly",
+			rule: "FREQ=MONTHLY;COUNT=2",
+			expected: []int64{
+				wallClock(time.March, 2, 9),
+				wallClock(time.April, 2, 9),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name,
+			func(t *testing.T) {
+				recurrence, errParse := ParseRecurrence(tt.rule)
+				require.NoError(t, errParse)
+
+				occurrences, errExpand := recurrence.Expand(
+					&ParamsExpand{
+						First:   first,
+						Horizon: tt.horizon,
+					},
+				)
+				require.NoError(t, errExpand)
+
+				starts := make([]int64, len(occurrences))
+
+				for ix, occurrence := range occurrences {
+					starts[ix] = occurrence.TimeStart
+
+					require.EqualValues(t,
+						oneHour,
+						occurrence.TimeEnd-occurrence.TimeStart,
+					)
+				}
+
+				require.Equal(t,
+					tt.expected,
+					starts,
+				)
+			},
+		)
+	}
+
+	t.Run(
+		"6. Unbounded rule needs horizon",
+		func(t *testing.T) {
+			recurrence, errParse := ParseRecurrence("FREQ=DAILY")
+			require.NoError(t, errParse)
+
+			_, errExpand := recurrence.Expand(
+				&ParamsExpand{
+					First: first,
+				},
+			)
+			require.Error(t, errExpand)
+		},
+	)
+
+	t.Run(
+		"7. Wall clock kept across DST",
+		func(t *testing.T) {
+			bucharest, errLoad := time.LoadLocation("Europe/Bucharest")
+			require.NoError(t, errLoad)
+
+			recurrence, errParse := ParseRecurrence("FREQ=WEEKLY;COUNT=2")
+			require.NoError(t, errParse)
+
+			occurrences, errExpand := recurrence.Expand(
+				&ParamsExpand{
+					First: NewTimeIntervalIn(
+						bucharest,
+
+						time.Date(2026, time.March, 23, 9, 0, 0, 0, bucharest),
+						time.Date(2026, time.March, 23, 10, 0, 0, 0, bucharest),
+					),
+				},
+			)
+			require.NoError(t, errExpand)
+			require.Len(t,
+				occurrences,
+				2,
+			)
+			require.EqualValues(t,
+				time.Date(2026, time.March, 30, 9, 0, 0, 0, bucharest).Unix(),
+				occurrences[1].GetUTCTimeStart(),
+			)
+		},
+	)
+
+	t.Run(
+		"8. Exdate in the zone of its TZID",
+		func(t *testing.T) {
+			// 11:00 in Bucharest, UTC+2, is the 09:00 UTC occurrence
+			recurrence, errParse := ParseRecurrence("FREQ=DAILY;COUNT=3\nEXDATE;TZID=Europe/Bucharest:20260303T110000")
+			require.NoError(t, errParse)
+
+			occurrences, errExpand := recurrence.Expand(
+				&ParamsExpand{
+					First: first,
+				},
+			)
+			require.NoError(t, errExpand)
+			require.Len(t,
+				occurrences,
+				2,
+			)
+			require.Equal(t,
+				wallClock(time.March, 4, 9),
+				occurrences[1].TimeStart,
+			)
+
+			_, errZone := ParseRecurrence("FREQ=DAILY;COUNT=3\nEXDATE;TZID=Mars/Olympus:20260303T110000")
+			require.Error(t, errZone)
+		},
+	)
+}
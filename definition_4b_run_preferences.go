@@ -0,0 +1,196 @@
+package scheduler
+
+import (
+	"cmp"
+	"slices"
+)
+
+// resourcePreferences are the preferred resources of a run, by resource ID.
+// A nil value has no preferences.
+type resourcePreferences struct {
+	required  map[int]bool
+	penalties map[int]Money // soft preferences, penalty when not selected
+}
+
+func (r *Run) getResourcePreferences() *resourcePreferences {
+	var result *resourcePreferences
+
+	for _, dependency := range r.Dependencies {
+		if dependency.PreferredResourceID == 0 {
+			continue
+		}
+
+		if result == nil {
+			result = &resourcePreferences{
+				required:  make(map[int]bool),
+				penalties: make(map[int]Money),
+			}
+		}
+
+		if dependency.PreferenceRequired {
+			result.required[dependency.PreferredResourceID] = true
+
+			continue
+		}
+
+		result.penalties[dependency.PreferredResourceID] = result.penalties[dependency.PreferredResourceID].
+			add(dependency.PreferencePenalty)
+	}
+
+	return result
+}
+
+func (p *resourcePreferences) isPreferred(resource *ResourceScheduled) bool {
+	if p == nil {
+		return false
+	}
+
+	_, isSoft := p.penalties[resource.ID]
+
+	return isSoft || p.required[resource.ID]
+}
+
+func (p *resourcePreferences) isRequired(resource *ResourceScheduled) bool {
+	return p != nil && p.required[resource.ID]
+}
+
+// getBonus is what selecting the resource saves in ranking cost, the penalty of a soft preference.
+func (p *resourcePreferences) getBonus(resource *ResourceScheduled) Money {
+	if p == nil {
+		return Money{}
+	}
+
+	return p.penalties[resource.ID]
+}
+
+// hasRequired returns true if all required resources are selected.
+func (p *resourcePreferences) hasRequired(resources []*ResourceScheduled) bool {
+	if p == nil {
+		return true
+	}
+
+	for resourceID := range p.required {
+		if !slices.ContainsFunc(
+			resources,
+			func(resource *ResourceScheduled) bool {
+				return resource.ID == resourceID
+			},
+		) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// getPenalty returns the penalties of the soft preferences not selected.
+func (p *resourcePreferences) getPenalty(resources []*ResourceScheduled) Money {
+	if p == nil {
+		return Money{}
+	}
+
+	var result Money
+
+	for resourceID, penalty := range p.penalties {
+		if !slices.ContainsFunc(
+			resources,
+			func(resource *ResourceScheduled) bool {
+				return resource.ID == resourceID
+			},
+		) {
+			result = result.add(penalty)
+		}
+	}
+
+	return result
+}
+
+// sortByRank orders the required resources first, then cheapest first counting preference bonus as saving.
+// Ties go to preferred resources, then to the cheaper ones. The cost of each resource is taken once.
+func (p *resourcePreferences) sortByRank(resources []*ResourceScheduled, getCost func(*ResourceScheduled) Money) {
+	type rankedResource struct {
+		resource *ResourceScheduled
+
+		cost int64
+		rank int64 // cost less bonus
+
+		isRequired  bool
+		isPreferred bool
+	}
+
+	ranked := make([]rankedResource, len(resources))
+
+	for ix, resource := range resources {
+		cost := getCost(resource).Amount
+
+		ranked[ix] = rankedResource{
+			resource: resource,
+
+			cost: cost,
+			rank: cost - p.getBonus(resource).Amount,
+
+			isRequired:  p.isRequired(resource),
+			isPreferred: p.isPreferred(resource),
+		}
+	}
+
+	slices.SortStableFunc(
+		ranked,
+		func(a, b rankedResource) int {
+			if a.isRequired != b.isRequired {
+				return ternary(a.isRequired, -1, 1)
+			}
+
+			if a.rank != b.rank {
+				return cmp.Compare(a.rank, b.rank)
+			}
+
+			if a.isPreferred != b.isPreferred {
+				return ternary(a.isPreferred, -1, 1)
+			}
+
+			return cmp.Compare(a.cost, b.cost)
+		},
+	)
+
+	for ix := range ranked {
+		resources[ix] = ranked[ix].resource
+	}
+}
+
+// sortPreferredFirst orders the preferred resources first, required ones before, order kept otherwise.
+func (p *resourcePreferences) sortPreferredFirst(resources []*ResourceScheduled) {
+	if p == nil {
+		return
+	}
+
+	slices.SortStableFunc(
+		resources,
+		func(a, b *ResourceScheduled) int {
+			return p.getPreferenceRank(a) - p.getPreferenceRank(b)
+		},
+	)
+}
+
+// sortRequiredFirst orders the required resources first, order kept otherwise.
+func (p *resourcePreferences) sortRequiredFirst(resources []*ResourceScheduled) {
+	if p == nil {
+		return
+	}
+
+	slices.SortStableFunc(
+		resources,
+		func(a, b *ResourceScheduled) int {
+			return ternary(p.required[a.ID], 0, 1) - ternary(p.required[b.ID], 0, 1)
+		},
+	)
+}
+
+// getPreferenceRank is 0 for required, 1 for preferred and 2 for other resources.
+func (p *resourcePreferences) getPreferenceRank(resource *ResourceScheduled) int {
+	if p.required[resource.ID] {
+		return 0
+	}
+
+	return ternary(p.isPreferred(resource), 1, 2)
+}
@@ -0,0 +1,252 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourcePreferences(t *testing.T) {
+	newResources := func() []*ResourceScheduled {
+		result := make([]*ResourceScheduled, 0)
+
+		for id, cost := range map[int]int64{1: 1, 2: 2, 7: 3} {
+			result = append(result, newTestResource(id, 1, cost, nil))
+		}
+
+		return result
+	}
+
+	interval := TimeInterval{
+		TimeStart: now,
+		TimeEnd:   now + oneHour,
+	}
+
+	tests := []struct {
+		name       string
+		dependency RunDependency
+		busy7      bool
+
+		expectedScheduled    bool
+		expectedWhenCanStart int64
+		expectedCost         int64
+		expectedResourceID   int
+	}{
+		{
+			name:               "1. No preference, cheapest",
+			expectedScheduled:  true,
+			expectedCost:       1,
+			expectedResourceID: 1,
+		},
+		{
+			name: "2. Preferred, penalty greater than cost difference",
+			dependency: RunDependency{
+				PreferredResourceID: 7,
+				PreferencePenalty:   Money{Amount: 5},
+			},
+			expectedScheduled:  true,
+			expectedCost:       3,
+			expectedResourceID: 7,
+		},
+		{
+			name: "3. Preferred, penalty less than cost difference",
+			dependency: RunDependency{
+				PreferredResourceID: 7,
+				PreferencePenalty:   Money{Amount: 1},
+			},
+			expectedScheduled:  true,
+			expectedCost:       1,
+			expectedResourceID: 1,
+		},
+		{
+			name: "4. Required",
+			dependency: RunDependency{
+				PreferredResourceID: 7,
+				PreferenceRequired:  true,
+			},
+			expectedScheduled:  true,
+			expectedCost:       3,
+			expectedResourceID: 7,
+		},
+		{
+			name: "5. Required busy, fallback waits for it",
+			dependency: RunDependency{
+				PreferredResourceID: 7,
+				PreferenceRequired:  true,
+			},
+			busy7:                true,
+			expectedWhenCanStart: now + oneHour,
+			expectedCost:         3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name,
+			func(t *testing.T) {
+				resources := newResources()
+
+				if tt.busy7 {
+					for _, resource := range resources {
+						if resource.ID == 7 {
+							resource.schedule = newTestSchedule(map[TimeInterval]RunID{
+								interval: Maintenance,
+							})
+						}
+					}
+				}
+
+				location := newTestLocation(t, resources...)
+
+				response, errSchedule := location.CanSchedule(
+					&ParamsCanRun{
+						TimeInterval: interval,
+						TaskRun:      newTestRunPreferring(1, tt.dependency),
+					},
+				)
+				require.NoError(t, errSchedule)
+				require.Equal(t,
+					tt.expectedScheduled,
+					response.WasScheduled,
+				)
+				require.Equal(t,
+					tt.expectedCost,
+					response.Cost.Amount,
+				)
+
+				if !tt.expectedScheduled {
+					require.Equal(t,
+						tt.expectedWhenCanStart,
+						response.WhenCanStart,
+					)
+
+					return
+				}
+
+				for _, resource := range resources {
+					require.Equal(t,
+						resource.ID == tt.expectedResourceID,
+						resource.schedule.hasRun(1),
+						resource.ID,
+					)
+				}
+			},
+		)
+	}
+
+	t.Run(
+		"6. Options",
+		func(t *testing.T) {
+			location := newTestLocation(t, newResources()...)
+
+			paramsRequired := ParamsCanRun{
+				TimeInterval: TimeInterval{
+					TimeStart: now,
+					TimeEnd:   now + 2*oneHour,
+				},
+				TaskRun: newTestRunPreferring(
+					1,
+					RunDependency{
+						PreferredResourceID: 7,
+						PreferenceRequired:  true,
+					},
+				),
+				AllPossibilities: true,
+			}
+
+			options, errGet := location.GetSchedulingOptions(&paramsRequired)
+			require.NoError(t, errGet)
+			require.Len(t,
+				options,
+				2,
+			)
+
+			for _, option := range options {
+				require.Equal(t,
+					7,
+					option.SelectedResources[0].ID,
+				)
+			}
+
+			loco := Loco{
+				ID:   1,
+				Name: t.Name(),
+
+				Resources: ResourcesPerType{
+					1: newResources(),
+				},
+			}
+
+			optionsLoco, errGetLoco := loco.GetAllSchedulingOptions(&paramsRequired)
+			require.NoError(t, errGetLoco)
+			require.Len(t,
+				optionsLoco,
+				2,
+			)
+
+			paramsPreferred := paramsRequired
+			paramsPreferred.TaskRun = newTestRunPreferring(
+				1,
+				RunDependency{
+					PreferredResourceID: 2,
+					PreferencePenalty:   Money{Amount: 1},
+				},
+			)
+
+			optionsLocoPreferred, errGetLocoPreferred := loco.GetAllSchedulingOptions(&paramsPreferred)
+			require.NoError(t, errGetLocoPreferred)
+			require.Len(t,
+				optionsLocoPreferred,
+				6,
+			)
+			require.Equal(t,
+				2,
+				optionsLocoPreferred[0].Resources[1][0].ID,
+			)
+
+			optionsLocoSimple, errGetLocoSimple := loco.GetSchedulingOptions(&paramsPreferred)
+			require.NoError(t, errGetLocoSimple)
+			require.Equal(t,
+				2,
+				optionsLocoSimple[0].Resources[1][0].ID,
+			)
+		},
+	)
+}
+
+func TestSortByRank(t *testing.T) {
+	resources := []*ResourceScheduled{
+		newTestResource(1, 1, 3, nil),
+		newTestResource(2, 1, -5, nil), // required, a credit
+		newTestResource(3, 1, 1, nil),  // preferred
+		newTestResource(4, 1, 0, nil),
+	}
+
+	preferences := resourcePreferences{
+		required: map[int]bool{2: true},
+		penalties: map[int]Money{
+			3: {Amount: 5},
+		},
+	}
+
+	var costed int
+
+	preferences.sortByRank(
+		resources,
+		func(resource *ResourceScheduled) Money {
+			costed++
+
+			return resource.CostPerLoadUnit[1]
+		},
+	)
+
+	require.Equal(t,
+		[]int{2, 3, 4, 1},
+		[]int{resources[0].ID, resources[1].ID, resources[2].ID, resources[3].ID},
+	)
+	require.Equal(t,
+		len(resources),
+		costed,
+		"cost taken once per resource",
+	)
+}
@@ -0,0 +1,217 @@
+package scheduler
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+)
+
+// SelectionContext is what a strategy knows when ranking resources.
+type SelectionContext struct {
+	Run  *Run
+	Slot TimeInterval // candidate interval, in its offset
+
+	// Cost is the cost the scheduler ranks the resource at.
+	Cost func(*ResourceScheduled) Money
+
+	preferences *resourcePreferences
+}
+
+// Selection is a candidate choice of resources for a start.
+type Selection struct {
+	Resources    []*ResourceScheduled
+	WhenCanStart int64
+	Cost         Money // ranking cost, with the penalties of not selected preferred resources
+}
+
+// SelectionStrategy decides which resources a run gets among the available ones.
+// Required preferred resources should be ranked first, otherwise the slot is dropped.
+type SelectionStrategy interface {
+	// Rank orders, best first, the available resources of one type. The first needed ones are selected.
+	Rank(selection *SelectionContext, resources []*ResourceScheduled)
+
+	// Compare orders candidate selections of different starts, negative if a is better.
+	Compare(a, b *Selection) int
+}
+
+// SelectionObserver is implemented by strategies keeping state over the bookings made.
+type SelectionObserver interface {
+	Selected(resources []*ResourceScheduled)
+}
+
+func compareEarliest(a, b *Selection) int {
+	if a.WhenCanStart != b.WhenCanStart {
+		return cmp.Compare(a.WhenCanStart, b.WhenCanStart)
+	}
+
+	return compareMoney(a.Cost, b.Cost)
+}
+
+// Cheapest selects the lowest cost resources and the cheapest start, earliest for same cost.
+// Default for Location.
+type Cheapest struct{}
+
+var _ SelectionStrategy = Cheapest{}
+
+func (Cheapest) Rank(selection *SelectionContext, resources []*ResourceScheduled) {
+	selection.preferences.sortByRank(resources, selection.Cost)
+}
+
+func (Cheapest) Compare(a, b *Selection) int {
+	if a.Cost.Amount != b.Cost.Amount {
+		return compareMoney(a.Cost, b.Cost)
+	}
+
+	return cmp.Compare(a.WhenCanStart, b.WhenCanStart)
+}
+
+// Earliest selects the earliest start, with the lowest cost resources for it.
+type Earliest struct{}
+
+var _ SelectionStrategy = Earliest{}
+
+func (Earliest) Rank(selection *SelectionContext, resources []*ResourceScheduled) {
+	Cheapest{}.Rank(selection, resources)
+}
+
+func (Earliest) Compare(a, b *Selection) int {
+	return compareEarliest(a, b)
+}
+
+// LeastUtilized balances load, selecting the resources with the fewest booked seconds
+// within Window seconds around the slot, all bookings if zero. Earliest start.
+type LeastUtilized struct {
+	Window int64
+}
+
+var _ SelectionStrategy = LeastUtilized{}
+
+func (s LeastUtilized) Rank(selection *SelectionContext, resources []*ResourceScheduled) {
+	var window *TimeInterval
+
+	if s.Window > 0 {
+		window = &TimeInterval{
+			TimeStart:     selection.Slot.TimeStart - s.Window,
+			TimeEnd:       selection.Slot.TimeEnd + s.Window,
+			SecondsOffset: selection.Slot.SecondsOffset,
+			TimeZone:      selection.Slot.TimeZone,
+		}
+	}
+
+	booked := make(map[*ResourceScheduled]int64, len(resources))
+
+	for _, resource := range resources {
+		booked[resource] = resource.GetBookedSeconds(window)
+	}
+
+	Cheapest{}.Rank(selection, resources)
+
+	slices.SortStableFunc(
+		resources,
+		func(a, b *ResourceScheduled) int {
+			return cmp.Compare(booked[a], booked[b])
+		},
+	)
+
+	selection.preferences.sortRequiredFirst(resources)
+}
+
+func (LeastUtilized) Compare(a, b *Selection) int {
+	return compareEarliest(a, b)
+}
+
+// RoundRobin selects, per resource type, the resources following by ID the ones last booked.
+// Earliest start. Safe for concurrent use, share one value per rotation.
+type RoundRobin struct {
+	mu sync.Mutex
+
+	lastIDs map[uint8]int // resource type | last booked resource ID
+}
+
+var (
+	_ SelectionStrategy = &RoundRobin{}
+	_ SelectionObserver = &RoundRobin{}
+)
+
+func (s *RoundRobin) Rank(selection *SelectionContext, resources []*ResourceScheduled) {
+	if len(resources) == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	lastID, exists := s.lastIDs[resources[0].ResourceType]
+	s.mu.Unlock()
+
+	slices.SortStableFunc(
+		resources,
+		func(a, b *ResourceScheduled) int {
+			return cmp.Compare(a.ID, b.ID)
+		},
+	)
+
+	if exists {
+		next, _ := slices.BinarySearchFunc(
+			resources,
+			lastID+1,
+			func(resource *ResourceScheduled, id int) int {
+				return cmp.Compare(resource.ID, id)
+			},
+		)
+
+		slices.Reverse(resources[:next])
+		slices.Reverse(resources[next:])
+		slices.Reverse(resources)
+	}
+
+	selection.preferences.sortRequiredFirst(resources)
+}
+
+func (*RoundRobin) Compare(a, b *Selection) int {
+	return compareEarliest(a, b)
+}
+
+func (s *RoundRobin) Selected(resources []*ResourceScheduled) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.lastIDs == nil {
+		s.lastIDs = make(map[uint8]int)
+	}
+
+	for _, resource := range resources {
+		s.lastIDs[resource.ResourceType] = resource.ID
+	}
+}
+
+// PreferredFirst selects the preferred resources first, then the others in the order they are held.
+// Earliest start. Default for Loco.
+type PreferredFirst struct{}
+
+var _ SelectionStrategy = PreferredFirst{}
+
+func (PreferredFirst) Rank(selection *SelectionContext, resources []*ResourceScheduled) {
+	selection.preferences.sortPreferredFirst(resources)
+}
+
+func (PreferredFirst) Compare(a, b *Selection) int {
+	return compareEarliest(a, b)
+}
+
+func getStrategy(strategy, byDefault SelectionStrategy) SelectionStrategy {
+	if strategy == nil {
+		return byDefault
+	}
+
+	return strategy
+}
+
+// selectionOnly hides the observer of a strategy, for evaluating without booking.
+type selectionOnly struct {
+	SelectionStrategy
+}
+
+func notifySelected(strategy SelectionStrategy, resources []*ResourceScheduled) {
+	if observer, isObserver := strategy.(SelectionObserver); isObserver {
+		observer.Selected(resources)
+	}
+}
@@ -0,0 +1,218 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectionStrategy(t *testing.T) {
+	interval := TimeInterval{
+		TimeStart: now,
+		TimeEnd:   now + 2*oneHour,
+	}
+
+	tests := []struct {
+		name       string
+		strategy   SelectionStrategy
+		resources  []*ResourceScheduled
+		dependency RunDependency
+
+		expectedScheduled  bool
+		expectedResourceID int
+	}{
+		{
+			name:     "1. Cheapest waits for the cheap resource",
+			strategy: Cheapest{},
+			resources: []*ResourceScheduled{
+				newTestResource(1, 1, 1, map[TimeInterval]RunID{
+					{TimeStart: now, TimeEnd: now + oneHour}: Maintenance,
+				}),
+				newTestResource(2, 1, 5, nil),
+			},
+		},
+		{
+			name:     "2. Earliest takes the expensive resource now",
+			strategy: Earliest{},
+			resources: []*ResourceScheduled{
+				newTestResource(1, 1, 1, map[TimeInterval]RunID{
+					{TimeStart: now, TimeEnd: now + oneHour}: Maintenance,
+				}),
+				newTestResource(2, 1, 5, nil),
+			},
+			expectedScheduled:  true,
+			expectedResourceID: 2,
+		},
+		{
+			name:     "3. Least utilized",
+			strategy: LeastUtilized{},
+			resources: []*ResourceScheduled{
+				newTestResource(1, 1, 1, map[TimeInterval]RunID{
+					{TimeStart: now + 5*oneHour, TimeEnd: now + 6*oneHour}: Maintenance,
+				}),
+				newTestResource(2, 1, 5, nil),
+			},
+			expectedScheduled:  true,
+			expectedResourceID: 2,
+		},
+		{
+			name:     "4. Least utilized within window",
+			strategy: LeastUtilized{Window: oneHour},
+			resources: []*ResourceScheduled{
+				newTestResource(1, 1, 1, map[TimeInterval]RunID{
+					{TimeStart: now + 5*oneHour, TimeEnd: now + 6*oneHour}: Maintenance,
+				}),
+				newTestResource(2, 1, 5, nil),
+			},
+			expectedScheduled:  true,
+			expectedResourceID: 1,
+		},
+		{
+			name:     "5. Preferred first",
+			strategy: PreferredFirst{},
+			resources: []*ResourceScheduled{
+				newTestResource(1, 1, 1, nil),
+				newTestResource(2, 1, 5, nil),
+			},
+			dependency: RunDependency{
+				PreferredResourceID: 2,
+			},
+			expectedScheduled:  true,
+			expectedResourceID: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name,
+			func(t *testing.T) {
+				location, errCr := NewLocation(
+					&ParamsNewLocation{
+						ID:        1,
+						Name:      t.Name(),
+						Resources: tt.resources,
+						Strategy:  tt.strategy,
+					},
+				)
+				require.NoError(t, errCr)
+
+				response, errSchedule := location.CanSchedule(
+					&ParamsCanRun{
+						TimeInterval: interval,
+						TaskRun:      newTestRunPreferring(1, tt.dependency),
+					},
+				)
+				require.NoError(t, errSchedule)
+				require.Equal(t,
+					tt.expectedScheduled,
+					response.WasScheduled,
+				)
+
+				if !tt.expectedScheduled {
+					require.EqualValues(t,
+						now+oneHour,
+						response.WhenCanStart,
+					)
+
+					return
+				}
+
+				for _, resource := range tt.resources {
+					require.Equal(t,
+						resource.ID == tt.expectedResourceID,
+						resource.schedule.hasRun(1),
+						resource.ID,
+					)
+				}
+			},
+		)
+	}
+
+	t.Run(
+		"6. Round robin",
+		func(t *testing.T) {
+			resources := []*ResourceScheduled{
+				newTestResource(3, 1, 1, nil),
+				newTestResource(1, 1, 1, nil),
+				newTestResource(2, 1, 1, nil),
+			}
+
+			location, errCr := NewLocation(
+				&ParamsNewLocation{
+					ID:        1,
+					Name:      t.Name(),
+					Resources: resources,
+					Strategy:  &RoundRobin{},
+				},
+			)
+			require.NoError(t, errCr)
+
+			for ix, expectedResourceID := range []int{1, 2, 3, 1} {
+				runID := int64(ix + 1)
+				start := now + int64(ix)*oneHour
+
+				response, errSchedule := location.CanSchedule(
+					&ParamsCanRun{
+						TimeInterval: TimeInterval{
+							TimeStart: start,
+							TimeEnd:   start + oneHour,
+						},
+						TaskRun: newTestRunPreferring(runID, RunDependency{}),
+					},
+				)
+				require.NoError(t, errSchedule)
+				require.True(t, response.WasScheduled)
+
+				for _, resource := range resources {
+					require.Equal(t,
+						resource.ID == expectedResourceID,
+						resource.schedule.hasRun(RunID(runID)),
+						runID,
+					)
+				}
+			}
+		},
+	)
+
+	t.Run(
+		"7. Loco",
+		func(t *testing.T) {
+			loco := Loco{
+				ID:   1,
+				Name: t.Name(),
+
+				Resources: ResourcesPerType{
+					1: {
+						newTestResource(2, 1, 5, nil),
+						newTestResource(1, 1, 1, nil),
+					},
+				},
+			}
+
+			params := ParamsCanRun{
+				TimeInterval: TimeInterval{
+					TimeStart: now,
+					TimeEnd:   now + oneHour,
+				},
+				TaskRun: newTestRunPreferring(1, RunDependency{}),
+			}
+
+			options, errGet := loco.GetSchedulingOptions(&params)
+			require.NoError(t, errGet)
+			require.Equal(t,
+				2,
+				options[0].Resources[1][0].ID,
+				"default takes resources as held",
+			)
+
+			loco.Strategy = Cheapest{}
+
+			optionsCheapest, errGetCheapest := loco.GetSchedulingOptions(&params)
+			require.NoError(t, errGetCheapest)
+			require.Equal(t,
+				1,
+				optionsCheapest[0].Resources[1][0].ID,
+			)
+		},
+	)
+}
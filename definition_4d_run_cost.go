@@ -0,0 +1,230 @@
+package scheduler
+
+import (
+	"fmt"
+	"math/big"
+
+	goerrors "github.com/TudorHulban/go-errors"
+)
+
+// CostLine is the cost of a run on a resource, load, time and fee.
+// Load and time charges are rounded once each to minor units, the cost is their exact sum with the fee.
+type CostLine struct {
+	Resource *ResourceScheduled
+
+	Load        float32
+	LoadUnit    uint8
+	CostPerUnit Money
+	CostLoad    Money
+
+	Seconds     int64 // the run holds the resource
+	CostPerHour Money
+	CostTime    Money
+
+	Factor *big.Rat // exact pricing calendar factor over the run, applied to load and time
+
+	CostPerRun Money // activation or setup fee
+
+	Cost Money
+}
+
+// RunCost is the cost of a run on its resources, a line per resource.
+type RunCost struct {
+	Lines []*CostLine
+	Total Money
+}
+
+// supportsLoadUnit returns true if the resource prices the run load unit
+// or, pricing no load unit at all, charges by the hour only, the load then costing nothing.
+// A resource pricing other load units does not support the run.
+func (r *Run) supportsLoadUnit(resource *ResourceScheduled) bool {
+	if len(resource.CostPerLoadUnit) == 0 {
+		return !resource.CostPerHour.IsZero()
+	}
+
+	_, isPriced := resource.CostPerLoadUnit[r.LoadUnit]
+
+	return isPriced
+}
+
+// runPricing prices a run held over an interval.
+type runPricing struct {
+	run *Run
+
+	interval  *TimeInterval    // nil is not weighted by pricing calendars
+	reference *TimeInterval    // wall clock of the calendars, nil takes the interval
+	calendar  *PricingCalendar // for resources without one
+
+	currency Currency // of prices without one
+	rounding RoundingMode
+}
+
+func (p *runPricing) getFactor(resource *ResourceScheduled) *big.Rat {
+	if p.interval == nil {
+		return big.NewRat(1, 1)
+	}
+
+	calendar := ternary(resource.Pricing != nil, resource.Pricing, p.calendar)
+
+	return calendar.GetFactor(
+		ternary(p.reference != nil, p.reference, p.interval),
+		p.interval,
+	)
+}
+
+// getCostLine prices the run on the resource: the load in the run load unit,
+// the estimated duration at the hourly rate, both weighted by the pricing calendar and rounded, and the fee per run.
+// False if the resource does not support the run load unit, see supportsLoadUnit.
+func (p *runPricing) getCostLine(resource *ResourceScheduled) (*CostLine, bool) {
+	if !p.run.supportsLoadUnit(resource) {
+		return nil,
+			false
+	}
+
+	costPerUnit := resource.CostPerLoadUnit[p.run.LoadUnit] // zero for time only pricing
+
+	// checked on location creation, or by getCost
+	currency, _ := getCurrency(p.currency, resource.getPrices()...)
+
+	result := CostLine{
+		Resource: resource,
+
+		Load:        p.run.Load,
+		LoadUnit:    p.run.LoadUnit,
+		CostPerUnit: costPerUnit.in(currency),
+
+		Seconds:     p.run.EstimatedDuration,
+		CostPerHour: resource.CostPerHour.in(currency),
+
+		Factor: p.getFactor(resource),
+
+		CostPerRun: resource.CostPerRun.in(currency),
+	}
+
+	// load taken as the decimal it is written in
+	result.CostLoad = result.CostPerUnit.scale(
+		new(big.Rat).Mul(decimalOf(p.run.Load), result.Factor),
+		p.rounding,
+	)
+	result.CostTime = result.CostPerHour.scale(
+		new(big.Rat).Mul(big.NewRat(p.run.EstimatedDuration, 3600), result.Factor),
+		p.rounding,
+	)
+	result.Cost = result.CostLoad.add(result.CostTime).add(result.CostPerRun)
+
+	return &result,
+		true
+}
+
+// getResourceCost is the cost of the run on the resource, what resources are ranked at.
+// Zero if the resource is not priced for the run, such resources are left out of the search.
+func (p *runPricing) getResourceCost(resource *ResourceScheduled) Money {
+	line, isPriced := p.getCostLine(resource)
+	if !isPriced {
+		return Money{Currency: p.currency}
+	}
+
+	return line.Cost
+}
+
+// getCostTotal is the cost of the run on the resources pricing its load unit.
+func (p *runPricing) getCostTotal(resources []*ResourceScheduled) Money {
+	result := Money{Currency: p.currency}
+
+	for _, resource := range resources {
+		result = result.add(p.getResourceCost(resource))
+	}
+
+	return result
+}
+
+func (p *runPricing) getCost(resources []*ResourceScheduled) (*RunCost, error) {
+	result := RunCost{
+		Lines: make([]*CostLine, 0, len(resources)),
+		Total: Money{Currency: p.currency},
+	}
+
+	for _, resource := range resources {
+		if _, errCurrency := getCurrency(p.currency, resource.getPrices()...); errCurrency != nil {
+			return nil,
+				goerrors.ErrValidation{
+					Caller: "GetCost",
+					Issue:  errCurrency,
+				}
+		}
+
+		line, isPriced := p.getCostLine(resource)
+		if !isPriced {
+			return nil,
+				goerrors.ErrValidation{
+					Caller: "GetCost",
+					Issue: goerrors.ErrInvalidInput{
+						InputName: fmt.Sprintf(
+							"resource %d - load unit %d not priced",
+							resource.ID,
+							p.run.LoadUnit,
+						),
+					},
+				}
+		}
+
+		total, errAdd := result.Total.Add(line.Cost)
+		if errAdd != nil {
+			return nil,
+				errAdd
+		}
+
+		result.Lines = append(result.Lines, line)
+		result.Total = total
+	}
+
+	return &result,
+		nil
+}
+
+// GetCost returns the cost of the run on the resources, a line per resource,
+// not weighted by pricing calendars, rounded half to even.
+// All resources should price the run load unit, in one currency.
+func (r *Run) GetCost(resources []*ResourceScheduled) (*RunCost, error) {
+	return (&runPricing{run: r}).getCost(resources)
+}
+
+// GetCostAt is GetCost with the run held over the interval, weighted by the pricing calendars
+// of the resources in the wall clock of the interval.
+func (r *Run) GetCostAt(resources []*ResourceScheduled, interval *TimeInterval) (*RunCost, error) {
+	return (&runPricing{run: r, interval: interval}).getCost(resources)
+}
+
+// at returns the pricing of the run held over the interval, with the calendar, currency and rounding of p.
+// Nil p prices without calendars.
+func (p *runPricing) at(run *Run, interval *TimeInterval) *runPricing {
+	result := runPricing{}
+
+	if p != nil {
+		result = *p
+	}
+
+	result.run = run
+	result.interval = interval
+
+	return &result
+}
+
+// getPricing prices the run held over the interval, in location wall clock.
+func (loc *Engine) getPricing(run *Run, interval *TimeInterval) *runPricing {
+	return &runPricing{
+		run:       run,
+		interval:  interval,
+		reference: loc.getTimeReference(),
+		calendar:  loc.Pricing,
+
+		currency: loc.Currency,
+		rounding: loc.Rounding,
+	}
+}
+
+// GetCostAt is Run.GetCostAt, resources without pricing calendar weighted by the location one,
+// in location wall clock, currency and rounding.
+func (loc *Engine) GetCostAt(run *Run, resources []*ResourceScheduled, interval *TimeInterval) (*RunCost, error) {
+	return loc.getPricing(run, interval).getCost(resources)
+}
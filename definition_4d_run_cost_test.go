@@ -0,0 +1,260 @@
+package scheduler
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunCost(t *testing.T) {
+	// amounts in euro cents
+	newResource := func(id int, costPerLoadUnit map[uint8]int64) *ResourceScheduled {
+		result := newTestResource(id, 1, 0, nil)
+		result.CostPerLoadUnit = make(map[uint8]Money, len(costPerLoadUnit))
+
+		for loadUnit, amount := range costPerLoadUnit {
+			result.CostPerLoadUnit[loadUnit] = NewMoney(amount, "EUR")
+		}
+
+		return result
+	}
+
+	run := *newTestRun(1, oneHour, 1)
+	run.RunLoad = RunLoad{
+		Load:     3,
+		LoadUnit: 2,
+	}
+
+	t.Run(
+		"1. Lines in the run load unit",
+		func(t *testing.T) {
+			resources := []*ResourceScheduled{
+				newResource(1, map[uint8]int64{1: 1, 2: 10}),
+				newResource(2, map[uint8]int64{1: 5, 2: 2}),
+			}
+
+			cost, errGet := run.GetCost(resources)
+			require.NoError(t, errGet)
+			require.Equal(t,
+				NewMoney(36, "EUR"),
+				cost.Total,
+			)
+			require.Len(t,
+				cost.Lines,
+				2,
+			)
+			require.Equal(t,
+				&CostLine{
+					Resource:    resources[1],
+					Load:        3,
+					LoadUnit:    2,
+					CostPerUnit: NewMoney(2, "EUR"),
+					CostLoad:    NewMoney(6, "EUR"),
+					Seconds:     oneHour,
+					CostPerHour: NewMoney(0, "EUR"),
+					CostTime:    NewMoney(0, "EUR"),
+					Factor:      big.NewRat(1, 1),
+					CostPerRun:  NewMoney(0, "EUR"),
+					Cost:        NewMoney(6, "EUR"),
+				},
+				cost.Lines[1],
+			)
+		},
+	)
+
+	t.Run(
+		"2. Unit not priced",
+		func(t *testing.T) {
+			_, errGet := run.GetCost(
+				[]*ResourceScheduled{
+					newResource(1, map[uint8]int64{1: 1}),
+				},
+			)
+			require.Error(t, errGet)
+		},
+	)
+
+	t.Run(
+		"3. Ranked in the run load unit, unpriced left out",
+		func(t *testing.T) {
+			resources := []*ResourceScheduled{
+				newResource(1, map[uint8]int64{1: 1, 2: 10}),
+				newResource(2, map[uint8]int64{1: 5, 2: 2}),
+				newResource(3, map[uint8]int64{1: 0}),
+			}
+
+			engine := newTestLocation(t, resources...)
+
+			params := ParamsCanRun{
+				TimeInterval: TimeInterval{
+					TimeStart: now,
+					TimeEnd:   now + oneHour,
+				},
+				TaskRun:          &run,
+				AllPossibilities: true,
+			}
+
+			options, errGet := engine.GetSchedulingOptions(&params)
+			require.NoError(t, errGet)
+			require.Len(t,
+				options,
+				2,
+			)
+
+			response, errSchedule := engine.CanSchedule(&params)
+			require.NoError(t, errSchedule)
+			require.True(t, response.WasScheduled)
+			require.Equal(t,
+				NewMoney(6, "EUR"),
+				response.Cost,
+			)
+			require.True(t,
+				resources[1].schedule.hasRun(1),
+			)
+		},
+	)
+
+	t.Run(
+		"4. Time and fee, same on all paths",
+		func(t *testing.T) {
+			runLong := run
+			runLong.EstimatedDuration = oneHour + halfHour
+
+			newResources := func() []*ResourceScheduled {
+				hourly := newResource(1, map[uint8]int64{2: 1})
+				hourly.CostPerHour = NewMoney(10, "EUR")
+				hourly.CostPerRun = NewMoney(5, "EUR")
+
+				return []*ResourceScheduled{
+					hourly,
+					newResource(2, map[uint8]int64{2: 7}),
+				}
+			}
+
+			resources := newResources()
+
+			cost, errGet := runLong.GetCost(resources[:1])
+			require.NoError(t, errGet)
+			require.Equal(t,
+				NewMoney(3+15+5, "EUR"),
+				cost.Total,
+			)
+			require.Equal(t,
+				NewMoney(15, "EUR"),
+				cost.Lines[0].CostTime,
+			)
+
+			params := ParamsCanRun{
+				TimeInterval: TimeInterval{
+					TimeStart: now,
+					TimeEnd:   now + 2*oneHour,
+				},
+				TaskRun: &runLong,
+			}
+
+			loco := Loco{
+				ID:   1,
+				Name: t.Name(),
+
+				Resources: ResourcesPerType{
+					1: newResources(),
+				},
+				Strategy: Cheapest{},
+			}
+
+			optionsLoco, errGetLoco := loco.GetSchedulingOptions(&params)
+			require.NoError(t, errGetLoco)
+			require.Equal(t,
+				2,
+				optionsLoco[0].Resources[1][0].ID,
+				"hourly resource is dearer for a long run",
+			)
+
+			costLoco, errCostLoco := optionsLoco[0].GetCostFor(&runLong)
+			require.NoError(t, errCostLoco)
+
+			engine := newTestLocation(t, resources...)
+
+			options, errGetOptions := engine.GetSchedulingOptions(&params)
+			require.NoError(t, errGetOptions)
+
+			response, errSchedule := engine.CanSchedule(&params)
+			require.NoError(t, errSchedule)
+			require.True(t, response.WasScheduled)
+
+			require.Equal(t, NewMoney(21, "EUR"), response.Cost)
+			require.Equal(t, response.Cost, options[0].Cost)
+			require.Equal(t, response.Cost, costLoco)
+		},
+	)
+
+	t.Run(
+		"5. Time only resource, load costs nothing",
+		func(t *testing.T) {
+			hourly := newResource(1, nil)
+			hourly.CostPerHour = NewMoney(120, "EUR")
+
+			cost, errGet := run.GetCost(
+				[]*ResourceScheduled{hourly},
+			)
+			require.NoError(t, errGet)
+			require.Len(t,
+				cost.Lines,
+				1,
+			)
+			require.True(t,
+				cost.Lines[0].CostLoad.IsZero(),
+			)
+			require.Equal(t,
+				NewMoney(120, "EUR"),
+				cost.Lines[0].CostTime,
+			)
+			require.Equal(t,
+				NewMoney(120, "EUR"),
+				cost.Total,
+			)
+
+			params := ParamsCanRun{
+				TimeInterval: TimeInterval{
+					TimeStart: now,
+					TimeEnd:   now + oneHour,
+				},
+				TaskRun: &run,
+			}
+
+			response, errSchedule := newTestLocation(t, hourly).CanSchedule(&params)
+			require.NoError(t, errSchedule)
+			require.True(t, response.WasScheduled)
+			require.Equal(t,
+				NewMoney(120, "EUR"),
+				response.Cost,
+			)
+		},
+	)
+
+	t.Run(
+		"6. Hourly resource pricing another unit",
+		func(t *testing.T) {
+			hourly := newResource(1, map[uint8]int64{1: 1})
+			hourly.CostPerHour = NewMoney(120, "EUR")
+
+			_, errGet := run.GetCost(
+				[]*ResourceScheduled{hourly},
+			)
+			require.Error(t, errGet)
+
+			params := ParamsCanRun{
+				TimeInterval: TimeInterval{
+					TimeStart: now,
+					TimeEnd:   now + oneHour,
+				},
+				TaskRun: &run,
+			}
+
+			response, errSchedule := newTestLocation(t, hourly).CanSchedule(&params)
+			require.NoError(t, errSchedule)
+			require.False(t, response.WasScheduled)
+		},
+	)
+}
@@ -4,48 +4,168 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	goerrors "github.com/TudorHulban/go-errors"
 	"github.com/asaskevich/govalidator"
 )
 
-type Location struct {
+// Engine schedules runs on a set of resources: it generates options, quantity aware,
+// costs them and books them.
+//
+// Methods are safe for concurrent use. Operations that check and book hold the location lock
+// for their whole duration and book under the locks of the resources involved,
+// so a resource shared with another location, or booked directly with AddRun,
+// is never double booked.
+// Locks are taken location first, then resources ordered by ID.
+type Engine struct {
 	Name      string
 	Resources []*ResourceScheduled
 	mu        sync.Mutex
 
 	ID             int64
 	LocationOffset int64
+
+	TimeZone *time.Location   // takes precedence over LocationOffset, DST aware
+	Calendar *Calendar        // opening hours, nil is always open
+	Pricing  *PricingCalendar // for resources without one, nil prices all time alike
+
+	Currency Currency     // of all costs, prices without currency are in it
+	Rounding RoundingMode // of load and time charges, default half to even
+
+	Now      func() time.Time  // clock for hold expiry, nil is time.Now
+	Strategy SelectionStrategy // how resources are selected, nil is Cheapest
+
+	holds map[HoldToken]*hold
+
+	quotes quoteBook // issued, by ID
 }
 
-type ParamsNewLocation struct {
+// Location is the engine for the resources of a place, kept for compatibility.
+type Location = Engine
+
+type ParamsNewEngine struct {
 	Name      string               `valid:"required"`
 	Resources []*ResourceScheduled `valid:"required"`
 
 	ID             int64 `valid:"required"`
 	LocationOffset int64
+
+	TimeZone *time.Location
+	Calendar *Calendar         `valid:"-"`
+	Pricing  *PricingCalendar  `valid:"-"`
+	Currency Currency          `valid:"-"` // empty takes the one of the resources
+	Rounding RoundingMode      `valid:"-"`
+	Now      func() time.Time  `valid:"-"`
+	Strategy SelectionStrategy `valid:"-"`
 }
 
-func NewLocation(params *ParamsNewLocation) (*Location, error) {
+type ParamsNewLocation = ParamsNewEngine
+
+func NewEngine(params *ParamsNewEngine) (*Engine, error) {
 	if _, errValidation := govalidator.ValidateStruct(params); errValidation != nil {
 		return nil,
 			goerrors.ErrServiceValidation{
 				ServiceName: "Organigram",
-				Caller:      "NewLocation",
+				Caller:      "NewEngine",
 				Issue:       errValidation,
 			}
 	}
 
-	return &Location{
+	currency, errCurrency := getCurrencyOf(params.Currency, params.Resources)
+	if errCurrency != nil {
+		return nil,
+			goerrors.ErrValidation{
+				Caller: "NewEngine",
+				Issue:  errCurrency,
+			}
+	}
+
+	return &Engine{
 			ID:             params.ID,
 			Name:           params.Name,
 			LocationOffset: params.LocationOffset,
+			TimeZone:       params.TimeZone,
+			Calendar:       params.Calendar,
+			Pricing:        params.Pricing,
+			Currency:       currency,
+			Rounding:       params.Rounding,
+			Now:            params.Now,
+			Strategy:       params.Strategy,
 
 			Resources: params.Resources,
 		},
 		nil
 }
 
+// getCurrencyOf returns the currency the resources are priced in, the passed one unless empty.
+// Resources priced in another currency are an error, currencies are not mixed within a location.
+func getCurrencyOf(currency Currency, resources []*ResourceScheduled) (Currency, error) {
+	result := currency
+
+	for _, resource := range resources {
+		if resource == nil {
+			continue
+		}
+
+		resourceCurrency, errCurrency := getCurrency(result, resource.getPrices()...)
+		if errCurrency != nil {
+			return "",
+				fmt.Errorf(
+					"resource %d: %w",
+					resource.ID,
+					errCurrency,
+				)
+		}
+
+		result = resourceCurrency
+	}
+
+	return result,
+		nil
+}
+
+func NewLocation(params *ParamsNewLocation) (*Location, error) {
+	return NewEngine(params)
+}
+
+func (loc *Engine) getStrategy() SelectionStrategy {
+	return getStrategy(loc.Strategy, Cheapest{})
+}
+
+// getStrategyFor returns the strategy searching for the run, the one of the params if set.
+func (loc *Engine) getStrategyFor(params *ParamsCanRun) SelectionStrategy {
+	return getStrategy(params.strategy, loc.getStrategy())
+}
+
+// GetSecondsOffsetAt returns the location offset in effect at the passed UTC time.
+func (loc *Engine) GetSecondsOffsetAt(utc int64) int64 {
+	if loc.TimeZone != nil {
+		return secondsOffsetIn(loc.TimeZone, utc)
+	}
+
+	return loc.LocationOffset
+}
+
+// getTimeReference returns an empty interval expressed as location time,
+// used as reference for wall clock conversions.
+func (loc *Engine) getTimeReference() *TimeInterval {
+	return &TimeInterval{
+		SecondsOffset: loc.LocationOffset,
+		TimeZone:      loc.TimeZone,
+	}
+}
+
+// toLocationTime expresses the interval as location local time,
+// wall clock of the location zone if set.
+func (loc *Engine) toLocationTime(interval *TimeInterval) TimeInterval {
+	if loc.TimeZone != nil {
+		return interval.InTimeZone(loc.TimeZone)
+	}
+
+	return interval.ToOffset(loc.LocationOffset)
+}
+
 type ParamsCanRun struct {
 	TimeInterval
 
@@ -53,6 +173,11 @@ type ParamsCanRun struct {
 
 	PossibilitiesUpTo uint8
 	AllPossibilities  bool
+
+	ContinuousStart bool  // search starts where the resources free up, not only multiples of the duration from TimeStart
+	StartStep       int64 // with ContinuousStart, seconds of the local clock grid starts snap to, zero takes the free up times
+
+	strategy SelectionStrategy // overrides the location strategy for the search, nil keeps it
 }
 
 func (p ParamsCanRun) String() string {
@@ -65,6 +190,10 @@ func (p ParamsCanRun) String() string {
 	sb.WriteString(fmt.Sprintf("\t\tTimeStart: %d,\n", p.TimeStart))
 	sb.WriteString(fmt.Sprintf("\t\tTimeEnd: %d,\n", p.TimeEnd))
 	sb.WriteString(fmt.Sprintf("\t\tSecondsOffset: %d,\n", p.SecondsOffset))
+
+	if p.TimeZone != nil {
+		sb.WriteString(fmt.Sprintf("\t\tTimeZone: %s,\n", p.TimeZone))
+	}
 	sb.WriteString("\t},\n")
 
 	// TaskRun
@@ -78,6 +207,8 @@ func (p ParamsCanRun) String() string {
 			for _, dep := range p.TaskRun.Dependencies {
 				sb.WriteString("\t\t\t{\n")
 				sb.WriteString(fmt.Sprintf("\t\t\t\tPreferredResourceID: %d,\n", dep.PreferredResourceID))
+				sb.WriteString(fmt.Sprintf("\t\t\t\tPreferenceRequired: %t,\n", dep.PreferenceRequired))
+				sb.WriteString(fmt.Sprintf("\t\t\t\tPreferencePenalty: %s,\n", dep.PreferencePenalty))
 				sb.WriteString(fmt.Sprintf("\t\t\t\tResourceType: %d,\n", dep.ResourceType))
 				sb.WriteString(fmt.Sprintf("\t\t\t\tResourceQuantity: %d,\n", dep.ResourceQuantity))
 				sb.WriteString("\t\t\t},\n")
@@ -0,0 +1,441 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngineQuantities(t *testing.T) {
+	newResources := func() []*ResourceScheduled {
+		return []*ResourceScheduled{
+			newTestResourceServing(1, 2, 3), // apartment, serves two
+			newTestResourceServing(2, 1, 1),
+			newTestResourceServing(3, 0, 1), // zero serves one
+		}
+	}
+
+	run := *newTestRun(1, oneHour, 1)
+	run.Dependencies[0].ResourceQuantity = 2
+
+	interval := TimeInterval{
+		TimeStart: now,
+		TimeEnd:   now + 2*oneHour,
+	}
+
+	t.Run(
+		"1. Book serving the quantity",
+		func(t *testing.T) {
+			resources := newResources()
+
+			engine := newTestLocation(t, resources...)
+
+			response, errSchedule := engine.CanSchedule(
+				&ParamsCanRun{
+					TimeInterval: interval,
+					TaskRun:      &run,
+				},
+			)
+			require.NoError(t, errSchedule)
+			require.True(t, response.WasScheduled)
+			require.EqualValues(t,
+				2,
+				response.Cost.Amount,
+				"two single rooms",
+			)
+
+			secondRun := run
+			secondRun.ID = 2
+
+			responseSecond, errScheduleSecond := engine.CanSchedule(
+				&ParamsCanRun{
+					TimeInterval: TimeInterval{
+						TimeStart: now,
+						TimeEnd:   now + oneHour,
+					},
+					TaskRun: &secondRun,
+				},
+			)
+			require.NoError(t, errScheduleSecond)
+			require.True(t, responseSecond.WasScheduled)
+			require.True(t,
+				resources[0].schedule.hasRun(2),
+				"apartment alone serves two",
+			)
+		},
+	)
+
+	t.Run(
+		"2. Options capped per start",
+		func(t *testing.T) {
+			engine := newTestLocation(t, newResources()...)
+
+			params := ParamsCanRun{
+				TimeInterval:     interval,
+				TaskRun:          &run,
+				AllPossibilities: true,
+			}
+
+			options, errGet := engine.GetSchedulingOptions(&params)
+			require.NoError(t, errGet)
+			require.Len(t,
+				options,
+				4,
+				"two starts, apartment or two rooms",
+			)
+
+			params.PossibilitiesUpTo = 1
+
+			optionsCapped, errGetCapped := engine.GetSchedulingOptions(&params)
+			require.NoError(t, errGetCapped)
+			require.Len(t,
+				optionsCapped,
+				2,
+			)
+		},
+	)
+
+	t.Run(
+		"3. Loco books through the engine",
+		func(t *testing.T) {
+			loco := Loco{
+				ID:   1,
+				Name: t.Name(),
+
+				Resources: ResourcesPerType{
+					1: newResources(),
+				},
+			}
+
+			engine, errEngine := loco.GetEngine()
+			require.NoError(t, errEngine)
+
+			response, errSchedule := engine.CanSchedule(
+				&ParamsCanRun{
+					TimeInterval: TimeInterval{
+						TimeStart: now,
+						TimeEnd:   now + oneHour,
+					},
+					TaskRun: &run,
+				},
+			)
+			require.NoError(t, errSchedule)
+			require.True(t, response.WasScheduled)
+			require.True(t,
+				loco.Resources[1][0].schedule.hasRun(1),
+				"default strategy takes resources as held",
+			)
+
+			options, errGet := loco.GetSchedulingOptions(
+				&ParamsCanRun{
+					TimeInterval: interval,
+					TaskRun:      &run,
+				},
+			)
+			require.NoError(t, errGet)
+			require.Len(t,
+				options,
+				2,
+			)
+			require.EqualValues(t,
+				now,
+				options[0].WhenCanStart,
+			)
+			require.Equal(t,
+				[]*ResourceScheduled{loco.Resources[1][1], loco.Resources[1][2]},
+				options[0].Resources[1],
+				"apartment booked, two rooms serve",
+			)
+
+			cost, errCost := options[0].GetCostFor(&run)
+			require.NoError(t, errCost)
+			require.Positive(t, cost.Amount)
+		},
+	)
+
+	t.Run(
+		"4. Loco keeps one engine, holds across calls",
+		func(t *testing.T) {
+			loco := Loco{
+				ID:   1,
+				Name: t.Name(),
+
+				Resources: ResourcesPerType{
+					1: newResources(),
+				},
+			}
+
+			engineFirst, errFirst := loco.GetEngine()
+			require.NoError(t, errFirst)
+
+			options, errGet := engineFirst.GetSchedulingOptions(
+				&ParamsCanRun{
+					TimeInterval: interval,
+					TaskRun:      &run,
+				},
+			)
+			require.NoError(t, errGet)
+
+			token, errHold := engineFirst.Hold(options[0], time.Minute)
+			require.NoError(t, errHold)
+
+			engineSecond, errSecond := loco.GetEngine()
+			require.NoError(t, errSecond)
+			require.Same(t,
+				engineFirst,
+				engineSecond,
+			)
+
+			require.NoError(t,
+				engineSecond.Confirm(token),
+			)
+			require.True(t,
+				loco.Resources[1][0].schedule.hasRun(1),
+			)
+		},
+	)
+}
+
+func TestEngineOvershoot(t *testing.T) {
+	newRun := func(quantity uint8) *Run {
+		result := newTestRun(1, oneHour, 1)
+		result.Dependencies[0].ResourceQuantity = quantity
+
+		return result
+	}
+
+	interval := TimeInterval{
+		TimeStart: now,
+		TimeEnd:   now + oneHour,
+	}
+
+	t.Run(
+		"1. Three beds in two apartments of two",
+		func(t *testing.T) {
+			loco := Loco{
+				ID:   1,
+				Name: t.Name(),
+
+				Resources: ResourcesPerType{
+					1: {
+						newTestResourceServing(1, 2, 1),
+						newTestResourceServing(2, 2, 1),
+					},
+				},
+			}
+
+			params := ParamsCanRun{
+				TimeInterval: interval,
+				TaskRun:      newRun(3),
+			}
+
+			options, errGet := loco.GetAllSchedulingOptions(&params)
+			require.NoError(t, errGet)
+			require.Len(t,
+				options,
+				1,
+			)
+			require.Len(t,
+				options[0].Resources[1],
+				2,
+			)
+			require.Equal(t,
+				1,
+				options[0].Waste,
+			)
+
+			optionsCheapest, errGetCheapest := loco.GetCheapestSchedulingOptions(
+				&ParamsCheapestOptions{
+					ParamsCanRun: params,
+				},
+			)
+			require.NoError(t, errGetCheapest)
+			require.Len(t,
+				optionsCheapest,
+				1,
+			)
+			require.Equal(t,
+				1,
+				optionsCheapest[0].Waste,
+			)
+		},
+	)
+
+	t.Run(
+		"2. Less waste first",
+		func(t *testing.T) {
+			engine := newTestLocation(
+				t,
+
+				newTestResourceServing(1, 4, 1),
+				newTestResourceServing(2, 2, 2),
+				newTestResourceServing(3, 1, 2),
+			)
+
+			options, errGet := engine.GetSchedulingOptions(
+				&ParamsCanRun{
+					TimeInterval:     interval,
+					TaskRun:          newRun(3),
+					AllPossibilities: true,
+				},
+			)
+			require.NoError(t, errGet)
+			require.Len(t,
+				options,
+				2,
+				"apartment alone, or two and one",
+			)
+
+			require.Zero(t, options[0].Waste)
+			require.EqualValues(t,
+				4,
+				options[0].Cost.Amount,
+			)
+
+			require.Equal(t,
+				1,
+				options[1].Waste,
+			)
+			require.EqualValues(t,
+				1,
+				options[1].Cost.Amount,
+			)
+		},
+	)
+
+	t.Run(
+		"3. Selection drops what is not needed",
+		func(t *testing.T) {
+			resources := []*ResourceScheduled{
+				newTestResourceServing(1, 1, 1),
+				newTestResourceServing(2, 1, 1),
+				newTestResourceServing(3, 4, 1),
+			}
+
+			selected, serves := selectServing(resources, 5)
+			require.True(t, serves)
+			require.Equal(t,
+				[]*ResourceScheduled{resources[0], resources[2]},
+				selected,
+			)
+			require.Zero(t,
+				getWaste(selected, map[uint8]uint16{1: 5}),
+			)
+
+			_, servesNot := selectServing(resources, 7)
+			require.False(t, servesNot)
+		},
+	)
+
+	t.Run(
+		"4. Least waste before ranking",
+		func(t *testing.T) {
+			// ranked A, B, C
+			resources := []*ResourceScheduled{
+				newTestResourceServing(1, 2, 1),
+				newTestResourceServing(2, 2, 1),
+				newTestResourceServing(3, 1, 1),
+			}
+
+			selected, serves := selectServing(resources, 3)
+			require.True(t, serves)
+			require.Equal(t,
+				[]*ResourceScheduled{resources[0], resources[2]},
+				selected,
+			)
+
+			response, errSchedule := newTestLocation(t, resources...).CanSchedule(
+				&ParamsCanRun{
+					TimeInterval: interval,
+					TaskRun:      newRun(3),
+				},
+			)
+			require.NoError(t, errSchedule)
+			require.True(t, response.WasScheduled)
+			require.True(t,
+				resources[2].schedule.hasRun(1),
+				"A and C, no waste",
+			)
+			require.False(t,
+				resources[1].schedule.hasRun(1),
+			)
+		},
+	)
+}
+
+func TestEngineAllNeededTypes(t *testing.T) {
+	newResources := func() []*ResourceScheduled {
+		return []*ResourceScheduled{
+			newTestResource(1, 1, 1, nil),
+			newTestResource(2, 2, 1, map[TimeInterval]RunID{
+				{TimeStart: now, TimeEnd: now + oneHour}: Maintenance,
+			}),
+		}
+	}
+
+	params := ParamsCanRun{
+		TimeInterval: TimeInterval{
+			TimeStart: now,
+			TimeEnd:   now + 2*oneHour,
+		},
+		TaskRun:          newTestRun(1, oneHour, 1, 2),
+		AllPossibilities: true,
+	}
+
+	t.Run(
+		"1. Engine, no option while a type is busy",
+		func(t *testing.T) {
+			engine := newTestLocation(t, newResources()...)
+
+			options, errGet := engine.GetSchedulingOptions(&params)
+			require.NoError(t, errGet)
+			require.NotEmpty(t, options)
+
+			for _, option := range options {
+				require.Len(t,
+					newResourcesPerType(option.SelectedResources),
+					2,
+					option.WhenCanStart,
+				)
+				require.GreaterOrEqual(t,
+					option.WhenCanStart,
+					now+oneHour,
+				)
+			}
+		},
+	)
+
+	t.Run(
+		"2. Loco, no option while a type is busy",
+		func(t *testing.T) {
+			resources := newResources()
+
+			loco := Loco{
+				ID:   1,
+				Name: t.Name(),
+
+				Resources: ResourcesPerType{
+					1: resources[:1],
+					2: resources[1:],
+				},
+			}
+
+			options, errGet := loco.GetAllSchedulingOptions(&params)
+			require.NoError(t, errGet)
+			require.NotEmpty(t, options)
+
+			for _, option := range options {
+				require.Len(t,
+					option.Resources,
+					2,
+					option.WhenCanStart,
+				)
+				require.GreaterOrEqual(t,
+					option.WhenCanStart,
+					now+oneHour,
+				)
+			}
+		},
+	)
+}
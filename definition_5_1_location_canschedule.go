@@ -1,6 +1,7 @@
 package scheduler
 
 import (
+	"errors"
 	"slices"
 
 	goerrors "github.com/TudorHulban/go-errors"
@@ -11,13 +12,31 @@ type ResponseGetPossibilities struct {
 
 	resourceTypesNeeded    []uint8
 	resourcesNeededPerType map[uint8]uint16
+	preferences            *resourcePreferences
 
-	offsetedTimeInterval TimeInterval
+	requestTimeInterval  TimeInterval // as passed, possibly zone based
+	taskTimeInterval     TimeInterval // request in the task offset at its start
+	offsetedTimeInterval TimeInterval // request in the location offset at its start
+	offsetDifference     int64        // location offset minus task offset
+
+	openTimeIntervals *IntervalSet // location open time within the request and fallback search, nil if always open
+}
+
+// toTaskTime converts a task fixed offset timestamp to the representation of the request,
+// wall clock of the request zone if set.
+func (resp *ResponseGetPossibilities) toTaskTime(timestamp int64) int64 {
+	if timestamp == _NoAvailability {
+		return timestamp
+	}
+
+	return resp.requestTimeInterval.fromUTCTimestamp(
+		timestamp - resp.taskTimeInterval.SecondsOffset,
+	)
 }
 
 // GetPossibilities returns all possible time slots when resources are available if all possibilities is true.
-func (loc *Location) GetPossibilities(params *ParamsCanRun) (*ResponseGetPossibilities, error) {
-	if params.TimeEnd-params.TimeStart < params.TaskRun.EstimatedDuration {
+func (loc *Engine) GetPossibilities(params *ParamsCanRun) (*ResponseGetPossibilities, error) {
+	if params.Duration() < params.TaskRun.EstimatedDuration {
 		return nil,
 			goerrors.ErrValidation{
 				Caller: "GetPossibilities",
@@ -30,9 +49,11 @@ func (loc *Location) GetPossibilities(params *ParamsCanRun) (*ResponseGetPossibi
 	resourceTypeCandidates := make(map[uint8][]*ResourceScheduled)
 	resourceTypesNeeded := params.TaskRun.GetNeededResourceTypes()
 	resourcesNeededPerType := params.TaskRun.GetNeededResourcesPerType()
+	preferences := params.TaskRun.getResourcePreferences()
 
 	for _, candidate := range loc.Resources {
-		if slices.Contains(resourceTypesNeeded, candidate.ResourceType) {
+		// resources not pricing the run load unit are left out
+		if slices.Contains(resourceTypesNeeded, candidate.ResourceType) && params.TaskRun.supportsLoadUnit(candidate) {
 			resourceTypeCandidates[candidate.ResourceType] = append(
 				resourceTypeCandidates[candidate.ResourceType],
 				candidate,
@@ -40,12 +61,23 @@ func (loc *Location) GetPossibilities(params *ParamsCanRun) (*ResponseGetPossibi
 		}
 	}
 
-	offsetDifference := params.SecondsOffset - loc.LocationOffset
-
-	offsetedTimeInterval := TimeInterval{
-		TimeStart:     params.TimeStart + offsetDifference,
-		TimeEnd:       params.TimeEnd + offsetDifference,
-		SecondsOffset: offsetDifference,
+	// Offsets resolved at the start of the request make fixed offset frames,
+	// they map to UTC linearly whatever DST changes happen inside the interval.
+	taskTimeInterval := params.ToOffset(params.GetSecondsOffsetStart())
+	offsetedTimeInterval := params.ToOffset(loc.GetSecondsOffsetAt(params.GetUTCTimeStart()))
+
+	var openTimeIntervals *IntervalSet
+
+	if loc.Calendar != nil {
+		// fallback searches starts up to the end of the request.
+		openTimeIntervals = loc.Calendar.GetOpenIntervals(
+			loc.getTimeReference(),
+			&TimeInterval{
+				TimeStart:     offsetedTimeInterval.TimeStart,
+				TimeEnd:       offsetedTimeInterval.TimeEnd + 2*params.TaskRun.EstimatedDuration,
+				SecondsOffset: offsetedTimeInterval.SecondsOffset,
+			},
+		)
 	}
 
 	possibilities := populatePossibilities(
@@ -54,9 +86,16 @@ func (loc *Location) GetPossibilities(params *ParamsCanRun) (*ResponseGetPossibi
 			ResourcesNeededPerType: resourcesNeededPerType,
 			TimeInterval:           offsetedTimeInterval,
 
-			Duration: params.TaskRun.EstimatedDuration,
+			Duration:          params.TaskRun.EstimatedDuration,
+			OpenTimeIntervals: openTimeIntervals,
+			Preferences:       preferences,
+			Strategy:          loc.getStrategyFor(params),
+			Run:               params.TaskRun,
+			Pricing:           loc.getPricing(nil, nil),
 
 			AllPossibilities: params.AllPossibilities,
+			ContinuousStart:  params.ContinuousStart,
+			StartStep:        params.StartStep,
 		},
 	)
 
@@ -65,15 +104,25 @@ func (loc *Location) GetPossibilities(params *ParamsCanRun) (*ResponseGetPossibi
 
 			resourceTypesNeeded:    resourceTypesNeeded,
 			resourcesNeededPerType: resourcesNeededPerType,
-			offsetedTimeInterval:   offsetedTimeInterval,
+			preferences:            preferences,
+
+			requestTimeInterval:  params.TimeInterval,
+			taskTimeInterval:     taskTimeInterval,
+			offsetedTimeInterval: offsetedTimeInterval,
+			offsetDifference:     offsetedTimeInterval.SecondsOffset - taskTimeInterval.SecondsOffset,
+			openTimeIntervals:    openTimeIntervals,
 		},
 		nil
 }
 
 type ResponseCanRun struct {
 	WhenCanStart int64
-	Cost         float32
+	Cost         Money
 	WasScheduled bool
+
+	runID     RunID                // the response is for
+	resources []*ResourceScheduled // selected, booked if WasScheduled
+	interval  TimeInterval         // priced, location time
 }
 
 // CanSchedule returns zero for WhenCanStart if it can run within passed interval and
@@ -81,74 +130,80 @@ type ResponseCanRun struct {
 //
 // If it cannot run at TimeStart, it provides the timestamp
 // from which it could in WhenCanStart and the cost of this run.
-func (loc *Location) CanSchedule(params *ParamsCanRun) (*ResponseCanRun, error) {
+//
+// Check and book are atomic, see Location.
+func (loc *Engine) CanSchedule(params *ParamsCanRun) (*ResponseCanRun, error) {
+	loc.mu.Lock()
+	defer loc.mu.Unlock()
+
+	loc.expireHolds()
+
+	var errConflict ErrRunConflict
+
+	for range _BookingAttempts {
+		response, errCanSchedule := loc.canSchedule(params)
+		if errors.As(errCanSchedule, &errConflict) {
+			continue // booked directly on the resource meanwhile, evaluate again
+		}
+
+		return response,
+			errCanSchedule
+	}
+
+	return nil,
+		errConflict
+}
+
+// canSchedule should be called under the location lock.
+func (loc *Engine) canSchedule(params *ParamsCanRun) (*ResponseCanRun, error) {
 	possibilitiesResp, errGetPossibilities := loc.GetPossibilities(params)
 	if errGetPossibilities != nil {
 		return nil,
 			errGetPossibilities
 	}
 
-	// Find the best slot using the standard algorithm
-	result, errSchedulingOptions := loc.findBestSchedulingOption(possibilitiesResp, params)
-	if errSchedulingOptions != nil {
+	// Standard algorithm, with fallback when it finds nothing
+	option, errSchedulingOption := loc.findSchedulingOption(possibilitiesResp, params)
+	if errSchedulingOption != nil {
 		return nil,
-			errSchedulingOptions
+			errSchedulingOption
 	}
 
-	timeStart := params.TimeStart + possibilitiesResp.offsetedTimeInterval.SecondsOffset
-	targetTimeInterval := TimeInterval{
-		TimeStart:     timeStart,
-		TimeEnd:       timeStart + params.TaskRun.EstimatedDuration,
-		SecondsOffset: possibilitiesResp.offsetedTimeInterval.SecondsOffset,
-	}
+	if option.WhenCanStart != _NoAvailability {
+		interval := loc.toLocationTime(
+			&TimeInterval{
+				TimeStart:     option.WhenCanStart,
+				TimeEnd:       option.WhenCanStart + params.TaskRun.EstimatedDuration,
+				SecondsOffset: possibilitiesResp.taskTimeInterval.SecondsOffset,
+			},
+		)
 
-	// If we found a viable option with the standard algorithm
-	if result.WhenCanStart != _NoAvailability {
-		// Schedule if needed and return
-		if result.WhenCanStart == params.TimeStart {
-			loc.scheduleResources(
-				&paramsScheduleResources{
-					Resources:    result.SelectedResources,
-					TaskRunID:    RunID(params.TaskRun.ID),
-					TimeInterval: targetTimeInterval,
-				},
-			)
+		if option.WhenCanStart == possibilitiesResp.taskTimeInterval.TimeStart {
+			if errBook := loc.bookAtStart(possibilitiesResp, params, option.SelectedResources); errBook != nil {
+				return nil,
+					errBook
+			}
 
 			return &ResponseCanRun{
 					WhenCanStart: _ScheduledForStart,
-					Cost:         result.Cost,
+					Cost:         option.Cost,
 					WasScheduled: true,
+
+					runID:     RunID(params.TaskRun.ID),
+					resources: option.SelectedResources,
+					interval:  interval,
 				},
 				nil
 		}
 
 		return &ResponseCanRun{
-				WhenCanStart: result.WhenCanStart,
-				Cost:         result.Cost,
+				WhenCanStart: possibilitiesResp.toTaskTime(option.WhenCanStart),
+				Cost:         option.Cost,
 				WasScheduled: false,
-			},
-			nil
-	}
-
-	// Fallback algorithm when standard approach fails
-	fallbackResult := loc.findFallbackOption(possibilitiesResp, params)
-
-	// If fallback algorithm found an option and it's for immediate scheduling
-	if fallbackResult.WhenCanStart != _NoAvailability {
-		if fallbackResult.WhenCanStart == params.TimeStart {
-			loc.scheduleResources(
-				&paramsScheduleResources{
-					Resources:    fallbackResult.SelectedResources,
-					TaskRunID:    RunID(params.TaskRun.ID),
-					TimeInterval: targetTimeInterval,
-				},
-			)
-		}
 
-		return &ResponseCanRun{
-				WhenCanStart: fallbackResult.WhenCanStart,
-				Cost:         fallbackResult.Cost,
-				WasScheduled: fallbackResult.WhenCanStart == params.TimeStart,
+				runID:     RunID(params.TaskRun.ID),
+				resources: option.SelectedResources,
+				interval:  interval,
 			},
 			nil
 	}
@@ -156,7 +211,7 @@ func (loc *Location) CanSchedule(params *ParamsCanRun) (*ResponseCanRun, error)
 	// No viable options found
 	return &ResponseCanRun{
 			WhenCanStart: params.TimeEnd,
-			Cost:         0,
+			Cost:         Money{},
 			WasScheduled: false,
 		},
 		nil
@@ -0,0 +1,192 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestCanScheduleConcurrent books, moves, cancels, holds and confirms from many goroutines,
+// through two locations sharing a resource and directly on the resources,
+// and checks no booking overlaps another.
+// Run with -race.
+func TestCanScheduleConcurrent(t *testing.T) {
+	shared := newTestResource(1, 1, 1, nil)
+	resourceA := newTestResource(2, 1, 2, nil)
+	resourceB := newTestResource(3, 1, 3, nil)
+
+	locationA, errCrA := NewLocation(
+		&ParamsNewLocation{
+			ID:        1,
+			Name:      "A",
+			Resources: []*ResourceScheduled{shared, resourceA},
+		},
+	)
+	require.NoError(t, errCrA)
+
+	locationB, errCrB := NewLocation(
+		&ParamsNewLocation{
+			ID:        2,
+			Name:      "B",
+			Resources: []*ResourceScheduled{shared, resourceB},
+		},
+	)
+	require.NoError(t, errCrB)
+
+	const (
+		goroutines = 8
+		perRoutine = 20
+	)
+
+	var (
+		runID  atomic.Int64
+		booked atomic.Int64
+		wg     sync.WaitGroup
+
+		onceErr  sync.Once
+		errFirst error
+	)
+
+	// conflicts are expected, any other error fails the test
+	record := func(err error) {
+		if err == nil || errors.As(err, &ErrRunConflict{}) {
+			return
+		}
+
+		onceErr.Do(
+			func() {
+				errFirst = err
+			},
+		)
+	}
+
+	for routine := range goroutines {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			location := ternary(routine%2 == 0, locationA, locationB)
+
+			for ix := range perRoutine {
+				id := runID.Add(1)
+				start := now + int64(ix%5)*halfHour
+
+				params := ParamsCanRun{
+					TimeInterval: TimeInterval{
+						TimeStart: start,
+						TimeEnd:   start + oneHour,
+					},
+					TaskRun: newTestRun(id, halfHour, 1),
+				}
+
+				switch routine % 4 {
+				case 0: // directly on the shared resource
+					_, errAdd := shared.AddRun(
+						context.Background(),
+						&ParamsRun{
+							TimeInterval: TimeInterval{
+								TimeStart: start,
+								TimeEnd:   start + halfHour,
+							},
+							ID: RunID(id),
+						},
+					)
+					if errAdd == nil {
+						booked.Add(1)
+					}
+
+				case 1: // booked, moved and every other one cancelled
+					response, errSchedule := location.CanSchedule(&params)
+					record(errSchedule)
+
+					if errSchedule != nil || !response.WasScheduled {
+						continue
+					}
+
+					booked.Add(1)
+
+					record(
+						location.MoveRun(RunID(id), start+halfHour),
+					)
+
+					if ix%2 == 0 {
+						errCancel := location.CancelRun(RunID(id))
+						record(errCancel)
+
+						if errCancel == nil {
+							booked.Add(-1)
+						}
+					}
+
+				case 2: // held and confirmed
+					options, errGet := location.GetSchedulingOptions(&params)
+					record(errGet)
+
+					if errGet != nil || len(options) == 0 {
+						continue
+					}
+
+					token, errHold := location.Hold(options[0], time.Minute)
+					record(errHold)
+
+					if errHold != nil {
+						continue
+					}
+
+					errConfirm := location.Confirm(token)
+					record(errConfirm)
+
+					if errConfirm == nil {
+						booked.Add(1)
+					}
+
+				default:
+					response, errSchedule := location.CanSchedule(&params)
+					record(errSchedule)
+
+					if errSchedule == nil && response.WasScheduled {
+						booked.Add(1)
+					}
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	require.NoError(t, errFirst)
+
+	var bookings int64
+
+	for _, resource := range []*ResourceScheduled{shared, resourceA, resourceB} {
+		var previous *scheduleEntry
+
+		resource.schedule.ascend(
+			func(entry *scheduleEntry) bool {
+				if previous != nil {
+					require.LessOrEqual(t,
+						previous.utcEnd,
+						entry.utcStart,
+						resource.ID,
+					)
+				}
+
+				previous = entry
+				bookings++
+
+				return true
+			},
+		)
+	}
+
+	require.Equal(t,
+		booked.Load(),
+		bookings,
+	)
+}
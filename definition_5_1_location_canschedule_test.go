@@ -10,10 +10,8 @@ func TestCanSchedule(t *testing.T) {
 			ID:              1,
 			Name:            "Low Cost",
 			ResourceType:    1,
-			CostPerLoadUnit: map[uint8]float32{1: 2.0},
+			CostPerLoadUnit: map[uint8]Money{1: {Amount: 2}},
 		},
-
-		schedule: make(map[TimeInterval]RunID),
 	}
 
 	resourceHighCost := ResourceScheduled{
@@ -21,10 +19,8 @@ func TestCanSchedule(t *testing.T) {
 			ID:              2,
 			Name:            "High Cost",
 			ResourceType:    1,
-			CostPerLoadUnit: map[uint8]float32{1: 3.0},
+			CostPerLoadUnit: map[uint8]Money{1: {Amount: 3}},
 		},
-
-		schedule: make(map[TimeInterval]RunID),
 	}
 
 	resourceType2 := ResourceScheduled{
@@ -32,10 +28,8 @@ func TestCanSchedule(t *testing.T) {
 			ID:              3,
 			Name:            "Resource Type 2",
 			ResourceType:    2,
-			CostPerLoadUnit: map[uint8]float32{1: 1.0},
+			CostPerLoadUnit: map[uint8]Money{1: {Amount: 1}},
 		},
-
-		schedule: make(map[TimeInterval]RunID),
 	}
 
 	location := Location{
@@ -83,7 +77,7 @@ func TestCanSchedule(t *testing.T) {
 
 			expectedResult: ResponseCanRun{
 				WhenCanStart: _ScheduledForStart,
-				Cost:         2.0,
+				Cost:         Money{Amount: 2},
 				WasScheduled: true,
 			},
 		},
@@ -116,7 +110,7 @@ func TestCanSchedule(t *testing.T) {
 
 			expectedResult: ResponseCanRun{
 				WhenCanStart: _ScheduledForStart,
-				Cost:         5.0,
+				Cost:         Money{Amount: 5},
 				WasScheduled: true,
 			},
 		},
@@ -154,7 +148,7 @@ func TestCanSchedule(t *testing.T) {
 
 			expectedResult: ResponseCanRun{
 				WhenCanStart: _ScheduledForStart,
-				Cost:         3.0,
+				Cost:         Money{Amount: 3},
 				WasScheduled: true,
 			},
 		},
@@ -192,7 +186,7 @@ func TestCanSchedule(t *testing.T) {
 
 			expectedResult: ResponseCanRun{
 				WhenCanStart: _ScheduledForStart,
-				Cost:         6.0,
+				Cost:         Money{Amount: 6},
 				WasScheduled: true,
 			},
 		},
@@ -229,7 +223,7 @@ func TestCanSchedule(t *testing.T) {
 
 			expectedResult: ResponseCanRun{
 				WhenCanStart: now + oneHour,
-				Cost:         2.0,
+				Cost:         Money{Amount: 2},
 				WasScheduled: false,
 			},
 		},
@@ -266,7 +260,7 @@ func TestCanSchedule(t *testing.T) {
 
 			expectedResult: ResponseCanRun{
 				WhenCanStart: now + oneHour,
-				Cost:         5.0,
+				Cost:         Money{Amount: 5},
 				WasScheduled: false,
 			},
 		},
@@ -308,7 +302,7 @@ func TestCanSchedule(t *testing.T) {
 
 			expectedResult: ResponseCanRun{
 				WhenCanStart: now + oneHour,
-				Cost:         6.0,
+				Cost:         Money{Amount: 6},
 				WasScheduled: false,
 			},
 		},
@@ -341,7 +335,7 @@ func TestCanSchedule(t *testing.T) {
 
 			expectedResult: ResponseCanRun{
 				WhenCanStart: _ScheduledForStart,
-				Cost:         2.0, // Cheapest resource
+				Cost:         Money{Amount: 2}, // Cheapest resource
 				WasScheduled: true,
 			},
 		},
@@ -378,7 +372,7 @@ func TestCanSchedule(t *testing.T) {
 
 			expectedResult: ResponseCanRun{
 				WhenCanStart: _ScheduledForStart,
-				Cost:         3.0,
+				Cost:         Money{Amount: 3},
 				WasScheduled: true,
 			},
 		},
@@ -413,7 +407,7 @@ func TestCanSchedule(t *testing.T) {
 
 			expectedResult: ResponseCanRun{
 				WhenCanStart: now + oneHour,
-				Cost:         2.0,
+				Cost:         Money{Amount: 2},
 				WasScheduled: false,
 			},
 		},
@@ -455,7 +449,7 @@ func TestCanSchedule(t *testing.T) {
 
 			expectedResult: ResponseCanRun{
 				WhenCanStart: now + oneHour + halfHour,
-				Cost:         3.0,
+				Cost:         Money{Amount: 3},
 				WasScheduled: false,
 			},
 		},
@@ -500,7 +494,7 @@ func TestCanSchedule(t *testing.T) {
 
 			expectedResult: ResponseCanRun{
 				WhenCanStart: now + oneHour + halfHour,
-				Cost:         3.0,
+				Cost:         Money{Amount: 3},
 				WasScheduled: false,
 			},
 		},
@@ -534,7 +528,7 @@ func TestCanSchedule(t *testing.T) {
 
 			expectedResult: ResponseCanRun{
 				WhenCanStart: _ScheduledForStart, // can use higher cost resource
-				Cost:         3,
+				Cost:         Money{Amount: 3},
 				WasScheduled: true,
 			},
 		},
@@ -544,19 +538,9 @@ func TestCanSchedule(t *testing.T) {
 		t.Run(
 			tt.name,
 			func(t *testing.T) {
-				resourceLowCost.schedule = tt.scheduleResourceLowCost
-				resourceHighCost.schedule = ternary(
-					len(tt.scheduleResourceHighCost) > 0,
-
-					tt.scheduleResourceHighCost,
-					make(map[TimeInterval]RunID),
-				)
-				resourceType2.schedule = ternary(
-					len(tt.scheduleResourceType2) > 0,
-
-					tt.scheduleResourceType2,
-					make(map[TimeInterval]RunID),
-				)
+				resourceLowCost.schedule = newTestSchedule(tt.scheduleResourceLowCost)
+				resourceHighCost.schedule = newTestSchedule(tt.scheduleResourceHighCost)
+				resourceType2.schedule = newTestSchedule(tt.scheduleResourceType2)
 
 				location.Resources = []*ResourceScheduled{
 					&resourceLowCost,
@@ -18,11 +18,11 @@ func TestGetPossibilities(t *testing.T) {
 					ResourceInfo: ResourceInfo{
 						ID:              1,
 						Name:            "Resource 1",
-						CostPerLoadUnit: map[uint8]float32{1: 2.0},
+						CostPerLoadUnit: map[uint8]Money{1: {Amount: 2}},
 						ResourceType:    1,
 					},
 
-					schedule: map[TimeInterval]RunID{},
+					schedule: newTestSchedule(map[TimeInterval]RunID{}),
 				},
 			},
 		},
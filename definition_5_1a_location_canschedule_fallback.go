@@ -5,44 +5,39 @@ import (
 	"slices"
 )
 
-func (loc *Location) findFallbackOption(possibilitiesResp *ResponseGetPossibilities, params *ParamsCanRun) *SchedulingOption {
+func (loc *Engine) findFallbackOption(possibilitiesResp *ResponseGetPossibilities, params *ParamsCanRun) *SchedulingOption {
 	resourcesByType := make(map[uint8][]*ResourceScheduled)
 	earliestByResource := make(map[*ResourceScheduled]int64)
-	costByResource := make(map[*ResourceScheduled]float32)
 
 	// First gather availability information for all resources
 	for _, res := range loc.Resources {
-		if slices.Contains(possibilitiesResp.resourceTypesNeeded, res.ResourceType) {
-			when := res.findAvailableTime(
+		if slices.Contains(possibilitiesResp.resourceTypesNeeded, res.ResourceType) && params.TaskRun.supportsLoadUnit(res) {
+			whenTaskTime := res.findAvailableTime(
 				&paramsFindAvailableTime{
-					TimeStart:             possibilitiesResp.offsetedTimeInterval.TimeStart,
-					MaximumTimeStart:      possibilitiesResp.offsetedTimeInterval.TimeEnd + params.TaskRun.EstimatedDuration,
+					TimeStart:             possibilitiesResp.taskTimeInterval.TimeStart,
+					MaximumTimeStart:      possibilitiesResp.taskTimeInterval.TimeEnd + params.TaskRun.EstimatedDuration,
 					SecondsDuration:       params.TaskRun.EstimatedDuration,
-					SecondsOffsetTask:     params.SecondsOffset,
-					SecondsOffsetLocation: loc.LocationOffset,
+					SecondsOffsetTask:     possibilitiesResp.taskTimeInterval.SecondsOffset,
+					SecondsOffsetLocation: possibilitiesResp.offsetedTimeInterval.SecondsOffset,
+					OpenTimeIntervals:     possibilitiesResp.openTimeIntervals,
 				},
 			)
 
-			if when != _NoAvailability {
-				whenTaskTime := when - possibilitiesResp.offsetedTimeInterval.SecondsOffset
+			if whenTaskTime != _NoAvailability {
 				resourcesByType[res.ResourceType] = append(resourcesByType[res.ResourceType], res)
 				earliestByResource[res] = whenTaskTime
-
-				// Cache cost calculation
-				cost, _ := calculateTaskCost(params.TaskRun, res)
-				costByResource[res] = cost
 			}
 		}
 	}
 
 	// Check if we have enough resources of each type
 	for resourceType, needed := range possibilitiesResp.resourcesNeededPerType {
-		if len(resourcesByType[resourceType]) < int(needed) {
+		if getServedTotal(resourcesByType[resourceType]) < int(needed) {
 			// Not enough resources of this type available
 			return &SchedulingOption{
-				WhenCanStart:      params.TimeEnd,
+				WhenCanStart:      possibilitiesResp.taskTimeInterval.TimeEnd,
 				SelectedResources: nil,
-				Cost:              0,
+				Cost:              Money{},
 			}
 		}
 	}
@@ -68,16 +63,12 @@ func (loc *Location) findFallbackOption(possibilitiesResp *ResponseGetPossibilit
 	// Find the earliest time where we can schedule all required resources
 	earliestFallback := _NoAvailability
 	selectedCombination := make([]*ResourceScheduled, 0)
-	lowestCost := float32(math.MaxFloat32)
-
-	var totalNeeded int
-	for _, qty := range possibilitiesResp.resourcesNeededPerType {
-		totalNeeded = totalNeeded + int(qty)
-	}
+	var lowestCost Money
+	lowestRank := int64(math.MaxInt64) // cost with preference penalties
 
 	// For each possible start time
 	for _, startTime := range allTimes {
-		if startTime > params.TimeEnd {
+		if startTime > possibilitiesResp.taskTimeInterval.TimeEnd {
 			break
 		}
 
@@ -95,7 +86,7 @@ func (loc *Location) findFallbackOption(possibilitiesResp *ResponseGetPossibilit
 		// Check if we have enough resources of each type
 		hasEnough := true
 		for rType, needed := range possibilitiesResp.resourcesNeededPerType {
-			if len(availableResources[rType]) < int(needed) {
+			if getServedTotal(availableResources[rType]) < int(needed) {
 				hasEnough = false
 				break
 			}
@@ -105,24 +96,51 @@ func (loc *Location) findFallbackOption(possibilitiesResp *ResponseGetPossibilit
 			continue
 		}
 
+		slot := TimeInterval{
+			TimeStart:     startTime,
+			TimeEnd:       startTime + params.TaskRun.EstimatedDuration,
+			SecondsOffset: possibilitiesResp.taskTimeInterval.SecondsOffset,
+		}
+
+		// Cost of the resources starting at this time
+		pricing := loc.getPricing(params.TaskRun, &slot)
+		costByResource := make(map[*ResourceScheduled]Money)
+
+		for _, resources := range availableResources {
+			for _, res := range resources {
+				costByResource[res] = pricing.getResourceCost(res)
+			}
+		}
+
 		// Try all possible combinations of resources to find cheapest
 		combinations := generateCheapestCombinations(
 			&paramsGenerateCheapestCombinations{
 				AvailableResources:     availableResources,
 				ResourcesNeededPerType: possibilitiesResp.resourcesNeededPerType,
 				CostByResource:         costByResource,
+				Preferences:            possibilitiesResp.preferences,
+				Strategy:               loc.getStrategyFor(params),
+				Run:                    params.TaskRun,
+				Slot:                   slot,
 			},
 		)
 
 		for _, combo := range combinations {
+			if !possibilitiesResp.preferences.hasRequired(combo) {
+				continue
+			}
+
 			// Calculate total cost
-			comboCost := float32(0)
+			comboCost := Money{Currency: loc.Currency}
 
 			for _, res := range combo {
-				comboCost += costByResource[res]
+				comboCost = comboCost.add(costByResource[res])
 			}
 
-			if comboCost < lowestCost {
+			comboRank := comboCost.add(possibilitiesResp.preferences.getPenalty(combo)).Amount
+
+			if comboRank < lowestRank {
+				lowestRank = comboRank
 				lowestCost = comboCost
 				selectedCombination = combo
 				earliestFallback = startTime
@@ -130,16 +148,16 @@ func (loc *Location) findFallbackOption(possibilitiesResp *ResponseGetPossibilit
 		}
 
 		// If we found a valid combination, we can stop looking
-		if len(selectedCombination) == totalNeeded {
+		if earliestFallback != _NoAvailability {
 			break
 		}
 	}
 
-	if earliestFallback == _NoAvailability || len(selectedCombination) != totalNeeded {
+	if earliestFallback == _NoAvailability {
 		return &SchedulingOption{
-			WhenCanStart:      params.TimeEnd,
+			WhenCanStart:      possibilitiesResp.taskTimeInterval.TimeEnd,
 			SelectedResources: nil,
-			Cost:              0,
+			Cost:              Money{},
 		}
 	}
 
@@ -1,17 +1,27 @@
 package scheduler
 
-func (loc *Location) findBestSchedulingOption(possibilitiesResp *ResponseGetPossibilities, params *ParamsCanRun) (*SchedulingOption, error) {
-	var totalNeeded int
+func (loc *Engine) findBestSchedulingOption(possibilitiesResp *ResponseGetPossibilities, params *ParamsCanRun) (*SchedulingOption, error) {
+	possibilities := possibilitiesResp.Possibilities
 
-	for _, qty := range possibilitiesResp.resourcesNeededPerType {
-		totalNeeded = totalNeeded + int(qty)
+	if params.AllPossibilities {
+		// slots hold all available resources, select the ones serving the run
+		possibilities = make(ResourcesPerTimeInterval, len(possibilitiesResp.Possibilities))
+
+		for slot, resources := range possibilitiesResp.Possibilities {
+			if selected, serves := selectPerType(resources, possibilitiesResp.resourcesNeededPerType); serves {
+				possibilities[slot] = selected
+			}
+		}
 	}
 
 	earliest, selectedResources := findEarliestSlot(
 		&paramsFindEarliestSlot{
-			Possibilities:    possibilitiesResp.Possibilities,
-			NeededCount:      totalNeeded,
-			OffsetDifference: possibilitiesResp.offsetedTimeInterval.SecondsOffset,
+			Possibilities:    possibilities,
+			Preferences:      possibilitiesResp.preferences,
+			Strategy:         loc.getStrategyFor(params),
+			Run:              params.TaskRun,
+			Pricing:          loc.getPricing(nil, nil),
+			OffsetDifference: possibilitiesResp.offsetDifference,
 		},
 	)
 
@@ -21,15 +31,116 @@ func (loc *Location) findBestSchedulingOption(possibilitiesResp *ResponseGetPoss
 	}
 
 	if earliest != _NoAvailability {
-		for _, resource := range selectedResources {
-			cost, _ := calculateTaskCost(params.TaskRun, resource)
-			result.Cost += cost
-		}
+		result.Cost = loc.getPricing(
+			params.TaskRun,
+			&TimeInterval{
+				TimeStart:     earliest,
+				TimeEnd:       earliest + params.TaskRun.EstimatedDuration,
+				SecondsOffset: possibilitiesResp.taskTimeInterval.SecondsOffset,
+			},
+		).getCostTotal(selectedResources)
 	}
 
 	return result, nil
 }
 
+// findSchedulingOption tries the standard algorithm and falls back when it finds nothing.
+// It does not book; WhenCanStart is in task fixed offset time, see toTaskTime.
+func (loc *Engine) findSchedulingOption(possibilitiesResp *ResponseGetPossibilities, params *ParamsCanRun) (*SchedulingOption, error) {
+	result, errSchedulingOptions := loc.findBestSchedulingOption(possibilitiesResp, params)
+	if errSchedulingOptions != nil {
+		return nil,
+			errSchedulingOptions
+	}
+
+	if result.WhenCanStart != _NoAvailability {
+		return result,
+			nil
+	}
+
+	return loc.findFallbackOption(possibilitiesResp, params),
+		nil
+}
+
+// evaluate does not book, it only reports what CanSchedule would do.
+func (loc *Engine) evaluate(params *ParamsCanRun) (*LocationOption, error) {
+	possibilitiesResp, errGetPossibilities := loc.GetPossibilities(params)
+	if errGetPossibilities != nil {
+		return nil,
+			errGetPossibilities
+	}
+
+	option, errSchedulingOption := loc.findSchedulingOption(possibilitiesResp, params)
+	if errSchedulingOption != nil {
+		return nil,
+			errSchedulingOption
+	}
+
+	result := LocationOption{
+		Location:          loc,
+		SelectedResources: option.SelectedResources,
+		possibilities:     possibilitiesResp,
+		WhenCanStart:      possibilitiesResp.toTaskTime(option.WhenCanStart),
+		Cost:              option.Cost,
+	}
+
+	if option.WhenCanStart == _NoAvailability || len(option.SelectedResources) == 0 {
+		result.WhenCanStart = _NoAvailability
+		result.Cost = Money{}
+
+		return &result,
+			nil
+	}
+
+	taskTimeInterval := possibilitiesResp.taskTimeInterval
+
+	result.CanRunInInterval = option.WhenCanStart >= taskTimeInterval.TimeStart &&
+		option.WhenCanStart+params.TaskRun.EstimatedDuration <= taskTimeInterval.TimeEnd
+	result.CanRunAtStart = option.WhenCanStart == taskTimeInterval.TimeStart
+
+	return &result,
+		nil
+}
+
+// evaluateLocked is evaluate under the location lock, for callers outside of it.
+// Holds past their expiry are released first.
+func (loc *Engine) evaluateLocked(params *ParamsCanRun) (*LocationOption, error) {
+	loc.mu.Lock()
+	defer loc.mu.Unlock()
+
+	loc.expireHolds()
+
+	return loc.evaluate(params)
+}
+
+// bookAtStart books the resources for the run at the requested time start, in location time.
+// Returns ErrRunConflict if a resource was booked meanwhile by another caller.
+func (loc *Engine) bookAtStart(possibilitiesResp *ResponseGetPossibilities, params *ParamsCanRun, resources []*ResourceScheduled) error {
+	return loc.scheduleResources(
+		&paramsScheduleResources{
+			Resources: resources,
+			TaskRunID: RunID(params.TaskRun.ID),
+			TimeInterval: loc.getBookingInterval(
+				possibilitiesResp,
+				possibilitiesResp.offsetedTimeInterval.TimeStart,
+				params.TaskRun.EstimatedDuration,
+			),
+		},
+	)
+}
+
+// getBookingInterval returns the interval booked for a start in the location offset of the request,
+// as location time.
+func (loc *Engine) getBookingInterval(possibilitiesResp *ResponseGetPossibilities, timeStart, duration int64) TimeInterval {
+	return loc.toLocationTime(
+		&TimeInterval{
+			TimeStart:     timeStart,
+			TimeEnd:       timeStart + duration,
+			SecondsOffset: possibilitiesResp.offsetedTimeInterval.SecondsOffset,
+		},
+	)
+}
+
 type paramsScheduleResources struct {
 	Resources []*ResourceScheduled
 
@@ -37,12 +148,17 @@ type paramsScheduleResources struct {
 	TaskRunID RunID
 }
 
-func (loc *Location) scheduleResources(params *paramsScheduleResources) {
-	loc.mu.Lock()
-
-	for _, resource := range params.Resources {
-		resource.schedule[params.TimeInterval] = params.TaskRunID
+// scheduleResources should be called under the location lock.
+func (loc *Engine) scheduleResources(params *paramsScheduleResources) error {
+	if errBook := bookResources(
+		params.Resources,
+		&params.TimeInterval,
+		params.TaskRunID,
+	); errBook != nil {
+		return errBook
 	}
 
-	loc.mu.Unlock()
+	notifySelected(loc.getStrategy(), params.Resources)
+
+	return nil
 }
@@ -9,7 +9,12 @@ import (
 type SchedulingOption struct {
 	WhenCanStart      int64
 	SelectedResources []*ResourceScheduled
-	Cost              float32
+	Cost              Money
+	Waste             int // quantity served over the needed quantities
+
+	runID    RunID
+	interval TimeInterval // location time, as it would be booked
+	pricing  *runPricing  // of the location
 }
 
 func (so *SchedulingOption) String() string {
@@ -21,7 +26,7 @@ func (so *SchedulingOption) String() string {
 		"SchedulingOption{\n"+
 			"  WhenCanStart: %d,\n"+
 			"  SelectedResources: [%s],\n"+
-			"  Cost: %.2f\n"+
+			"  Cost: %s\n"+
 			"}",
 		so.WhenCanStart,
 		strings.Join(resourcesStr, ", "),
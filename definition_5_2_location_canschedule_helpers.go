@@ -1,28 +1,141 @@
 package scheduler
 
-import "sort"
+import (
+	"context"
+	"iter"
+	"slices"
+)
+
+// getServedTotal is the quantity the resources serve together.
+func getServedTotal(resources []*ResourceScheduled) int {
+	var result int
+
+	for _, resource := range resources {
+		result = result + int(resource.getServedQuantity())
+	}
+
+	return result
+}
+
+// getWaste is the quantity the resources serve over the needed quantity of each type.
+func getWaste(resources []*ResourceScheduled, neededPerType map[uint8]uint16) int {
+	var result int
+
+	for resourceType, resourcesOfType := range newResourcesPerType(resources) {
+		if waste := getServedTotal(resourcesOfType) - int(neededPerType[resourceType]); waste > 0 {
+			result = result + waste
+		}
+	}
+
+	return result
+}
+
+// selectServing selects the ranked resources serving the needed quantity with the least waste,
+// the best ranked ones among those wasting as little.
+// Returns false if all of them do not serve it.
+func selectServing(ranked []*ResourceScheduled, needed uint16) ([]*ResourceScheduled, bool) {
+	if getServedTotal(ranked) < int(needed) {
+		return ranked,
+			false
+	}
+
+	// a selection without a resource it could do without serves less than needed plus the largest quantity
+	var largest int
+
+	for _, resource := range ranked {
+		if quantity := int(resource.getServedQuantity()); quantity > largest {
+			largest = quantity
+		}
+	}
+
+	limit := int(needed) + largest
+
+	// reachable[ix][quantity] is true if some resources from ix on serve exactly the quantity
+	reachable := make([][]bool, len(ranked)+1)
+	reachable[len(ranked)] = make([]bool, limit+1)
+	reachable[len(ranked)][0] = true
+
+	for ix := len(ranked) - 1; ix >= 0; ix-- {
+		quantity := int(ranked[ix].getServedQuantity())
+
+		reachable[ix] = slices.Clone(reachable[ix+1])
+
+		for total := quantity; total <= limit; total++ {
+			reachable[ix][total] = reachable[ix][total] || reachable[ix+1][total-quantity]
+		}
+	}
+
+	// the least quantity served at or over the needed one
+	target := int(needed)
+
+	for !reachable[0][target] {
+		target++
+	}
+
+	result := make([]*ResourceScheduled, 0)
+
+	for ix, resource := range ranked {
+		if quantity := int(resource.getServedQuantity()); quantity <= target && reachable[ix+1][target-quantity] {
+			result = append(result, resource)
+			target = target - quantity
+		}
+	}
+
+	return result,
+		true
+}
+
+// selectPerType selects, per needed type, the resources serving the quantity in the order passed.
+func selectPerType(resources []*ResourceScheduled, neededPerType map[uint8]uint16) ([]*ResourceScheduled, bool) {
+	byType := newResourcesPerType(resources)
+
+	result := make([]*ResourceScheduled, 0)
+
+	for _, resourceType := range sortedKeys(neededPerType) {
+		selected, serves := selectServing(byType[resourceType], neededPerType[resourceType])
+		if !serves {
+			return nil,
+				false
+		}
+
+		result = append(result, selected...)
+	}
+
+	return result,
+		true
+}
 
 type paramsGenerateCheapestCombinations struct {
 	AvailableResources     ResourcesPerType
 	ResourcesNeededPerType map[uint8]uint16
-	CostByResource         map[*ResourceScheduled]float32
+	CostByResource         map[*ResourceScheduled]Money
+	Preferences            *resourcePreferences
+	Strategy               SelectionStrategy
+	Run                    *Run
+	Slot                   TimeInterval
 }
 
 func generateCheapestCombinations(params *paramsGenerateCheapestCombinations) [][]*ResourceScheduled {
-	// For each resource type, sort by cost
+	strategy := getStrategy(params.Strategy, Cheapest{})
+
+	// For each resource type, rank by the strategy
 	for resourceType, resources := range params.AvailableResources {
-		sort.Slice(
-			resources,
-			func(i, j int) bool {
-				return params.CostByResource[resources[i]] < params.CostByResource[resources[j]]
+		strategy.Rank(
+			&SelectionContext{
+				Run:  params.Run,
+				Slot: params.Slot,
+				Cost: func(resource *ResourceScheduled) Money {
+					return params.CostByResource[resource]
+				},
+
+				preferences: params.Preferences,
 			},
+			resources,
 		)
 
-		// Keep only the N cheapest resources we need
-		needed := int(params.ResourcesNeededPerType[resourceType])
-
-		if needed > 0 && len(resources) > needed {
-			params.AvailableResources[resourceType] = resources[:needed]
+		// Keep only the best ranked resources serving the quantity we need
+		if needed := params.ResourcesNeededPerType[resourceType]; needed > 0 {
+			params.AvailableResources[resourceType], _ = selectServing(resources, needed)
 		}
 	}
 
@@ -30,15 +143,144 @@ func generateCheapestCombinations(params *paramsGenerateCheapestCombinations) []
 	var cheapestCombo []*ResourceScheduled
 
 	for resourceType, resources := range params.AvailableResources {
-		needed := int(params.ResourcesNeededPerType[resourceType])
-
-		if needed > 0 {
+		if params.ResourcesNeededPerType[resourceType] > 0 {
 			cheapestCombo = append(
 				cheapestCombo,
-				resources[:needed]...,
+				resources...,
 			)
 		}
 	}
 
 	return [][]*ResourceScheduled{cheapestCombo}
 }
+
+type paramsGenerateAllValidCombinations struct {
+	AvailableResourcesByType ResourcesPerType
+	ResourcesNeededPerType   map[uint8]uint16
+
+	UpTo int // cap the number of combinations returned if number greater than zero.
+}
+
+func generateAllValidCombinations(params *paramsGenerateAllValidCombinations) []ResourcesPerType {
+	result := make([]ResourcesPerType, 0)
+
+	for combination := range seqAllValidCombinations(
+		context.Background(),
+		params.AvailableResourcesByType,
+		params.ResourcesNeededPerType,
+	) {
+		result = append(result, combination)
+
+		if params.UpTo > 0 && len(result) >= params.UpTo {
+			break
+		}
+	}
+
+	return result
+}
+
+// seqAllValidCombinations yields the combinations of resources serving the needed quantity of each needed type,
+// types in ascending order, resources of a type in the order passed.
+// Yields none if a needed type has no resources. Stops when ctx is done.
+func seqAllValidCombinations(ctx context.Context, availableResourcesByType ResourcesPerType, resourcesNeededPerType map[uint8]uint16) iter.Seq[ResourcesPerType] {
+	resourceTypes := sortedKeys(resourcesNeededPerType)
+
+	return func(yield func(ResourcesPerType) bool) {
+		current := make(ResourcesPerType)
+
+		// backtrack returns false once the consumer stopped
+		var backtrack func(typeIndex int) bool
+
+		backtrack = func(typeIndex int) bool {
+			// Base case: we've processed all resource types
+			if typeIndex >= len(resourceTypes) {
+				combination := make(ResourcesPerType, len(current))
+
+				for resourceType, resources := range current {
+					combination[resourceType] = resources
+				}
+
+				return yield(combination)
+			}
+
+			resourceType := resourceTypes[typeIndex]
+
+			for resources := range seqResourceCombinations(
+				ctx,
+				availableResourcesByType[resourceType],
+				resourcesNeededPerType[resourceType],
+			) {
+				current[resourceType] = resources
+
+				if !backtrack(typeIndex + 1) {
+					return false
+				}
+			}
+
+			delete(current, resourceType)
+
+			return true
+		}
+
+		backtrack(0)
+	}
+}
+
+// seqResourceCombinations yields the combinations of resources serving the needed quantity
+// without a resource they could do without, overshoot allowed. Stops when ctx is done,
+// also while no combination is found.
+func seqResourceCombinations(ctx context.Context, resources []*ResourceScheduled, neededQuantity uint16) iter.Seq[[]*ResourceScheduled] {
+	return func(yield func([]*ResourceScheduled) bool) {
+		current := make([]*ResourceScheduled, 0)
+
+		// backtrack returns false once the consumer stopped
+		var backtrack func(int, int) bool
+
+		backtrack = func(start int, remainingNeeded int) bool {
+			if ctx.Err() != nil {
+				return false
+			}
+
+			// If we've met the quantity requirement
+			if remainingNeeded <= 0 {
+				if isMinimalCover(current, neededQuantity) {
+					return yield(slices.Clone(current))
+				}
+
+				return true
+			}
+
+			// Try each remaining resource
+			for i := start; i < len(resources); i++ {
+				current = append(current, resources[i])
+
+				if !backtrack(i+1, remainingNeeded-int(resources[i].getServedQuantity())) {
+					return false
+				}
+
+				current = current[:len(current)-1] // Backtrack
+			}
+
+			return true
+		}
+
+		backtrack(0, int(neededQuantity))
+	}
+}
+
+// isMinimalCover returns true if the resources serve the needed quantity and none of them can be left out.
+func isMinimalCover(resources []*ResourceScheduled, neededQuantity uint16) bool {
+	served := getServedTotal(resources)
+
+	if served < int(neededQuantity) {
+		return false
+	}
+
+	for _, resource := range resources {
+		if served-int(resource.getServedQuantity()) >= int(neededQuantity) {
+			return false
+		}
+	}
+
+	return len(resources) > 0 || neededQuantity == 0
+}
@@ -0,0 +1,287 @@
+package scheduler
+
+import (
+	"cmp"
+	"container/heap"
+	"fmt"
+	"slices"
+)
+
+// costedCombination is a combination of resources with its cost.
+type costedCombination struct {
+	resources []*ResourceScheduled
+	cost      Money
+}
+
+// combinationState is a partial combination of the candidates, by index in cost order.
+type combinationState struct {
+	indexes []int
+	served  int
+	cost    Money
+}
+
+// isMinimalCover returns true if no candidate of the state can be left out.
+func (s *combinationState) isMinimalCover(candidates []*ResourceScheduled, remaining int) bool {
+	for _, ix := range s.indexes {
+		if s.served-int(candidates[ix].getServedQuantity()) >= remaining {
+			return false
+		}
+	}
+
+	return true
+}
+
+// combinationQueue is a min heap of states by cost.
+type combinationQueue []*combinationState
+
+func (q combinationQueue) Len() int { return len(q) }
+
+func (q combinationQueue) Less(i, j int) bool {
+	if q[i].cost.Amount != q[j].cost.Amount {
+		return q[i].cost.Amount < q[j].cost.Amount
+	}
+
+	return slices.Compare(q[i].indexes, q[j].indexes) < 0
+}
+
+func (q combinationQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *combinationQueue) Push(x any) { *q = append(*q, x.(*combinationState)) }
+
+func (q *combinationQueue) Pop() any {
+	old := *q
+	result := old[len(old)-1]
+	*q = old[:len(old)-1]
+
+	return result
+}
+
+type paramsGetCheapestResourceCombinations struct {
+	Resources      []*ResourceScheduled
+	NeededQuantity uint16
+	CostOf         func(*ResourceScheduled) Money // not negative
+	Preferences    *resourcePreferences
+
+	K int
+}
+
+// getCheapestResourceCombinations returns up to K combinations serving the needed quantity,
+// overshoot allowed but no candidate they could do without, cheapest first, required resources always included.
+// Candidates are explored best first: a state grows by the next candidate or moves its last one to the next,
+// so states leave the queue by cost and only as many are expanded as the cheapest K need.
+func getCheapestResourceCombinations(params *paramsGetCheapestResourceCombinations) []*costedCombination {
+	if params.K <= 0 {
+		return nil
+	}
+
+	base := &costedCombination{
+		resources: make([]*ResourceScheduled, 0),
+	}
+
+	candidates := make([]*ResourceScheduled, 0, len(params.Resources))
+
+	var served int
+
+	for _, resource := range params.Resources {
+		if params.Preferences != nil && params.Preferences.required[resource.ID] {
+			base.resources = append(base.resources, resource)
+			base.cost = base.cost.add(params.CostOf(resource))
+			served = served + int(resource.getServedQuantity())
+
+			continue
+		}
+
+		candidates = append(candidates, resource)
+	}
+
+	remaining := int(params.NeededQuantity) - served
+
+	if remaining < 0 {
+		return nil
+	}
+
+	if remaining == 0 {
+		return []*costedCombination{base}
+	}
+
+	slices.SortStableFunc(
+		candidates,
+		func(a, b *ResourceScheduled) int {
+			if costA, costB := params.CostOf(a), params.CostOf(b); costA != costB {
+				return compareMoney(costA, costB)
+			}
+
+			return cmp.Compare(a.ID, b.ID)
+		},
+	)
+
+	// servedFrom is the quantity the candidates from the index on serve together
+	servedFrom := make([]int, len(candidates)+1)
+
+	for ix := len(candidates) - 1; ix >= 0; ix-- {
+		servedFrom[ix] = servedFrom[ix+1] + int(candidates[ix].getServedQuantity())
+	}
+
+	if servedFrom[0] < remaining {
+		return nil
+	}
+
+	queue := &combinationQueue{
+		{
+			indexes: []int{0},
+			served:  int(candidates[0].getServedQuantity()),
+			cost:    params.CostOf(candidates[0]),
+		},
+	}
+
+	result := make([]*costedCombination, 0, params.K)
+
+	for queue.Len() > 0 && len(result) < params.K {
+		state := heap.Pop(queue).(*combinationState)
+		last := state.indexes[len(state.indexes)-1]
+
+		if state.served >= remaining && state.isMinimalCover(candidates, remaining) {
+			combination := &costedCombination{
+				resources: slices.Clone(base.resources),
+				cost:      base.cost.add(state.cost),
+			}
+
+			for _, ix := range state.indexes {
+				combination.resources = append(combination.resources, candidates[ix])
+			}
+
+			result = append(result, combination)
+		}
+
+		next := last + 1
+		if next >= len(candidates) {
+			continue
+		}
+
+		// grow, all states after it keep this one as prefix so it must be able to reach the quantity
+		// and not serve it already
+		if state.served < remaining && state.served+servedFrom[next] >= remaining {
+			heap.Push(
+				queue,
+				&combinationState{
+					indexes: append(slices.Clone(state.indexes), next),
+					served:  state.served + int(candidates[next].getServedQuantity()),
+					cost:    state.cost.add(params.CostOf(candidates[next])),
+				},
+			)
+		}
+
+		// move the last candidate to the next one, states after it take candidates from the next on
+		servedPrefix := state.served - int(candidates[last].getServedQuantity())
+
+		if servedPrefix+servedFrom[next] >= remaining {
+			indexes := slices.Clone(state.indexes)
+			indexes[len(indexes)-1] = next
+
+			var cost Money
+
+			for _, ix := range indexes {
+				cost = cost.add(params.CostOf(candidates[ix]))
+			}
+
+			heap.Push(
+				queue,
+				&combinationState{
+					indexes: indexes,
+					served:  servedPrefix + int(candidates[next].getServedQuantity()),
+					cost:    cost,
+				},
+			)
+		}
+	}
+
+	return result
+}
+
+type paramsGetCheapestCombinations struct {
+	AvailableResourcesByType ResourcesPerType
+	ResourcesNeededPerType   map[uint8]uint16
+	CostOf                   func(*ResourceScheduled) Money // not negative
+	Preferences              *resourcePreferences
+
+	K int
+}
+
+// getCheapestCombinations returns up to K combinations serving the needed quantity of each type, cheapest first.
+// The K cheapest of each type are merged best first over their indexes.
+func getCheapestCombinations(params *paramsGetCheapestCombinations) []*costedCombination {
+	resourceTypes := sortedKeys(params.ResourcesNeededPerType)
+
+	perType := make([][]*costedCombination, len(resourceTypes))
+
+	for ix, resourceType := range resourceTypes {
+		perType[ix] = getCheapestResourceCombinations(
+			&paramsGetCheapestResourceCombinations{
+				Resources:      params.AvailableResourcesByType[resourceType],
+				NeededQuantity: params.ResourcesNeededPerType[resourceType],
+				CostOf:         params.CostOf,
+				Preferences:    params.Preferences,
+
+				K: params.K,
+			},
+		)
+
+		if len(perType[ix]) == 0 {
+			return nil
+		}
+	}
+
+	newState := func(indexes []int) *combinationState {
+		result := combinationState{
+			indexes: indexes,
+		}
+
+		for ix, position := range indexes {
+			result.cost = result.cost.add(perType[ix][position].cost)
+		}
+
+		return &result
+	}
+
+	queue := &combinationQueue{
+		newState(make([]int, len(resourceTypes))),
+	}
+
+	visited := map[string]bool{
+		fmt.Sprint((*queue)[0].indexes): true,
+	}
+
+	result := make([]*costedCombination, 0, params.K)
+
+	for queue.Len() > 0 && len(result) < params.K {
+		state := heap.Pop(queue).(*combinationState)
+
+		combination := &costedCombination{
+			resources: make([]*ResourceScheduled, 0),
+			cost:      state.cost,
+		}
+
+		for ix, position := range state.indexes {
+			combination.resources = append(combination.resources, perType[ix][position].resources...)
+		}
+
+		result = append(result, combination)
+
+		for ix := range state.indexes {
+			if state.indexes[ix]+1 >= len(perType[ix]) {
+				continue
+			}
+
+			indexes := slices.Clone(state.indexes)
+			indexes[ix]++
+
+			if key := fmt.Sprint(indexes); !visited[key] {
+				visited[key] = true
+
+				heap.Push(queue, newState(indexes))
+			}
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,179 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheapestResourceCombinations(t *testing.T) {
+	newResources := func(resourceType uint8, number int) []*ResourceScheduled {
+		result := make([]*ResourceScheduled, number)
+
+		for ix := range result {
+			result[ix] = &ResourceScheduled{
+				ResourceInfo: ResourceInfo{
+					ID:              int(resourceType)*100 + ix + 1,
+					Name:            fmt.Sprintf("Resource %d", ix+1),
+					CostPerLoadUnit: map[uint8]Money{1: {Amount: int64((ix*7)%5 + 1)}},
+					ResourceType:    resourceType,
+					ServedQuantity:  uint16(ix%3 + 1),
+				},
+			}
+		}
+
+		return result
+	}
+
+	costOf := func(resource *ResourceScheduled) Money {
+		return resource.CostPerLoadUnit[1]
+	}
+
+	getCosts := func(combinations []*costedCombination) []int64 {
+		result := make([]int64, len(combinations))
+
+		for ix, combination := range combinations {
+			result[ix] = combination.cost.Amount
+		}
+
+		return result
+	}
+
+	// getAllCosts are the costs of all combinations, enumerated.
+	getAllCosts := func(resources []*ResourceScheduled, needed uint16, k int) []int64 {
+		result := make([]int64, 0)
+
+		for combination := range seqResourceCombinations(context.Background(), resources, needed) {
+			var cost int64
+
+			for _, resource := range combination {
+				cost = cost + costOf(resource).Amount
+			}
+
+			result = append(result, cost)
+		}
+
+		slices.Sort(result)
+
+		return result[:min(int64(k), int64(len(result)))]
+	}
+
+	t.Run(
+		"1. Same costs as enumeration",
+		func(t *testing.T) {
+			resources := newResources(1, 12)
+
+			for _, needed := range []uint16{1, 4, 7, 13} {
+				combinations := getCheapestResourceCombinations(
+					&paramsGetCheapestResourceCombinations{
+						Resources:      resources,
+						NeededQuantity: needed,
+						CostOf:         costOf,
+
+						K: 10,
+					},
+				)
+
+				require.Equal(t,
+					getAllCosts(resources, needed, 10),
+					getCosts(combinations),
+					needed,
+				)
+
+				for _, combination := range combinations {
+					require.True(t,
+						isMinimalCover(combination.resources, needed),
+						needed,
+					)
+				}
+			}
+		},
+	)
+
+	t.Run(
+		"2. Required included",
+		func(t *testing.T) {
+			resources := newResources(1, 6)
+
+			combinations := getCheapestResourceCombinations(
+				&paramsGetCheapestResourceCombinations{
+					Resources:      resources,
+					NeededQuantity: 4,
+					CostOf:         costOf,
+					Preferences: &resourcePreferences{
+						required: map[int]bool{resources[5].ID: true},
+					},
+
+					K: 3,
+				},
+			)
+			require.NotEmpty(t, combinations)
+
+			for _, combination := range combinations {
+				require.Contains(t,
+					combination.resources,
+					resources[5],
+				)
+			}
+		},
+	)
+
+	t.Run(
+		"3. Quantity not served",
+		func(t *testing.T) {
+			require.Empty(t,
+				getCheapestResourceCombinations(
+					&paramsGetCheapestResourceCombinations{
+						Resources:      newResources(1, 2),
+						NeededQuantity: 4,
+						CostOf:         costOf,
+
+						K: 3,
+					},
+				),
+			)
+		},
+	)
+
+	t.Run(
+		"4. Types merged cheapest first",
+		func(t *testing.T) {
+			resourcesFirst := newResources(1, 5)
+			resourcesSecond := newResources(2, 5)
+
+			combinations := getCheapestCombinations(
+				&paramsGetCheapestCombinations{
+					AvailableResourcesByType: ResourcesPerType{
+						1: resourcesFirst,
+						2: resourcesSecond,
+					},
+					ResourcesNeededPerType: map[uint8]uint16{
+						1: 2,
+						2: 3,
+					},
+					CostOf: costOf,
+
+					K: 6,
+				},
+			)
+
+			expected := make([]int64, 0)
+
+			for _, costFirst := range getAllCosts(resourcesFirst, 2, 100) {
+				for _, costSecond := range getAllCosts(resourcesSecond, 3, 100) {
+					expected = append(expected, costFirst+costSecond)
+				}
+			}
+
+			slices.Sort(expected)
+
+			require.Equal(t,
+				expected[:6],
+				getCosts(combinations),
+			)
+		},
+	)
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 )
 
+// ResourcesPerTimeInterval is keyed by slots in fixed offset, without zone, so keys compare by value.
 type ResourcesPerTimeInterval map[TimeInterval][]*ResourceScheduled
 
 func (rpt ResourcesPerTimeInterval) String() string {
@@ -65,6 +66,28 @@ func (rpt ResourcesPerType) GetResourceTypesSorted() []uint8 {
 	return result
 }
 
+// newResourcesPerType groups the resources per type, keeping their order.
+func newResourcesPerType(resources []*ResourceScheduled) ResourcesPerType {
+	result := make(ResourcesPerType)
+
+	for _, resource := range resources {
+		result[resource.ResourceType] = append(result[resource.ResourceType], resource)
+	}
+
+	return result
+}
+
+// getResources returns the resources of all types, in resource type order.
+func (rpt ResourcesPerType) getResources() []*ResourceScheduled {
+	result := make([]*ResourceScheduled, 0)
+
+	for _, resourceType := range rpt.GetResourceTypesSorted() {
+		result = append(result, rpt[resourceType]...)
+	}
+
+	return result
+}
+
 func (rpt ResourcesPerType) String() string {
 	var sb strings.Builder
 	sb.WriteString("ResourcesPerType{\n")
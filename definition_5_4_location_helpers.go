@@ -1,50 +1,48 @@
 package scheduler
 
-import (
-	"math"
-	"sort"
-)
+import "slices"
 
 type paramsFindEarliestSlot struct {
 	Possibilities    ResourcesPerTimeInterval
-	NeededCount      int
+	Preferences      *resourcePreferences
+	Strategy         SelectionStrategy
+	Run              *Run
+	Pricing          *runPricing // of the location, nil prices without calendars
+	NeededCount      int         // zero takes all resources of the slot
 	OffsetDifference int64
 }
 
+// findEarliestSlot returns the slot the strategy selects, start in task fixed offset time.
 func findEarliestSlot(params *paramsFindEarliestSlot) (int64, []*ResourceScheduled) {
-	earliest := int64(_NoAvailability)
-	bestResources := make([]*ResourceScheduled, 0)
-	bestCost := float32(math.MaxFloat32)
+	strategy := getStrategy(params.Strategy, Cheapest{})
+
+	var best *Selection
 
 	for slot, resources := range params.Possibilities {
-		if len(resources) >= params.NeededCount { // Ensure total quantity across types
-			start := slot.TimeStart - params.OffsetDifference
-			var totalCost float32
+		needed := ternary(params.NeededCount > 0, params.NeededCount, len(resources))
 
-			for _, res := range resources[:params.NeededCount] { // Take only needed
-				cost := res.CostPerLoadUnit[1] // Assuming LoadUnit 1
+		if len(resources) >= needed { // Ensure total quantity across types
+			cost := params.Pricing.at(params.Run, &slot).getCostTotal(resources[:needed])
 
-				totalCost += cost
+			candidate := Selection{
+				Resources:    resources[:needed], // Take only needed
+				WhenCanStart: slot.TimeStart - params.OffsetDifference,
+				Cost:         cost.add(params.Preferences.getPenalty(resources[:needed])),
 			}
 
-			if totalCost < bestCost || (totalCost == bestCost && (earliest == _NoAvailability || start < earliest)) {
-				bestCost = totalCost
-				earliest = start
-
-				sort.Slice(
-					resources,
-					func(i, j int) bool {
-						return resources[i].CostPerLoadUnit[1] < resources[j].CostPerLoadUnit[1]
-					},
-				)
-
-				bestResources = resources[:params.NeededCount]
+			if best == nil || strategy.Compare(&candidate, best) < 0 {
+				best = &candidate
 			}
 		}
 	}
 
-	return earliest,
-		bestResources
+	if best == nil {
+		return _NoAvailability,
+			make([]*ResourceScheduled, 0)
+	}
+
+	return best.WhenCanStart,
+		best.Resources
 }
 
 type paramsPopulatePossibilities struct {
@@ -53,18 +51,68 @@ type paramsPopulatePossibilities struct {
 
 	TimeInterval
 
+	OpenTimeIntervals *IntervalSet // nil if always open
+	Preferences       *resourcePreferences
+	Strategy          SelectionStrategy // nil is Cheapest
+	Run               *Run
+	Pricing           *runPricing // of the location, nil prices without calendars
+
 	Duration         int64
 	AllPossibilities bool
+
+	ContinuousStart bool  // starts where resources free up, not multiples of the duration
+	StartStep       int64 // with ContinuousStart, starts on this grid of the local clock if not zero
 }
 
 func populatePossibilities(params *paramsPopulatePossibilities) ResourcesPerTimeInterval {
+	strategy := getStrategy(params.Strategy, Cheapest{})
+
+	rank := func(slot TimeInterval, resources []*ResourceScheduled) {
+		strategy.Rank(
+			&SelectionContext{
+				Run:  params.Run,
+				Slot: slot,
+				Cost: params.Pricing.at(params.Run, &slot).getResourceCost,
+
+				preferences: params.Preferences,
+			},
+			resources,
+		)
+	}
+
 	result := make(ResourcesPerTimeInterval)
 	typeSlots := make(map[TimeInterval]ResourcesPerType)
 
+	var freeCandidates []*freeCandidate
+
 	for resourceType, candidates := range params.Candidates {
 		for _, candidate := range candidates {
 			availSlots, availableEntireInterval := candidate.GetAvailability(&params.TimeInterval)
 
+			if params.OpenTimeIntervals != nil {
+				availSlots, availableEntireInterval = restrictToOpenTime(
+					params.OpenTimeIntervals,
+					&params.TimeInterval,
+					availSlots,
+					availableEntireInterval,
+				)
+			}
+
+			if params.ContinuousStart {
+				freeCandidates = append(
+					freeCandidates,
+					&freeCandidate{
+						resource: candidate,
+						free: NewIntervalSet(
+							params.TimeInterval.SecondsOffset,
+							ternary(availableEntireInterval, []TimeInterval{params.TimeInterval}, availSlots)...,
+						),
+					},
+				)
+
+				continue
+			}
+
 			if availableEntireInterval {
 				noIntervals := params.TimeInterval.NoIntervals(params.Duration)
 
@@ -89,6 +137,10 @@ func populatePossibilities(params *paramsPopulatePossibilities) ResourcesPerTime
 					exactSlots := slot.BreakDown(params.Duration)
 
 					for _, exactSlot := range exactSlots {
+						if exactSlot.TimeEnd-exactSlot.TimeStart < params.Duration {
+							continue // shorter than the run, a start here overlaps the next booking
+						}
+
 						normalizedSlot := TimeInterval{
 							TimeStart:     exactSlot.TimeStart,
 							TimeEnd:       exactSlot.TimeEnd,
@@ -108,6 +160,28 @@ func populatePossibilities(params *paramsPopulatePossibilities) ResourcesPerTime
 		}
 	}
 
+	for _, timeStart := range getContinuousStarts(&params.TimeInterval, freeCandidates, params.Duration, params.StartStep) {
+		slot := TimeInterval{
+			TimeStart:     timeStart,
+			TimeEnd:       timeStart + params.Duration,
+			SecondsOffset: params.TimeInterval.SecondsOffset,
+		}
+
+		for _, candidate := range freeCandidates {
+			if !candidate.free.Contains(&slot) {
+				continue
+			}
+
+			current := typeSlots[slot]
+			if current == nil {
+				current = make(ResourcesPerType)
+				typeSlots[slot] = current
+			}
+
+			current[candidate.resource.ResourceType] = append(current[candidate.resource.ResourceType], candidate.resource)
+		}
+	}
+
 	// Filter slots based on AllPossibilities
 	for slot, resourcesByType := range typeSlots {
 		allSatisfied := true
@@ -115,38 +189,28 @@ func populatePossibilities(params *paramsPopulatePossibilities) ResourcesPerTime
 
 		if params.AllPossibilities {
 			// Include all available resources
-			for resourceType := range resourcesByType {
-				sort.Slice(
-					resourcesByType[resourceType],
-					func(i, j int) bool {
-						return resourcesByType[resourceType][i].CostPerLoadUnit[1] <
-							resourcesByType[resourceType][j].CostPerLoadUnit[1]
-					},
-				)
+			for _, resourceType := range resourcesByType.GetResourceTypesSorted() {
+				rank(slot, resourcesByType[resourceType])
 				slotResources = append(slotResources, resourcesByType[resourceType]...)
 			}
-			if len(slotResources) > 0 {
+			if len(slotResources) > 0 && params.Preferences.hasRequired(slotResources) {
 				result[slot] = slotResources
 			}
 		} else {
-			// Original logic: exact quantity needed
-			for resourceType, needed := range params.ResourcesNeededPerType {
-				if len(resourcesByType[resourceType]) < int(needed) {
+			// Best ranked resources serving the quantity needed
+			for _, resourceType := range sortedKeys(params.ResourcesNeededPerType) {
+				rank(slot, resourcesByType[resourceType])
+
+				selected, serves := selectServing(resourcesByType[resourceType], params.ResourcesNeededPerType[resourceType])
+				if !serves {
 					allSatisfied = false
 					break
 				}
 
-				sort.Slice(
-					resourcesByType[resourceType],
-					func(i, j int) bool {
-						return resourcesByType[resourceType][i].CostPerLoadUnit[1] <
-							resourcesByType[resourceType][j].CostPerLoadUnit[1]
-					},
-				)
-				slotResources = append(slotResources, resourcesByType[resourceType][:int(needed)]...)
+				slotResources = append(slotResources, selected...)
 			}
 
-			if allSatisfied {
+			if allSatisfied && params.Preferences.hasRequired(slotResources) {
 				result[slot] = slotResources
 			}
 		}
@@ -155,6 +219,66 @@ func populatePossibilities(params *paramsPopulatePossibilities) ResourcesPerTime
 	return result
 }
 
+// freeCandidate is a candidate resource with its free time in the search interval.
+type freeCandidate struct {
+	resource *ResourceScheduled
+	free     *IntervalSet
+}
+
+// getContinuousStarts returns the starts to search, where a candidate frees up
+// or, with a step, each point of the step grid on the local clock.
+// Starts leave room for the duration within the search interval.
+func getContinuousStarts(searchInterval *TimeInterval, candidates []*freeCandidate, duration, step int64) []int64 {
+	result := make([]int64, 0)
+
+	if len(candidates) == 0 || duration <= 0 {
+		return result
+	}
+
+	latestStart := searchInterval.TimeEnd - duration
+
+	if step > 0 {
+		// round up to the grid
+		for timeStart := searchInterval.TimeStart + (step-searchInterval.TimeStart%step)%step; timeStart <= latestStart; timeStart = timeStart + step {
+			result = append(result, timeStart)
+		}
+
+		return result
+	}
+
+	for _, candidate := range candidates {
+		for _, freeInterval := range candidate.free.Intervals() {
+			if timeStart := max(freeInterval.TimeStart, searchInterval.TimeStart); timeStart <= latestStart {
+				result = append(result, timeStart)
+			}
+		}
+	}
+
+	slices.Sort(result)
+
+	return slices.Compact(result)
+}
+
+// restrictToOpenTime intersects the availability of a resource with the open time,
+// returned as GetAvailability would.
+func restrictToOpenTime(open *IntervalSet, searchInterval *TimeInterval, availSlots []TimeInterval, availableEntireInterval bool) ([]TimeInterval, bool) {
+	available := NewIntervalSet(searchInterval.SecondsOffset, availSlots...)
+
+	if availableEntireInterval {
+		available = NewIntervalSet(searchInterval.SecondsOffset, *searchInterval)
+	}
+
+	available = available.Intersect(open)
+
+	if available.Contains(searchInterval) {
+		return nil,
+			true
+	}
+
+	return available.IntervalsAs(searchInterval),
+		false
+}
+
 // func populatePossibilities(params *paramsPopulatePossibilities) ResourcesPerTimeInterval {
 // 	result := make(ResourcesPerTimeInterval)
 // 	typeSlots := make(map[TimeInterval]ResourcesPerType)
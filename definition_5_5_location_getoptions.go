@@ -1,113 +1,144 @@
 package scheduler
 
-import "slices"
+import (
+	"cmp"
+	"slices"
+)
+
+// newSchedulingOption returns the option of the run on the selected resources, priced over the interval.
+func (loc *Engine) newSchedulingOption(run *Run, whenCanStart int64, interval TimeInterval, selectedResources []*ResourceScheduled) *SchedulingOption {
+	return &SchedulingOption{
+		WhenCanStart:      whenCanStart,
+		SelectedResources: selectedResources,
+		Cost:              loc.getPricing(run, &interval).getCostTotal(selectedResources),
+		Waste:             getWaste(selectedResources, run.GetNeededResourcesPerType()),
+
+		runID:    RunID(run.ID),
+		interval: interval,
+		pricing:  loc.getPricing(nil, nil),
+	}
+}
+
+// getTimeSlots returns the time slots of the possibilities by start.
+func getTimeSlots(possibilities ResourcesPerTimeInterval) []TimeInterval {
+	result := make([]TimeInterval, 0, len(possibilities))
+
+	for timeSlot := range possibilities {
+		result = append(result, timeSlot)
+	}
+
+	slices.SortFunc(
+		result,
+		func(a, b TimeInterval) int {
+			return cmp.Compare(a.TimeStart, b.TimeStart)
+		},
+	)
+
+	return result
+}
+
+// getResourceIDs returns the IDs of the resources in ascending order.
+func getResourceIDs(resources []*ResourceScheduled) []int {
+	result := make([]int, len(resources))
+
+	for ix, resource := range resources {
+		result[ix] = resource.ID
+	}
+
+	slices.Sort(result)
+
+	return result
+}
+
+// GetSchedulingOptions returns the options by start, without booking.
+// With AllPossibilities, all combinations of resources serving the needed quantities,
+// up to PossibilitiesUpTo per start if not zero, the least wasting and cheapest ones.
+// Options of a start come by waste, cost with preference penalties, then resource IDs.
+func (loc *Engine) GetSchedulingOptions(params *ParamsCanRun) ([]*SchedulingOption, error) {
+	loc.mu.Lock()
+	defer loc.mu.Unlock()
+
+	loc.expireHolds()
 
-func (loc *Location) GetSchedulingOptions(params *ParamsCanRun) ([]*SchedulingOption, error) {
 	possibilitiesResp, errGetPossibilities := loc.GetPossibilities(params)
 	if errGetPossibilities != nil {
 		return nil, errGetPossibilities
 	}
 
-	options := make([]*SchedulingOption, 0)
+	// same start, less waste, then options with preferred resources first
+	compareOptions := func(a, b *SchedulingOption) int {
+		if a.Waste != b.Waste {
+			return cmp.Compare(a.Waste, b.Waste)
+		}
 
-	for timeSlot, resources := range possibilitiesResp.Possibilities {
-		// Group resources by type
-		resourcesByType := make(ResourcesPerType)
+		costA := a.Cost.add(possibilitiesResp.preferences.getPenalty(a.SelectedResources))
+		costB := b.Cost.add(possibilitiesResp.preferences.getPenalty(b.SelectedResources))
 
-		for _, res := range resources {
-			resourcesByType[res.ResourceType] = append(
-				resourcesByType[res.ResourceType],
-				res,
-			)
+		if costA != costB {
+			return compareMoney(costA, costB)
 		}
 
-		// If AllPossibilities, generate combinations; otherwise, use needed quantities
-		if params.AllPossibilities {
-			needed := possibilitiesResp.resourcesNeededPerType
-			typeOptions := make([][]*ResourceScheduled, len(needed))
-			indexes := make([]int, len(needed))
-			typeKeys := make([]uint8, 0, len(needed))
+		return slices.Compare(
+			getResourceIDs(a.SelectedResources),
+			getResourceIDs(b.SelectedResources),
+		)
+	}
 
-			for rt := range needed {
-				typeKeys = append(typeKeys, rt)
-			}
-			slices.Sort(typeKeys) // Consistent order
+	options := make([]*SchedulingOption, 0)
 
-			// Prepare resource options per type
-			for i, rt := range typeKeys {
-				typeOptions[i] = resourcesByType[rt]
-			}
+	for _, timeSlot := range getTimeSlots(possibilitiesResp.Possibilities) {
+		resources := possibilitiesResp.Possibilities[timeSlot]
 
-			// Generate combinations
-			for {
-				var selectedResources []*ResourceScheduled
-				var cost float32
-
-				for i := range typeKeys {
-					if indexes[i] < len(typeOptions[i]) {
-						res := typeOptions[i][indexes[i]]
-						selectedResources = append(selectedResources, res)
-						resourceCost, _ := calculateTaskCost(params.TaskRun, res)
-						cost = cost + resourceCost
-					}
-				}
-
-				if len(selectedResources) == len(needed) { // Ensure full set
-					options = append(
-						options,
-						&SchedulingOption{
-							WhenCanStart:      timeSlot.TimeStart,
-							SelectedResources: selectedResources,
-							Cost:              cost,
-						},
-					)
-				}
-
-				// Next combination
-				for i := len(typeKeys) - 1; i >= 0; i-- {
-					indexes[i]++
-					if indexes[i] < len(typeOptions[i]) {
-						break
-					}
-
-					indexes[i] = 0
-					if i == 0 {
-						goto done
-					}
-				}
-			}
-		done:
-		} else {
-			// Original single-option logic
-			var cost float32
-
-			for _, resource := range resources {
-				resourceCost, _ := calculateTaskCost(params.TaskRun, resource)
-				cost = cost + resourceCost
-			}
+		whenCanStart := possibilitiesResp.toTaskTime(timeSlot.TimeStart - possibilitiesResp.offsetDifference)
+		interval := loc.getBookingInterval(possibilitiesResp, timeSlot.TimeStart, params.TaskRun.EstimatedDuration)
 
+		if !params.AllPossibilities {
 			options = append(
 				options,
-				&SchedulingOption{
-					WhenCanStart:      timeSlot.TimeStart,
-					SelectedResources: resources,
-					Cost:              cost,
-				},
+				loc.newSchedulingOption(params.TaskRun, whenCanStart, interval, resources),
 			)
+
+			continue
 		}
+
+		// all combinations serving the quantities, capped once ordered
+		optionsOfStart := make([]*SchedulingOption, 0)
+
+		for _, combination := range generateAllValidCombinations(
+			&paramsGenerateAllValidCombinations{
+				AvailableResourcesByType: newResourcesPerType(resources),
+				ResourcesNeededPerType:   possibilitiesResp.resourcesNeededPerType,
+			},
+		) {
+			selectedResources := combination.getResources()
+
+			if !possibilitiesResp.preferences.hasRequired(selectedResources) {
+				continue
+			}
+
+			optionsOfStart = append(
+				optionsOfStart,
+				loc.newSchedulingOption(params.TaskRun, whenCanStart, interval, selectedResources),
+			)
+		}
+
+		slices.SortStableFunc(optionsOfStart, compareOptions)
+
+		if upTo := int(params.PossibilitiesUpTo); upTo > 0 && len(optionsOfStart) > upTo {
+			optionsOfStart = optionsOfStart[:upTo]
+		}
+
+		options = append(options, optionsOfStart...)
 	}
 
-	slices.SortFunc(
+	slices.SortStableFunc(
 		options,
 		func(a, b *SchedulingOption) int {
-			if a.WhenCanStart < b.WhenCanStart {
-				return -1
-			}
-			if a.WhenCanStart > b.WhenCanStart {
-				return 1
+			if a.WhenCanStart != b.WhenCanStart {
+				return cmp.Compare(a.WhenCanStart, b.WhenCanStart)
 			}
 
-			return 0
+			return compareOptions(a, b)
 		},
 	)
 
@@ -18,48 +18,48 @@ func TestGetSchedulingOptions(t *testing.T) {
 					ResourceInfo: ResourceInfo{
 						ID:              1,
 						Name:            "Resource 1",
-						CostPerLoadUnit: map[uint8]float32{1: 2.0},
+						CostPerLoadUnit: map[uint8]Money{1: {Amount: 2}},
 						ResourceType:    1,
 					},
 
-					schedule: map[TimeInterval]RunID{
+					schedule: newTestSchedule(map[TimeInterval]RunID{
 						{TimeStart: now, TimeEnd: now + halfHour}:                     Maintenance,
 						{TimeStart: now + oneHour, TimeEnd: now + oneHour + halfHour}: Maintenance,
-					},
+					}),
 				},
 				{
 					ResourceInfo: ResourceInfo{
 						ID:              2,
 						Name:            "Resource 2",
-						CostPerLoadUnit: map[uint8]float32{1: 3.0},
+						CostPerLoadUnit: map[uint8]Money{1: {Amount: 3}},
 						ResourceType:    1,
 					},
 
-					schedule: map[TimeInterval]RunID{
+					schedule: newTestSchedule(map[TimeInterval]RunID{
 						{TimeStart: now + oneHour, TimeEnd: now + oneHour + halfHour}: Maintenance,
-					},
+					}),
 				},
 				{
 					ResourceInfo: ResourceInfo{
 						ID:              3,
 						Name:            "Resource 3",
-						CostPerLoadUnit: map[uint8]float32{1: 2.0},
+						CostPerLoadUnit: map[uint8]Money{1: {Amount: 2}},
 						ResourceType:    1,
 					},
 
-					schedule: map[TimeInterval]RunID{
+					schedule: newTestSchedule(map[TimeInterval]RunID{
 						{TimeStart: now, TimeEnd: now + halfHour}: Maintenance,
-					},
+					}),
 				},
 				{
 					ResourceInfo: ResourceInfo{
 						ID:              4,
 						Name:            "Resource 4",
-						CostPerLoadUnit: map[uint8]float32{1: 1.0},
+						CostPerLoadUnit: map[uint8]Money{1: {Amount: 1}},
 						ResourceType:    2,
 					},
 
-					schedule: map[TimeInterval]RunID{},
+					schedule: newTestSchedule(map[TimeInterval]RunID{}),
 				},
 			},
 		},
@@ -105,3 +105,83 @@ func TestGetSchedulingOptions(t *testing.T) {
 		options,
 	)
 }
+
+func TestGetSchedulingOptionsOrder(t *testing.T) {
+	t.Run(
+		"1. Same options, same order, on every call",
+		func(t *testing.T) {
+			location := newTestLocation(t,
+				newTestResource(1, 1, 1, nil),
+				newTestResource(2, 1, 1, nil),
+				newTestResource(3, 1, 1, nil),
+				newTestResource(4, 1, 1, nil),
+			)
+
+			run := newTestRun(1, halfHour, 1)
+			run.Dependencies[0].ResourceQuantity = 2
+
+			params := ParamsCanRun{
+				TimeInterval: TimeInterval{
+					TimeStart: now,
+					TimeEnd:   now + 2*oneHour,
+				},
+				TaskRun:          run,
+				AllPossibilities: true,
+			}
+
+			describe := func(options []*SchedulingOption) []string {
+				result := make([]string, len(options))
+
+				for ix, option := range options {
+					result[ix] = fmt.Sprint(option.WhenCanStart, getResourceIDs(option.SelectedResources))
+				}
+
+				return result
+			}
+
+			first, errFirst := location.GetSchedulingOptions(&params)
+			require.NoError(t, errFirst)
+			require.NotEmpty(t, first)
+
+			for range 20 {
+				again, errAgain := location.GetSchedulingOptions(&params)
+				require.NoError(t, errAgain)
+				require.Equal(t,
+					describe(first),
+					describe(again),
+				)
+			}
+		},
+	)
+
+	t.Run(
+		"2. Capped per start, least waste kept",
+		func(t *testing.T) {
+			location := newTestLocation(t,
+				newTestResourceServing(1, 2, 1), // cheaper, wastes one
+				newTestResourceServing(2, 1, 5),
+			)
+
+			options, errGet := location.GetSchedulingOptions(
+				&ParamsCanRun{
+					TimeInterval: TimeInterval{
+						TimeStart: now,
+						TimeEnd:   now + oneHour,
+					},
+					TaskRun:           newTestRun(1, oneHour, 1),
+					AllPossibilities:  true,
+					PossibilitiesUpTo: 1,
+				},
+			)
+			require.NoError(t, errGet)
+			require.Len(t,
+				options,
+				1,
+			)
+			require.Equal(t,
+				[]int{2},
+				getResourceIDs(options[0].SelectedResources),
+			)
+		},
+	)
+}
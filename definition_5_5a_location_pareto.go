@@ -0,0 +1,261 @@
+package scheduler
+
+import (
+	"cmp"
+	"slices"
+
+	goerrors "github.com/TudorHulban/go-errors"
+)
+
+type ParamsParetoOptions struct {
+	ParamsCanRun
+
+	Utilization       bool  // compare also on the seconds booked on the selected resources
+	UtilizationWindow int64 // seconds before and after the option counted, zero counts all bookings
+}
+
+// ParetoWeights score the options of the front, each criterion normalized to [0, 1] over the front.
+// Lowest score is chosen, ties to the earliest then cheapest.
+type ParetoWeights struct {
+	Cost        float64
+	Start       float64
+	Utilization float64 // if compared
+}
+
+// paretoPoint holds the criteria of an option, lower is better.
+type paretoPoint struct {
+	option   *SchedulingOption
+	criteria []float64 // cost, start, utilization if compared
+}
+
+func (p *paretoPoint) dominates(other *paretoPoint) bool {
+	var isBetter bool
+
+	for ix, value := range p.criteria {
+		if value > other.criteria[ix] {
+			return false
+		}
+
+		isBetter = isBetter || value < other.criteria[ix]
+	}
+
+	return isBetter
+}
+
+// getParetoFront returns the points no other point dominates, by start then cost.
+func getParetoFront(points []*paretoPoint) []*paretoPoint {
+	result := make([]*paretoPoint, 0)
+
+	for _, point := range points {
+		if !slices.ContainsFunc(
+			points,
+			func(other *paretoPoint) bool {
+				return other.dominates(point)
+			},
+		) {
+			result = append(result, point)
+		}
+	}
+
+	slices.SortStableFunc(
+		result,
+		func(a, b *paretoPoint) int {
+			if a.option.WhenCanStart != b.option.WhenCanStart {
+				return cmp.Compare(a.option.WhenCanStart, b.option.WhenCanStart)
+			}
+
+			return compareMoney(a.option.Cost, b.option.Cost)
+		},
+	)
+
+	return result
+}
+
+// chooseParetoPoint returns the point of the front with the lowest weighted score.
+func chooseParetoPoint(front []*paretoPoint, weights *ParetoWeights) *paretoPoint {
+	if len(front) == 0 {
+		return nil
+	}
+
+	weightsByCriterion := []float64{weights.Cost, weights.Start, weights.Utilization}
+
+	lows := slices.Clone(front[0].criteria)
+	highs := slices.Clone(front[0].criteria)
+
+	for _, point := range front {
+		for ix, value := range point.criteria {
+			if value < lows[ix] {
+				lows[ix] = value
+			}
+
+			if value > highs[ix] {
+				highs[ix] = value
+			}
+		}
+	}
+
+	score := func(point *paretoPoint) float64 {
+		var result float64
+
+		for ix, value := range point.criteria {
+			if highs[ix] > lows[ix] {
+				result = result + weightsByCriterion[ix]*(value-lows[ix])/(highs[ix]-lows[ix])
+			}
+		}
+
+		return result
+	}
+
+	result := front[0]
+	lowest := score(result)
+
+	for _, point := range front[1:] {
+		if current := score(point); current < lowest {
+			result, lowest = point, current
+		}
+	}
+
+	return result
+}
+
+// getParetoPoints returns the options with their criteria.
+func (loc *Engine) getParetoPoints(params *ParamsParetoOptions) ([]*paretoPoint, error) {
+	options, errGet := loc.GetSchedulingOptions(&params.ParamsCanRun)
+	if errGet != nil {
+		return nil,
+			errGet
+	}
+
+	result := make([]*paretoPoint, len(options))
+
+	for ix, option := range options {
+		result[ix] = &paretoPoint{
+			option: option,
+			criteria: []float64{
+				float64(option.Cost.Amount),
+				float64(option.WhenCanStart),
+			},
+		}
+
+		if params.Utilization {
+			result[ix].criteria = append(
+				result[ix].criteria,
+				float64(option.getBookedSeconds(params.UtilizationWindow)),
+			)
+		}
+	}
+
+	return result,
+		nil
+}
+
+// getBookedSeconds is the seconds booked on the selected resources within window seconds
+// around the option, all bookings if zero.
+func (so *SchedulingOption) getBookedSeconds(window int64) int64 {
+	var interval *TimeInterval
+
+	if window > 0 {
+		interval = &TimeInterval{
+			TimeStart:     so.interval.TimeStart - window,
+			TimeEnd:       so.interval.TimeEnd + window,
+			SecondsOffset: so.interval.SecondsOffset,
+			TimeZone:      so.interval.TimeZone,
+		}
+	}
+
+	var result int64
+
+	for _, resource := range so.SelectedResources {
+		result = result + resource.GetBookedSeconds(interval)
+	}
+
+	return result
+}
+
+// GetParetoOptions returns the options no other option beats on all of cost, start
+// and, if requested, utilization, by start. It does not book.
+// Without AllPossibilities there is one option per start, the one the location strategy selects,
+// set it for the front over all resource combinations.
+func (loc *Engine) GetParetoOptions(params *ParamsParetoOptions) ([]*SchedulingOption, error) {
+	points, errGet := loc.getParetoPoints(params)
+	if errGet != nil {
+		return nil,
+			errGet
+	}
+
+	front := getParetoFront(points)
+
+	result := make([]*SchedulingOption, len(front))
+
+	for ix, point := range front {
+		result[ix] = point.option
+	}
+
+	return result,
+		nil
+}
+
+// ChooseParetoOption returns the option of the Pareto front with the lowest weighted score,
+// nil if there are no options. It does not book.
+func (loc *Engine) ChooseParetoOption(params *ParamsParetoOptions, weights *ParetoWeights) (*SchedulingOption, error) {
+	if params == nil || weights == nil {
+		return nil,
+			goerrors.ErrValidation{
+				Caller: "ChooseParetoOption",
+				Issue: goerrors.ErrNilInput{
+					InputName: "params or weights",
+				},
+			}
+	}
+
+	points, errGet := loc.getParetoPoints(params)
+	if errGet != nil {
+		return nil,
+			errGet
+	}
+
+	chosen := chooseParetoPoint(getParetoFront(points), weights)
+	if chosen == nil {
+		return nil,
+			nil
+	}
+
+	return chosen.option,
+		nil
+}
+
+// GetParetoOptions is the engine GetParetoOptions with options per type.
+func (loc *Loco) GetParetoOptions(params *ParamsParetoOptions) (OptionsSchedule, error) {
+	engine, errEngine := loc.GetEngine()
+	if errEngine != nil {
+		return nil,
+			errEngine
+	}
+
+	options, errGet := engine.GetParetoOptions(params)
+	if errGet != nil {
+		return nil,
+			errGet
+	}
+
+	return toOptionsSchedule(options),
+		nil
+}
+
+// ChooseParetoOption is the engine ChooseParetoOption with option per type.
+func (loc *Loco) ChooseParetoOption(params *ParamsParetoOptions, weights *ParetoWeights) (*OptionSchedule, error) {
+	engine, errEngine := loc.GetEngine()
+	if errEngine != nil {
+		return nil,
+			errEngine
+	}
+
+	option, errChoose := engine.ChooseParetoOption(params, weights)
+	if errChoose != nil || option == nil {
+		return nil,
+			errChoose
+	}
+
+	return toOptionsSchedule([]*SchedulingOption{option})[0],
+		nil
+}
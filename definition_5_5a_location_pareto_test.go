@@ -0,0 +1,174 @@
+package scheduler
+
+import (
+	"testing"
+
+	goerrors "github.com/TudorHulban/go-errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParetoOptions(t *testing.T) {
+	newResources := func() []*ResourceScheduled {
+		return []*ResourceScheduled{
+			newTestResource(1, 1, 1, map[TimeInterval]RunID{
+				{TimeStart: now, TimeEnd: now + oneHour}: Maintenance,
+			}),
+			newTestResource(2, 1, 5, nil),
+		}
+	}
+
+	params := ParamsParetoOptions{
+		ParamsCanRun: ParamsCanRun{
+			TimeInterval: TimeInterval{
+				TimeStart: now,
+				TimeEnd:   now + 2*oneHour,
+			},
+
+			TaskRun: newTestRun(1, oneHour, 1),
+
+			AllPossibilities: true,
+		},
+	}
+
+	newEngine := func(t *testing.T) *Engine {
+		return newTestLocation(t, newResources()...)
+	}
+
+	t.Run(
+		"1. Front drops dominated options",
+		func(t *testing.T) {
+			engine := newEngine(t)
+
+			options, errGet := engine.GetSchedulingOptions(&params.ParamsCanRun)
+			require.NoError(t, errGet)
+			require.Len(t,
+				options,
+				3,
+			)
+
+			front, errGetFront := engine.GetParetoOptions(&params)
+			require.NoError(t, errGetFront)
+			require.Len(t,
+				front,
+				2,
+				"expensive later is dominated",
+			)
+
+			require.EqualValues(t,
+				now,
+				front[0].WhenCanStart,
+			)
+			require.Equal(t,
+				2,
+				front[0].SelectedResources[0].ID,
+			)
+
+			require.EqualValues(t,
+				now+oneHour,
+				front[1].WhenCanStart,
+			)
+			require.Equal(t,
+				1,
+				front[1].SelectedResources[0].ID,
+			)
+		},
+	)
+
+	t.Run(
+		"2. Utilization keeps the less booked option",
+		func(t *testing.T) {
+			engine := newEngine(t)
+
+			paramsUtilization := params
+			paramsUtilization.Utilization = true
+
+			front, errGet := engine.GetParetoOptions(&paramsUtilization)
+			require.NoError(t, errGet)
+			require.Len(t,
+				front,
+				2,
+			)
+			require.Equal(t,
+				1,
+				front[1].SelectedResources[0].ID,
+				"cheap but booked is still on the front",
+			)
+		},
+	)
+
+	t.Run(
+		"3. Weighted choice",
+		func(t *testing.T) {
+			engine := newEngine(t)
+
+			cheapest, errCheapest := engine.ChooseParetoOption(
+				&params,
+				&ParetoWeights{
+					Cost:  2,
+					Start: 1,
+				},
+			)
+			require.NoError(t, errCheapest)
+			require.Equal(t,
+				1,
+				cheapest.SelectedResources[0].ID,
+			)
+
+			earliest, errEarliest := engine.ChooseParetoOption(
+				&params,
+				&ParetoWeights{
+					Cost:  1,
+					Start: 2,
+				},
+			)
+			require.NoError(t, errEarliest)
+			require.EqualValues(t,
+				now,
+				earliest.WhenCanStart,
+			)
+
+			_, errNilWeights := engine.ChooseParetoOption(&params, nil)
+			require.ErrorAs(t,
+				errNilWeights,
+				&goerrors.ErrValidation{},
+			)
+		},
+	)
+
+	t.Run(
+		"4. Loco",
+		func(t *testing.T) {
+			loco := Loco{
+				ID:   1,
+				Name: t.Name(),
+
+				Resources: ResourcesPerType{
+					1: newResources(),
+				},
+			}
+
+			front, errGet := loco.GetParetoOptions(&params)
+			require.NoError(t, errGet)
+			require.Len(t,
+				front,
+				2,
+			)
+
+			chosen, errChoose := loco.ChooseParetoOption(
+				&params,
+				&ParetoWeights{
+					Start: 1,
+				},
+			)
+			require.NoError(t, errChoose)
+			require.EqualValues(t,
+				now,
+				chosen.WhenCanStart,
+			)
+			require.Equal(t,
+				2,
+				chosen.Resources[1][0].ID,
+			)
+		},
+	)
+}
@@ -0,0 +1,116 @@
+package scheduler
+
+import (
+	"cmp"
+	"context"
+	"iter"
+	"slices"
+)
+
+type ParamsSeqSchedulingOptions struct {
+	ParamsCanRun // AllPossibilities and PossibilitiesUpTo not used
+
+	UpTo         int // cap the number of options yielded if number greater than zero
+	UpToPerStart int // cap the number of options yielded per start if number greater than zero
+}
+
+// SeqSchedulingOptions returns all combinations of resources serving the needed quantities as they are generated,
+// without booking. Options come by start, then combination with resources of a type taken by ID.
+// Possibilities are taken at the call, the iteration stops when the consumer breaks, ctx is done or a cap is reached.
+func (loc *Engine) SeqSchedulingOptions(ctx context.Context, params *ParamsSeqSchedulingOptions) (iter.Seq[*SchedulingOption], error) {
+	loc.mu.Lock()
+	defer loc.mu.Unlock()
+
+	loc.expireHolds()
+
+	paramsPossibilities := params.ParamsCanRun
+	paramsPossibilities.AllPossibilities = true
+
+	possibilitiesResp, errGetPossibilities := loc.GetPossibilities(&paramsPossibilities)
+	if errGetPossibilities != nil {
+		return nil,
+			errGetPossibilities
+	}
+
+	timeSlots := getTimeSlots(possibilitiesResp.Possibilities)
+
+	run, upTo, upToPerStart := params.TaskRun, params.UpTo, params.UpToPerStart
+
+	return func(yield func(*SchedulingOption) bool) {
+			var yielded int
+
+			for _, timeSlot := range timeSlots {
+				if ctx.Err() != nil {
+					return
+				}
+
+				whenCanStart := possibilitiesResp.toTaskTime(timeSlot.TimeStart - possibilitiesResp.offsetDifference)
+				interval := loc.getBookingInterval(possibilitiesResp, timeSlot.TimeStart, run.EstimatedDuration)
+
+				resourcesByType := newResourcesPerType(possibilitiesResp.Possibilities[timeSlot])
+
+				for _, resources := range resourcesByType {
+					slices.SortStableFunc(
+						resources,
+						func(a, b *ResourceScheduled) int {
+							return cmp.Compare(a.ID, b.ID)
+						},
+					)
+				}
+
+				var yieldedPerStart int
+
+				for combination := range seqAllValidCombinations(
+					ctx,
+					resourcesByType,
+					possibilitiesResp.resourcesNeededPerType,
+				) {
+					selectedResources := combination.getResources()
+
+					if !possibilitiesResp.preferences.hasRequired(selectedResources) {
+						continue
+					}
+
+					if !yield(loc.newSchedulingOption(run, whenCanStart, interval, selectedResources)) {
+						return
+					}
+
+					yielded++
+					yieldedPerStart++
+
+					if upTo > 0 && yielded >= upTo {
+						return
+					}
+
+					if upToPerStart > 0 && yieldedPerStart >= upToPerStart {
+						break
+					}
+				}
+			}
+		},
+		nil
+}
+
+// SeqAllSchedulingOptions is the engine SeqSchedulingOptions with options per type.
+func (loc *Loco) SeqAllSchedulingOptions(ctx context.Context, params *ParamsSeqSchedulingOptions) (iter.Seq[*OptionSchedule], error) {
+	engine, errEngine := loc.GetEngine()
+	if errEngine != nil {
+		return nil,
+			errEngine
+	}
+
+	options, errSeq := engine.SeqSchedulingOptions(ctx, params)
+	if errSeq != nil {
+		return nil,
+			errSeq
+	}
+
+	return func(yield func(*OptionSchedule) bool) {
+			for option := range options {
+				if !yield(toOptionSchedule(option)) {
+					return
+				}
+			}
+		},
+		nil
+}
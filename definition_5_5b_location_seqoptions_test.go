@@ -0,0 +1,261 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeqSchedulingOptions(t *testing.T) {
+	const numberResources = 300
+
+	newResources := func() []*ResourceScheduled {
+		result := make([]*ResourceScheduled, 0, numberResources)
+
+		// reversed, options come by ID
+		for id := numberResources; id > 0; id-- {
+			result = append(result, newTestResource(id, 1, 1, nil))
+		}
+
+		return result
+	}
+
+	engine := newTestLocation(t, newResources()...)
+
+	params := ParamsSeqSchedulingOptions{
+		ParamsCanRun: ParamsCanRun{
+			TimeInterval: TimeInterval{
+				TimeStart: now,
+				TimeEnd:   now + oneHour,
+			},
+
+			TaskRun: newTestRun(1, oneHour, 1),
+		},
+	}
+
+	t.Run(
+		"1. All options, more than 255",
+		func(t *testing.T) {
+			options, errSeq := engine.SeqSchedulingOptions(t.Context(), &params)
+			require.NoError(t, errSeq)
+
+			var count int
+
+			for option := range options {
+				count++
+
+				require.Equal(t,
+					count,
+					option.SelectedResources[0].ID,
+					"deterministic order",
+				)
+			}
+
+			require.Equal(t,
+				numberResources,
+				count,
+			)
+		},
+	)
+
+	t.Run(
+		"2. Caps",
+		func(t *testing.T) {
+			paramsCapped := params
+			paramsCapped.UpTo = 270
+
+			options, errSeq := engine.SeqSchedulingOptions(t.Context(), &paramsCapped)
+			require.NoError(t, errSeq)
+
+			var count int
+
+			for range options {
+				count++
+			}
+
+			require.Equal(t,
+				270,
+				count,
+			)
+
+			paramsCapped.UpTo = 0
+			paramsCapped.UpToPerStart = 7
+
+			optionsPerStart, errSeqPerStart := engine.SeqSchedulingOptions(t.Context(), &paramsCapped)
+			require.NoError(t, errSeqPerStart)
+
+			var countPerStart int
+
+			for range optionsPerStart {
+				countPerStart++
+			}
+
+			require.Equal(t,
+				7,
+				countPerStart,
+				"single start",
+			)
+		},
+	)
+
+	t.Run(
+		"3. Consumer breaks",
+		func(t *testing.T) {
+			options, errSeq := engine.SeqSchedulingOptions(t.Context(), &params)
+			require.NoError(t, errSeq)
+
+			var count int
+
+			for range options {
+				count++
+
+				if count == 3 {
+					break
+				}
+			}
+
+			require.Equal(t,
+				3,
+				count,
+			)
+		},
+	)
+
+	t.Run(
+		"4. Context canceled",
+		func(t *testing.T) {
+			ctx, cancel := context.WithCancel(t.Context())
+			defer cancel()
+
+			options, errSeq := engine.SeqSchedulingOptions(ctx, &params)
+			require.NoError(t, errSeq)
+
+			var count int
+
+			for range options {
+				count++
+
+				if count == 5 {
+					cancel()
+				}
+			}
+
+			require.Equal(t,
+				5,
+				count,
+			)
+		},
+	)
+
+	t.Run(
+		"5. Invalid interval",
+		func(t *testing.T) {
+			paramsShort := params
+			paramsShort.TimeEnd = now + halfHour
+
+			_, errSeq := engine.SeqSchedulingOptions(t.Context(), &paramsShort)
+			require.Error(t, errSeq)
+		},
+	)
+
+	t.Run(
+		"6. Loco",
+		func(t *testing.T) {
+			loco := Loco{
+				ID:   1,
+				Name: t.Name(),
+
+				Resources: ResourcesPerType{
+					1: newResources(),
+				},
+			}
+
+			options, errSeq := loco.SeqAllSchedulingOptions(t.Context(), &params)
+			require.NoError(t, errSeq)
+
+			var count int
+
+			for option := range options {
+				count++
+
+				require.EqualValues(t,
+					now,
+					option.WhenCanStart,
+				)
+				require.Len(t,
+					option.Resources[1],
+					1,
+				)
+			}
+
+			require.Equal(t,
+				numberResources,
+				count,
+			)
+		},
+	)
+
+	t.Run(
+		"7. Needed type fully booked",
+		func(t *testing.T) {
+			engineTypes := newTestLocation(
+				t,
+
+				newTestResource(1, 1, 1, nil),
+				newTestResource(2, 1, 1, nil),
+				newTestResource(3, 2, 1, map[TimeInterval]RunID{
+					{TimeStart: now, TimeEnd: now + oneHour}: Maintenance,
+				}),
+			)
+
+			paramsTypes := params
+			paramsTypes.TaskRun = newTestRun(1, halfHour, 1, 2)
+
+			options, errSeq := engineTypes.SeqSchedulingOptions(t.Context(), &paramsTypes)
+			require.NoError(t, errSeq)
+
+			var count int
+
+			for option := range options {
+				count++
+
+				require.Len(t,
+					newResourcesPerType(option.SelectedResources),
+					2,
+				)
+			}
+
+			require.Zero(t, count)
+		},
+	)
+
+	t.Run(
+		"8. Context done while no combination is found",
+		func(t *testing.T) {
+			// 2^40 subsets searched, none serves the quantity
+			resources := make([]*ResourceScheduled, 40)
+
+			for ix := range resources {
+				resources[ix] = newTestResource(ix+1, 1, 1, nil)
+			}
+
+			ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+			defer cancel()
+
+			var count int
+
+			for range seqAllValidCombinations(
+				ctx,
+				ResourcesPerType{1: resources},
+				map[uint8]uint16{1: 41},
+			) {
+				count++
+			}
+
+			require.Zero(t, count)
+			require.Error(t, ctx.Err())
+		},
+	)
+}
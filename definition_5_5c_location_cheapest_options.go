@@ -0,0 +1,86 @@
+package scheduler
+
+import (
+	"cmp"
+	"slices"
+)
+
+type ParamsCheapestOptions struct {
+	ParamsCanRun // AllPossibilities and PossibilitiesUpTo not used
+
+	K int // cheapest combinations per start, one if zero
+}
+
+// GetCheapestOptions returns up to K cheapest options per start, by start then cost, without booking.
+// Combinations serve the needed quantities exactly and are searched cheapest first,
+// so large resource types are not enumerated. Soft preferences are not counted.
+func (loc *Engine) GetCheapestOptions(params *ParamsCheapestOptions) ([]*SchedulingOption, error) {
+	loc.mu.Lock()
+	defer loc.mu.Unlock()
+
+	loc.expireHolds()
+
+	paramsPossibilities := params.ParamsCanRun
+	paramsPossibilities.AllPossibilities = true
+
+	possibilitiesResp, errGetPossibilities := loc.GetPossibilities(&paramsPossibilities)
+	if errGetPossibilities != nil {
+		return nil,
+			errGetPossibilities
+	}
+
+	options := make([]*SchedulingOption, 0)
+
+	for timeSlot, resources := range possibilitiesResp.Possibilities {
+		whenCanStart := possibilitiesResp.toTaskTime(timeSlot.TimeStart - possibilitiesResp.offsetDifference)
+		interval := loc.getBookingInterval(possibilitiesResp, timeSlot.TimeStart, params.TaskRun.EstimatedDuration)
+
+		for _, combination := range getCheapestCombinations(
+			&paramsGetCheapestCombinations{
+				AvailableResourcesByType: newResourcesPerType(resources),
+				ResourcesNeededPerType:   possibilitiesResp.resourcesNeededPerType,
+				CostOf:                   loc.getPricing(params.TaskRun, &interval).getResourceCost,
+				Preferences:              possibilitiesResp.preferences,
+
+				K: int(max(int64(params.K), 1)),
+			},
+		) {
+			options = append(
+				options,
+				loc.newSchedulingOption(params.TaskRun, whenCanStart, interval, combination.resources),
+			)
+		}
+	}
+
+	slices.SortStableFunc(
+		options,
+		func(a, b *SchedulingOption) int {
+			if a.WhenCanStart != b.WhenCanStart {
+				return cmp.Compare(a.WhenCanStart, b.WhenCanStart)
+			}
+
+			return compareMoney(a.Cost, b.Cost)
+		},
+	)
+
+	return options,
+		nil
+}
+
+// GetCheapestSchedulingOptions is the engine GetCheapestOptions with options per type.
+func (loc *Loco) GetCheapestSchedulingOptions(params *ParamsCheapestOptions) (OptionsSchedule, error) {
+	engine, errEngine := loc.GetEngine()
+	if errEngine != nil {
+		return nil,
+			errEngine
+	}
+
+	options, errGet := engine.GetCheapestOptions(params)
+	if errGet != nil {
+		return nil,
+			errGet
+	}
+
+	return toOptionsSchedule(options),
+		nil
+}
@@ -0,0 +1,121 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheapestOptions(t *testing.T) {
+	const numberResources = 200
+
+	newResources := func() []*ResourceScheduled {
+		result := make([]*ResourceScheduled, 0, numberResources)
+
+		for id := 1; id <= numberResources; id++ {
+			result = append(result, newTestResource(id, 1, int64(numberResources-id+1), nil))
+		}
+
+		return result
+	}
+
+	params := ParamsCheapestOptions{
+		ParamsCanRun: ParamsCanRun{
+			TimeInterval: TimeInterval{
+				TimeStart: now,
+				TimeEnd:   now + oneHour,
+			},
+
+			TaskRun: &Run{
+				ID:                1,
+				EstimatedDuration: oneHour,
+
+				Dependencies: []RunDependency{
+					{
+						ResourceType:     1,
+						ResourceQuantity: 5,
+					},
+				},
+
+				RunLoad: RunLoad{
+					Load:     1,
+					LoadUnit: 1,
+				},
+			},
+		},
+
+		K: 3,
+	}
+
+	t.Run(
+		"1. K cheapest of a large type",
+		func(t *testing.T) {
+			engine := newTestLocation(t, newResources()...)
+
+			started := time.Now()
+
+			options, errGet := engine.GetCheapestOptions(&params)
+			require.NoError(t, errGet)
+			require.Less(t,
+				time.Since(started),
+				time.Second,
+				"2.5 billion combinations not enumerated",
+			)
+
+			require.Len(t,
+				options,
+				3,
+			)
+			require.EqualValues(t,
+				[]int64{15, 16, 17},
+				[]int64{options[0].Cost.Amount, options[1].Cost.Amount, options[2].Cost.Amount},
+			)
+			require.ElementsMatch(t,
+				[]int{196, 197, 198, 199, 200},
+				[]int{
+					options[0].SelectedResources[0].ID,
+					options[0].SelectedResources[1].ID,
+					options[0].SelectedResources[2].ID,
+					options[0].SelectedResources[3].ID,
+					options[0].SelectedResources[4].ID,
+				},
+			)
+		},
+	)
+
+	t.Run(
+		"2. Loco",
+		func(t *testing.T) {
+			loco := Loco{
+				ID:   1,
+				Name: t.Name(),
+
+				Resources: ResourcesPerType{
+					1: newResources(),
+				},
+			}
+
+			paramsOne := params
+			paramsOne.K = 0
+
+			options, errGet := loco.GetCheapestSchedulingOptions(&paramsOne)
+			require.NoError(t, errGet)
+			require.Len(t,
+				options,
+				1,
+			)
+			require.Len(t,
+				options[0].Resources[1],
+				5,
+			)
+
+			cost, errCost := options[0].GetCostFor(params.TaskRun)
+			require.NoError(t, errCost)
+			require.EqualValues(t,
+				15,
+				cost.Amount,
+			)
+		},
+	)
+}
@@ -0,0 +1,261 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	goerrors "github.com/TudorHulban/go-errors"
+)
+
+type ParamsScheduleSeries struct {
+	TimeInterval // first occurrence, each occurrence is searched in the same length interval
+
+	TaskRun    *Run // occurrence ix is booked with run ID TaskRun.ID + ix, none of them booked already
+	Recurrence *Recurrence
+
+	Horizon int64 // see ParamsExpand
+
+	BestEffort bool // books what it can, otherwise all or nothing
+}
+
+type OccurrenceResult struct {
+	TimeInterval
+
+	RunID        RunID
+	WhenCanStart int64 // set if it cannot start at TimeStart, _NoAvailability if it cannot run at all
+	Cost         Money
+	WasScheduled bool
+	Conflict     string // why it was not scheduled
+}
+
+func (o *OccurrenceResult) String() string {
+	return fmt.Sprintf(
+		"Occurrence{RunID: %d, [%d-%d], WasScheduled: %t, Cost: %s, Conflict: %q}",
+
+		o.RunID,
+		o.TimeStart,
+		o.TimeEnd,
+		o.WasScheduled,
+		o.Cost,
+		o.Conflict,
+	)
+}
+
+type ResponseScheduleSeries struct {
+	Occurrences []*OccurrenceResult
+
+	Cost         Money // of the scheduled occurrences
+	WasScheduled bool  // all occurrences were scheduled
+}
+
+func (r *ResponseScheduleSeries) GetConflicts() []*OccurrenceResult {
+	result := make([]*OccurrenceResult, 0)
+
+	for _, occurrence := range r.Occurrences {
+		if len(occurrence.Conflict) > 0 {
+			result = append(result, occurrence)
+		}
+	}
+
+	return result
+}
+
+func (r *ResponseScheduleSeries) String() string {
+	var sb strings.Builder
+
+	sb.WriteString("ResponseScheduleSeries{\n")
+
+	for _, occurrence := range r.Occurrences {
+		sb.WriteString("\t" + occurrence.String() + ",\n")
+	}
+
+	sb.WriteString(fmt.Sprintf("\tCost: %s, WasScheduled: %t\n", r.Cost, r.WasScheduled))
+	sb.WriteString("}")
+
+	return sb.String()
+}
+
+func (params *ParamsScheduleSeries) isValid() error {
+	if params.TaskRun == nil {
+		return goerrors.ErrValidation{
+			Caller: "ScheduleSeries",
+			Issue: goerrors.ErrNilInput{
+				InputName: "TaskRun",
+			},
+		}
+	}
+
+	if params.Recurrence == nil {
+		return goerrors.ErrValidation{
+			Caller: "ScheduleSeries",
+			Issue: goerrors.ErrNilInput{
+				InputName: "Recurrence",
+			},
+		}
+	}
+
+	if params.TaskRun.ID <= 0 {
+		return goerrors.ErrValidation{
+			Caller: "ScheduleSeries",
+			Issue: goerrors.ErrNegativeInput{
+				InputName: "TaskRun.ID",
+			},
+		}
+	}
+
+	return nil
+}
+
+// ScheduleSeries expands the recurrence and books every occurrence at its time start.
+// All or nothing unless BestEffort, the response reports conflicting occurrences.
+// An all or nothing series failing to book, as a resource was booked directly meanwhile,
+// undoes the occurrences booked and returns the response with the errors of the undo if any.
+func (loc *Engine) ScheduleSeries(params *ParamsScheduleSeries) (*ResponseScheduleSeries, error) {
+	if errValidation := params.isValid(); errValidation != nil {
+		return nil,
+			errValidation
+	}
+
+	loc.mu.Lock()
+	defer loc.mu.Unlock()
+
+	loc.expireHolds()
+
+	occurrences, errExpand := params.Recurrence.Expand(
+		&ParamsExpand{
+			First:   params.TimeInterval,
+			Horizon: params.Horizon,
+		},
+	)
+	if errExpand != nil {
+		return nil,
+			errExpand
+	}
+
+	for ix := 1; ix < len(occurrences); ix++ {
+		if occurrences[ix].GetUTCTimeStart() < occurrences[ix-1].GetUTCTimeStart()+params.TaskRun.EstimatedDuration {
+			return nil,
+				goerrors.ErrValidation{
+					Caller: "ScheduleSeries",
+					Issue: goerrors.ErrInvalidInput{
+						InputName:  "Recurrence",
+						InputValue: params.Recurrence.String(),
+						Issue:      errors.New("occurrences overlap each other"),
+					},
+				}
+		}
+	}
+
+	runIDs := make([]RunID, len(occurrences))
+
+	for ix := range occurrences {
+		runIDs[ix] = RunID(params.TaskRun.ID + int64(ix))
+	}
+
+	if errBooked := loc.checkNotBooked("ScheduleSeries", runIDs); errBooked != nil {
+		return nil,
+			errBooked
+	}
+
+	result := ResponseScheduleSeries{
+		Occurrences: make([]*OccurrenceResult, len(occurrences)),
+	}
+
+	evaluated := make([]*LocationOption, len(occurrences))
+	paramsOccurrences := make([]*ParamsCanRun, len(occurrences))
+
+	for ix, occurrence := range occurrences {
+		taskRun := *params.TaskRun
+		taskRun.ID = params.TaskRun.ID + int64(ix)
+
+		paramsOccurrences[ix] = &ParamsCanRun{
+			TimeInterval: occurrence,
+			TaskRun:      &taskRun,
+		}
+
+		option, errEvaluate := loc.evaluate(paramsOccurrences[ix])
+		if errEvaluate != nil {
+			return nil,
+				errEvaluate
+		}
+
+		evaluated[ix] = option
+
+		result.Occurrences[ix] = &OccurrenceResult{
+			TimeInterval: occurrence,
+			RunID:        RunID(taskRun.ID),
+			Cost:         option.Cost,
+		}
+
+		if !option.CanRunAtStart {
+			result.Occurrences[ix].WhenCanStart = option.WhenCanStart
+			result.Occurrences[ix].Conflict = ternary(
+				option.WhenCanStart == _NoAvailability,
+
+				"no resources available in interval",
+				fmt.Sprintf("resources busy, earliest start %d", option.WhenCanStart),
+			)
+
+			continue
+		}
+
+		// best effort books right away so the next occurrences see it.
+		if params.BestEffort {
+			if errBook := loc.bookAtStart(option.possibilities, paramsOccurrences[ix], option.SelectedResources); errBook != nil {
+				result.Occurrences[ix].Conflict = errBook.Error()
+
+				continue
+			}
+
+			result.Occurrences[ix].WasScheduled = true
+			result.Cost = result.Cost.add(option.Cost)
+		}
+	}
+
+	if params.BestEffort {
+		result.WasScheduled = len(result.GetConflicts()) == 0
+
+		return &result,
+			nil
+	}
+
+	// all or nothing, occurrences without conflict stay not scheduled.
+	if len(result.GetConflicts()) > 0 {
+		return &result,
+			nil
+	}
+
+	for ix, option := range evaluated {
+		if errBook := loc.bookAtStart(option.possibilities, paramsOccurrences[ix], option.SelectedResources); errBook != nil {
+			// booked directly on a resource meanwhile, undo the occurrences already booked.
+			// Occurrences failing to undo stay reported as scheduled, with the undo errors returned.
+			result.Occurrences[ix].Conflict = errBook.Error()
+			result.Cost = Money{}
+
+			errsUndo := make([]error, 0)
+
+			for _, occurrence := range result.Occurrences[:ix] {
+				if errCancel := loc.cancelRun(occurrence.RunID); errCancel != nil {
+					errsUndo = append(errsUndo, errCancel)
+					result.Cost = result.Cost.add(occurrence.Cost)
+
+					continue
+				}
+
+				occurrence.WasScheduled = false
+			}
+
+			return &result,
+				errors.Join(errsUndo...)
+		}
+
+		result.Occurrences[ix].WasScheduled = true
+		result.Cost = result.Cost.add(option.Cost)
+	}
+
+	result.WasScheduled = true
+
+	return &result,
+		nil
+}
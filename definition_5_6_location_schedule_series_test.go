@@ -0,0 +1,236 @@
+package scheduler
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// strategyOnSelected is Earliest calling onSelected after each booking.
+type strategyOnSelected struct {
+	Earliest
+
+	onSelected func(resources []*ResourceScheduled)
+}
+
+func (s strategyOnSelected) Selected(resources []*ResourceScheduled) {
+	s.onSelected(resources)
+}
+
+func TestScheduleSeries(t *testing.T) {
+	newLocation := func(t *testing.T) *Location {
+		return newTestLocation(
+			t,
+
+			newTestResource(1, 1, 2, map[TimeInterval]RunID{
+				{TimeStart: now + 2*oneDay, TimeEnd: now + 2*oneDay + oneHour}: Maintenance,
+			}),
+		)
+	}
+
+	recurrence, errParse := ParseRecurrence("FREQ=DAILY;COUNT=4")
+	require.NoError(t, errParse)
+
+	paramsSeries := func(bestEffort bool) *ParamsScheduleSeries {
+		return &ParamsScheduleSeries{
+			TimeInterval: TimeInterval{
+				TimeStart: now,
+				TimeEnd:   now + oneHour,
+			},
+
+			TaskRun: newTestRun(100, oneHour, 1),
+
+			Recurrence: recurrence,
+			BestEffort: bestEffort,
+		}
+	}
+
+	t.Run(
+		"1. All or nothing, one conflict books nothing",
+		func(t *testing.T) {
+			location := newLocation(t)
+
+			response, errSchedule := location.ScheduleSeries(paramsSeries(false))
+			require.NoError(t, errSchedule)
+			require.False(t, response.WasScheduled)
+			require.Len(t,
+				response.Occurrences,
+				4,
+			)
+
+			conflicts := response.GetConflicts()
+			require.Len(t,
+				conflicts,
+				1,
+			)
+			require.EqualValues(t,
+				102,
+				conflicts[0].RunID,
+			)
+			require.Equal(t,
+				1,
+				location.Resources[0].schedule.Len(),
+				"only maintenance",
+			)
+
+			fmt.Println(
+				response,
+			)
+		},
+	)
+
+	t.Run(
+		"2. Best effort books what it can",
+		func(t *testing.T) {
+			location := newLocation(t)
+
+			response, errSchedule := location.ScheduleSeries(paramsSeries(true))
+			require.NoError(t, errSchedule)
+			require.False(t, response.WasScheduled)
+			require.Len(t,
+				response.GetConflicts(),
+				1,
+			)
+			require.EqualValues(t,
+				6,
+				response.Cost.Amount,
+			)
+			require.Equal(t,
+				4,
+				location.Resources[0].schedule.Len(),
+				"maintenance and three occurrences",
+			)
+		},
+	)
+
+	t.Run(
+		"3. All or nothing, no conflict",
+		func(t *testing.T) {
+			location := newLocation(t)
+
+			params := paramsSeries(false)
+			params.Recurrence = &Recurrence{
+				Frequency: FrequencyDaily,
+				Count:     2,
+			}
+
+			response, errSchedule := location.ScheduleSeries(params)
+			require.NoError(t, errSchedule)
+			require.True(t, response.WasScheduled)
+			require.Empty(t, response.GetConflicts())
+			require.Equal(t,
+				3,
+				location.Resources[0].schedule.Len(),
+			)
+
+			run, errGet := location.Resources[0].GetRun(now+oneDay, 0)
+			require.NoError(t, errGet)
+			require.EqualValues(t,
+				101,
+				run.ID,
+			)
+		},
+	)
+
+	t.Run(
+		"4. Occurrence run ID already booked",
+		func(t *testing.T) {
+			location := newLocation(t)
+
+			_, errAdd := location.Resources[0].AddRun(
+				t.Context(),
+				&ParamsRun{
+					TimeInterval: TimeInterval{
+						TimeStart: now + 5*oneDay,
+						TimeEnd:   now + 5*oneDay + oneHour,
+					},
+					ID: 102,
+				},
+			)
+			require.NoError(t, errAdd)
+
+			_, errSchedule := location.ScheduleSeries(paramsSeries(true))
+			require.Error(t, errSchedule)
+			require.Equal(t,
+				2,
+				location.Resources[0].schedule.Len(),
+				"maintenance and run 102",
+			)
+		},
+	)
+
+	t.Run(
+		"5. All or nothing, booked directly meanwhile, rolled back",
+		func(t *testing.T) {
+			resource := newTestResource(1, 1, 2, nil)
+			location := newTestLocation(t, resource)
+
+			// the second occurrence is booked directly once the first one is booked
+			location.Strategy = strategyOnSelected{
+				onSelected: func(_ []*ResourceScheduled) {
+					_, errAdd := resource.AddRun(
+						t.Context(),
+						&ParamsRun{
+							TimeInterval: TimeInterval{
+								TimeStart: now + oneDay,
+								TimeEnd:   now + oneDay + oneHour,
+							},
+							ID: 999,
+						},
+					)
+					require.NoError(t, errAdd)
+				},
+			}
+
+			response, errSchedule := location.ScheduleSeries(paramsSeries(false))
+			require.NoError(t, errSchedule)
+			require.False(t, response.WasScheduled)
+			require.False(t, response.Occurrences[0].WasScheduled)
+			require.NotEmpty(t, response.Occurrences[1].Conflict)
+			require.True(t, response.Cost.IsZero())
+			require.Equal(t,
+				1,
+				resource.schedule.Len(),
+				"only the direct booking",
+			)
+		},
+	)
+
+	t.Run(
+		"6. Undo failing is returned",
+		func(t *testing.T) {
+			resource := newTestResource(1, 1, 2, nil)
+			location := newTestLocation(t, resource)
+
+			// the first occurrence is also removed directly, its undo fails
+			location.Strategy = strategyOnSelected{
+				onSelected: func(_ []*ResourceScheduled) {
+					_, errAdd := resource.AddRun(
+						t.Context(),
+						&ParamsRun{
+							TimeInterval: TimeInterval{
+								TimeStart: now + oneDay,
+								TimeEnd:   now + oneDay + oneHour,
+							},
+							ID: 999,
+						},
+					)
+					require.NoError(t, errAdd)
+					require.NoError(t,
+						resource.removeRun(100),
+					)
+				},
+			}
+
+			response, errSchedule := location.ScheduleSeries(paramsSeries(false))
+			require.Error(t, errSchedule)
+			require.NotNil(t, response)
+			require.False(t, response.WasScheduled)
+			require.True(t,
+				response.Occurrences[0].WasScheduled,
+				"not undone",
+			)
+		},
+	)
+}
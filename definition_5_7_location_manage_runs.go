@@ -0,0 +1,230 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+
+	goerrors "github.com/TudorHulban/go-errors"
+)
+
+type runBooking struct {
+	resource *ResourceScheduled
+	interval TimeInterval
+}
+
+// getRunBookings should be called holding the locks of the location resources.
+func (loc *Engine) getRunBookings(caller string, runID RunID) ([]runBooking, error) {
+	if runID <= Maintenance {
+		return nil,
+			goerrors.ErrInvalidInput{
+				Caller:     caller,
+				InputName:  "runID",
+				InputValue: runID,
+				Issue:      errors.New("maintenance and holds are reserved"),
+			}
+	}
+
+	result := make([]runBooking, 0)
+
+	for _, resource := range loc.Resources {
+		for _, interval := range resource.schedule.getRunIntervals(runID) {
+			result = append(
+				result,
+				runBooking{
+					resource: resource,
+					interval: interval,
+				},
+			)
+		}
+	}
+
+	if len(result) == 0 {
+		return nil,
+			goerrors.ErrEntryNotFound{
+				Key: runID,
+			}
+	}
+
+	return result,
+		nil
+}
+
+// checkNotBooked returns a validation error for the first run ID already booked at the location.
+// Should be called under the location lock.
+func (loc *Engine) checkNotBooked(caller string, runIDs []RunID) error {
+	for _, runID := range runIDs {
+		for _, resource := range loc.Resources {
+			resource.mu.RLock()
+			isBooked := resource.schedule.hasRun(runID)
+			resource.mu.RUnlock()
+
+			if isBooked {
+				return goerrors.ErrValidation{
+					Caller: caller,
+					Issue: goerrors.ErrInvalidInput{
+						InputName:  "Run.ID",
+						InputValue: runID,
+						Issue:      fmt.Errorf("run ID %d already booked", runID),
+					},
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// CancelRun removes the run from every resource holding it.
+func (loc *Engine) CancelRun(runID RunID) error {
+	loc.mu.Lock()
+	defer loc.mu.Unlock()
+
+	loc.expireHolds()
+
+	return loc.cancelRun(runID)
+}
+
+// cancelRun should be called under the location lock.
+func (loc *Engine) cancelRun(runID RunID) error {
+	unlock := lockResources(loc.Resources)
+	defer unlock()
+
+	bookings, errGet := loc.getRunBookings("CancelRun", runID)
+	if errGet != nil {
+		return errGet
+	}
+
+	for _, booking := range bookings {
+		booking.resource.schedule.removeRuns(runID)
+	}
+
+	return nil
+}
+
+// MoveRun moves the run to start at newStart, location time, keeping its duration.
+// All bookings of the run are shifted by the same elapsed seconds.
+func (loc *Engine) MoveRun(runID RunID, newStart int64) error {
+	loc.mu.Lock()
+	defer loc.mu.Unlock()
+
+	loc.expireHolds()
+
+	unlock := lockResources(loc.Resources)
+	defer unlock()
+
+	bookings, errGet := loc.getRunBookings("MoveRun", runID)
+	if errGet != nil {
+		return errGet
+	}
+
+	earliestStart := bookings[0].interval.GetUTCTimeStart()
+
+	for _, booking := range bookings[1:] {
+		earliestStart = min(earliestStart, booking.interval.GetUTCTimeStart())
+	}
+
+	shift := loc.getTimeReference().toUTCTimestamp(newStart) - earliestStart
+
+	return loc.rebook(
+		"MoveRun",
+		runID,
+		bookings,
+
+		func(interval *TimeInterval) TimeInterval {
+			return interval.fromUTC(
+				interval.GetUTCTimeStart()+shift,
+				interval.GetUTCTimeEnd()+shift,
+			)
+		},
+	)
+}
+
+// ResizeRun changes the duration of the run, in seconds, keeping its start.
+func (loc *Engine) ResizeRun(runID RunID, newDuration int64) error {
+	if newDuration <= 0 {
+		return goerrors.ErrInvalidInput{
+			Caller:     "ResizeRun",
+			InputName:  "newDuration",
+			InputValue: newDuration,
+			Issue: goerrors.ErrNegativeInput{
+				InputName: "newDuration",
+			},
+		}
+	}
+
+	loc.mu.Lock()
+	defer loc.mu.Unlock()
+
+	loc.expireHolds()
+
+	unlock := lockResources(loc.Resources)
+	defer unlock()
+
+	bookings, errGet := loc.getRunBookings("ResizeRun", runID)
+	if errGet != nil {
+		return errGet
+	}
+
+	return loc.rebook(
+		"ResizeRun",
+		runID,
+		bookings,
+
+		func(interval *TimeInterval) TimeInterval {
+			return interval.fromUTC(
+				interval.GetUTCTimeStart(),
+				interval.GetUTCTimeStart()+newDuration,
+			)
+		},
+	)
+}
+
+// rebook replaces the run bookings with the transformed intervals, all or nothing.
+// Intervals outside the location calendar are rejected, on conflict the original bookings are restored.
+// Should be called under the location lock, holding the locks of the location resources.
+func (loc *Engine) rebook(caller string, runID RunID, bookings []runBooking, transform func(*TimeInterval) TimeInterval) error {
+	for _, booking := range bookings {
+		rebooked := transform(&booking.interval)
+
+		if !loc.Calendar.IsOpen(loc.getTimeReference(), &rebooked) {
+			return goerrors.ErrInvalidInput{
+				Caller:     caller,
+				InputName:  "runID",
+				InputValue: runID,
+				Issue: fmt.Errorf(
+					"location closed in [%d-%d]",
+					rebooked.TimeStart,
+					rebooked.TimeEnd,
+				),
+			}
+		}
+	}
+
+	for _, booking := range bookings {
+		booking.resource.schedule.removeRuns(runID)
+	}
+
+	for ix, booking := range bookings {
+		rebooked := transform(&booking.interval)
+
+		if _, available := booking.resource.getAvailability(&rebooked); !available {
+			for _, restore := range bookings[:ix] {
+				restore.resource.schedule.removeRuns(runID)
+			}
+
+			for _, restore := range bookings {
+				restore.resource.schedule.insert(restore.interval, runID)
+			}
+
+			return ErrRunConflict{
+				Interval:   rebooked,
+				RunID:      runID,
+				ResourceID: booking.resource.ID,
+			}
+		}
+
+		booking.resource.schedule.insert(rebooked, runID)
+	}
+
+	return nil
+}
@@ -0,0 +1,187 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	goerrors "github.com/TudorHulban/go-errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManageRuns(t *testing.T) {
+	location := newTestLocation(
+		t,
+
+		newTestResource(1, 1, 2, map[TimeInterval]RunID{
+			{TimeStart: now + 2*oneHour, TimeEnd: now + 3*oneHour}: Maintenance,
+		}),
+		newTestResource(2, 2, 1, nil),
+	)
+
+	newRun := func(id int64) *Run {
+		return newTestRun(id, oneHour, 1, 2)
+	}
+
+	for id, start := range map[int64]int64{1: now, 2: now + 6*oneHour} {
+		response, errSchedule := location.CanSchedule(
+			&ParamsCanRun{
+				TimeInterval: TimeInterval{
+					TimeStart: start,
+					TimeEnd:   start + oneHour,
+				},
+
+				TaskRun: newRun(id),
+			},
+		)
+		require.NoError(t, errSchedule)
+		require.True(t, response.WasScheduled)
+	}
+
+	requireRunAt := func(t *testing.T, runID RunID, at int64) {
+		for _, resource := range location.Resources {
+			run, errGet := resource.GetRun(at, 0)
+			require.NoError(t, errGet, resource.Name)
+			require.Equal(t,
+				runID,
+				run.ID,
+				resource.Name,
+			)
+		}
+	}
+
+	t.Run(
+		"1. Move conflicting with maintenance leaves the run in place",
+		func(t *testing.T) {
+			errMove := location.MoveRun(1, now+oneHour+halfHour)
+			require.ErrorAs(t,
+				errMove,
+				&ErrRunConflict{},
+			)
+
+			requireRunAt(t, 1, now+halfHour)
+		},
+	)
+
+	t.Run(
+		"2. Move",
+		func(t *testing.T) {
+			require.NoError(t,
+				location.MoveRun(1, now+4*oneHour),
+			)
+
+			requireRunAt(t, 1, now+4*oneHour+halfHour)
+
+			_, errGet := location.Resources[1].GetRun(now+halfHour, 0)
+			require.Error(t, errGet)
+		},
+	)
+
+	t.Run(
+		"3. Resize",
+		func(t *testing.T) {
+			require.NoError(t,
+				location.ResizeRun(1, 2*oneHour),
+			)
+
+			requireRunAt(t, 1, now+5*oneHour+halfHour)
+
+			errResize := location.ResizeRun(1, 3*oneHour)
+			require.ErrorAs(t,
+				errResize,
+				&ErrRunConflict{},
+				"overlaps run 2",
+			)
+
+			requireRunAt(t, 1, now+5*oneHour+halfHour)
+			requireRunAt(t, 2, now+6*oneHour+halfHour)
+		},
+	)
+
+	t.Run(
+		"4. Cancel",
+		func(t *testing.T) {
+			require.NoError(t,
+				location.CancelRun(1),
+			)
+
+			for _, resource := range location.Resources {
+				require.False(t,
+					resource.schedule.hasRun(1),
+				)
+			}
+
+			require.ErrorAs(t,
+				location.CancelRun(1),
+				&goerrors.ErrEntryNotFound{},
+			)
+
+			requireRunAt(t, 2, now+6*oneHour+halfHour)
+		},
+	)
+
+	t.Run(
+		"5. Maintenance is reserved",
+		func(t *testing.T) {
+			require.Error(t,
+				location.CancelRun(Maintenance),
+			)
+			require.Equal(t,
+				2,
+				location.Resources[0].schedule.Len(),
+			)
+		},
+	)
+
+	t.Run(
+		"6. Move and resize into closed time leave the run in place",
+		func(t *testing.T) {
+			calendar := NewCalendar()
+
+			for day := time.Sunday; day <= time.Saturday; day++ {
+				require.NoError(t,
+					calendar.SetWeekly(day, DailyWindow{From: 0, To: 12 * oneHour}),
+				)
+			}
+
+			open := newTestLocation(t, newTestResource(1, 1, 1, nil))
+			open.Calendar = calendar
+
+			dayStart := now - now%oneDay
+
+			response, errSchedule := open.CanSchedule(
+				&ParamsCanRun{
+					TimeInterval: TimeInterval{
+						TimeStart: dayStart + 10*oneHour,
+						TimeEnd:   dayStart + 11*oneHour,
+					},
+
+					TaskRun: newTestRun(1, oneHour, 1),
+				},
+			)
+			require.NoError(t, errSchedule)
+			require.True(t, response.WasScheduled)
+
+			require.Error(t,
+				open.MoveRun(1, dayStart+13*oneHour),
+			)
+			require.Error(t,
+				open.ResizeRun(1, 3*oneHour),
+			)
+
+			run, errGet := open.Resources[0].GetRun(dayStart+10*oneHour+halfHour, 0)
+			require.NoError(t, errGet)
+			require.EqualValues(t,
+				1,
+				run.ID,
+			)
+			require.Equal(t,
+				1,
+				open.Resources[0].schedule.Len(),
+			)
+
+			require.NoError(t,
+				open.MoveRun(1, dayStart+11*oneHour),
+			)
+		},
+	)
+}
@@ -0,0 +1,241 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	goerrors "github.com/TudorHulban/go-errors"
+)
+
+// HoldToken identifies a tentative hold on the resources of a scheduling option.
+// Tokens are unique across locations, as resources can be shared.
+type HoldToken uint64
+
+var holdTokens atomic.Uint64
+
+// runID returns the ID the hold is booked under on the resources.
+// Holds use negative run IDs, which runs cannot take.
+func (t HoldToken) runID() RunID {
+	return -RunID(t)
+}
+
+type hold struct {
+	resources []*ResourceScheduled
+	interval  TimeInterval
+
+	runID     RunID
+	expiresAt time.Time
+}
+
+// isExpired tells by the location clock if a hold expiring at expiresAt expired.
+// Called by queries on the held resources, it does not take the location lock.
+func (loc *Engine) isExpired(expiresAt time.Time) func() bool {
+	return func() bool {
+		return !loc.now().Before(expiresAt)
+	}
+}
+
+func (loc *Engine) now() time.Time {
+	if loc.Now == nil {
+		return time.Now()
+	}
+
+	return loc.Now()
+}
+
+// expireHolds releases the holds past their expiry. Expired holds do not block bookings
+// before, and a booking overlapping one removes its entry from the resource.
+// This drops the other entries and the holds. Should be called under the location lock.
+func (loc *Engine) expireHolds() {
+	now := loc.now()
+
+	for _, token := range sortedKeys(loc.holds) {
+		if now.Before(loc.holds[token].expiresAt) {
+			continue
+		}
+
+		loc.releaseHold(token)
+	}
+}
+
+// releaseHold should be called under the location lock.
+func (loc *Engine) releaseHold(token HoldToken) {
+	held := loc.holds[token]
+
+	unlock := lockResources(held.resources)
+	defer unlock()
+
+	for _, resource := range held.resources {
+		resource.schedule.removeRuns(token.runID())
+	}
+
+	delete(loc.holds, token)
+}
+
+// checkNotHeld returns a validation error if the run is held at the location.
+// Should be called under the location lock.
+func (loc *Engine) checkNotHeld(caller string, runID RunID) error {
+	for _, held := range loc.holds {
+		if held.runID == runID {
+			return goerrors.ErrValidation{
+				Caller: caller,
+				Issue: goerrors.ErrInvalidInput{
+					InputName:  "Run.ID",
+					InputValue: runID,
+					Issue:      fmt.Errorf("run ID %d already held", runID),
+				},
+			}
+		}
+	}
+
+	return nil
+}
+
+// Hold reserves the selected resources of the option, obtained with GetSchedulingOptions,
+// for ttl. The reserved interval is busy for any other booking, of any location sharing
+// the resources, until the hold is confirmed, released or expires.
+// A run is held once and not if already booked at the location.
+func (loc *Engine) Hold(option *SchedulingOption, ttl time.Duration) (HoldToken, error) {
+	if option == nil {
+		return 0,
+			goerrors.ErrValidation{
+				Caller: "Hold",
+				Issue: goerrors.ErrNilInput{
+					InputName: "option",
+				},
+			}
+	}
+
+	if ttl <= 0 {
+		return 0,
+			goerrors.ErrInvalidInput{
+				Caller:     "Hold",
+				InputName:  "ttl",
+				InputValue: ttl,
+				Issue: goerrors.ErrNegativeInput{
+					InputName: "ttl",
+				},
+			}
+	}
+
+	if option.runID <= Maintenance || option.interval.TimeEnd <= option.interval.TimeStart {
+		return 0,
+			goerrors.ErrInvalidInput{
+				Caller:     "Hold",
+				InputName:  "option",
+				InputValue: option.WhenCanStart,
+				Issue:      errors.New("option not obtained from GetSchedulingOptions"),
+			}
+	}
+
+	for _, resource := range option.SelectedResources {
+		if !slices.Contains(loc.Resources, resource) {
+			return 0,
+				goerrors.ErrInvalidInput{
+					Caller:     "Hold",
+					InputName:  "option",
+					InputValue: resource.ID,
+					Issue:      errors.New("resource not in location"),
+				}
+		}
+	}
+
+	loc.mu.Lock()
+	defer loc.mu.Unlock()
+
+	loc.expireHolds()
+
+	if errBooked := loc.checkNotBooked("Hold", []RunID{option.runID}); errBooked != nil {
+		return 0,
+			errBooked
+	}
+
+	if errHeld := loc.checkNotHeld("Hold", option.runID); errHeld != nil {
+		return 0,
+			errHeld
+	}
+
+	token := HoldToken(holdTokens.Add(1))
+	expiresAt := loc.now().Add(ttl)
+
+	if errBook := bookResourcesHeld(
+		option.SelectedResources,
+		&option.interval,
+		token.runID(),
+		loc.isExpired(expiresAt),
+	); errBook != nil {
+		return 0,
+			errBook
+	}
+
+	if loc.holds == nil {
+		loc.holds = make(map[HoldToken]*hold)
+	}
+
+	loc.holds[token] = &hold{
+		resources: slices.Clone(option.SelectedResources),
+		interval:  option.interval,
+		runID:     option.runID,
+		expiresAt: expiresAt,
+	}
+
+	return token,
+		nil
+}
+
+// Confirm books the held interval under the run ID of the option.
+// Returns ErrEntryNotFound if the hold expired or was released, and a validation error,
+// keeping the hold, if the run was booked meanwhile.
+func (loc *Engine) Confirm(token HoldToken) error {
+	loc.mu.Lock()
+	defer loc.mu.Unlock()
+
+	loc.expireHolds()
+
+	held, exists := loc.holds[token]
+	if !exists {
+		return goerrors.ErrEntryNotFound{
+			Key: token,
+		}
+	}
+
+	if errBooked := loc.checkNotBooked("Confirm", []RunID{held.runID}); errBooked != nil {
+		return errBooked
+	}
+
+	unlock := lockResources(held.resources)
+	defer unlock()
+
+	for _, resource := range held.resources {
+		resource.schedule.removeRuns(token.runID())
+		resource.schedule.insert(held.interval, held.runID)
+	}
+
+	delete(loc.holds, token)
+
+	notifySelected(loc.getStrategy(), held.resources)
+
+	return nil
+}
+
+// Release drops the hold, freeing the interval.
+// Returns ErrEntryNotFound if the hold expired or was already released.
+func (loc *Engine) Release(token HoldToken) error {
+	loc.mu.Lock()
+	defer loc.mu.Unlock()
+
+	loc.expireHolds()
+
+	if _, exists := loc.holds[token]; !exists {
+		return goerrors.ErrEntryNotFound{
+			Key: token,
+		}
+	}
+
+	loc.releaseHold(token)
+
+	return nil
+}
@@ -0,0 +1,309 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	goerrors "github.com/TudorHulban/go-errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocationHolds(t *testing.T) {
+	clock := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	newLocation := func(t *testing.T) *Location {
+		location := newTestLocation(t, newTestResource(1, 1, 1, nil))
+		location.Now = func() time.Time {
+			return clock
+		}
+
+		return location
+	}
+
+	newParams := func(runID int64) *ParamsCanRun {
+		return &ParamsCanRun{
+			TimeInterval: TimeInterval{
+				TimeStart: now,
+				TimeEnd:   now + oneHour,
+			},
+			TaskRun: newTestRun(runID, oneHour, 1),
+		}
+	}
+
+	hold := func(t *testing.T, location *Location, runID int64) (HoldToken, error) {
+		options, errGet := location.GetSchedulingOptions(newParams(runID))
+		require.NoError(t, errGet)
+		require.NotEmpty(t, options)
+
+		return location.Hold(options[0], time.Minute)
+	}
+
+	t.Run(
+		"1. Hold blocks, confirm books",
+		func(t *testing.T) {
+			location := newLocation(t)
+
+			optionsStale, errGet := location.GetSchedulingOptions(newParams(2))
+			require.NoError(t, errGet)
+
+			token, errHold := hold(t, location, 1)
+			require.NoError(t, errHold)
+
+			_, errStale := location.Hold(optionsStale[0], time.Minute)
+			require.True(t,
+				errors.As(errStale, &ErrRunConflict{}),
+			)
+
+			response, errSchedule := location.CanSchedule(newParams(2))
+			require.NoError(t, errSchedule)
+			require.False(t, response.WasScheduled)
+			require.EqualValues(t,
+				now+oneHour,
+				response.WhenCanStart,
+			)
+
+			require.NoError(t,
+				location.Confirm(token),
+			)
+			require.True(t,
+				location.Resources[0].schedule.hasRun(1),
+			)
+			require.False(t,
+				location.Resources[0].schedule.hasRun(token.runID()),
+			)
+			require.Equal(t,
+				1,
+				location.Resources[0].schedule.Len(),
+			)
+
+			require.ErrorAs(t,
+				location.Confirm(token),
+				&goerrors.ErrEntryNotFound{},
+			)
+		},
+	)
+
+	t.Run(
+		"2. Release frees the interval",
+		func(t *testing.T) {
+			location := newLocation(t)
+
+			token, errHold := hold(t, location, 1)
+			require.NoError(t, errHold)
+
+			require.NoError(t,
+				location.Release(token),
+			)
+			require.Zero(t,
+				location.Resources[0].schedule.Len(),
+			)
+			require.ErrorAs(t,
+				location.Release(token),
+				&goerrors.ErrEntryNotFound{},
+			)
+
+			response, errSchedule := location.CanSchedule(newParams(2))
+			require.NoError(t, errSchedule)
+			require.True(t, response.WasScheduled)
+		},
+	)
+
+	t.Run(
+		"3. Hold expires",
+		func(t *testing.T) {
+			location := newLocation(t)
+
+			token, errHold := hold(t, location, 1)
+			require.NoError(t, errHold)
+
+			clock = clock.Add(59 * time.Second)
+
+			response, errSchedule := location.CanSchedule(newParams(2))
+			require.NoError(t, errSchedule)
+			require.False(t, response.WasScheduled)
+
+			clock = clock.Add(time.Second)
+
+			response, errSchedule = location.CanSchedule(newParams(2))
+			require.NoError(t, errSchedule)
+			require.True(t, response.WasScheduled)
+
+			require.ErrorAs(t,
+				location.Confirm(token),
+				&goerrors.ErrEntryNotFound{},
+			)
+		},
+	)
+
+	t.Run(
+		"4. Invalid",
+		func(t *testing.T) {
+			location := newLocation(t)
+
+			_, errNil := location.Hold(nil, time.Minute)
+			require.Error(t, errNil)
+
+			_, errForeign := location.Hold(
+				&SchedulingOption{
+					SelectedResources: location.Resources,
+				},
+				time.Minute,
+			)
+			require.Error(t, errForeign)
+
+			options, errGet := location.GetSchedulingOptions(newParams(1))
+			require.NoError(t, errGet)
+
+			_, errTTL := location.Hold(options[0], 0)
+			require.Error(t, errTTL)
+
+			require.Error(t,
+				location.CancelRun(-1),
+				"holds are not runs",
+			)
+		},
+	)
+
+	t.Run(
+		"5. Shared resource, holds of two locations",
+		func(t *testing.T) {
+			shared := newTestResource(1, 1, 1, nil)
+
+			locationA := newTestLocation(t, shared)
+			locationA.Now = func() time.Time {
+				return clock
+			}
+
+			locationB := newTestLocation(t, shared)
+			locationB.Now = func() time.Time {
+				return clock
+			}
+
+			tokenA, errHoldA := hold(t, locationA, 1)
+			require.NoError(t, errHoldA)
+
+			paramsLater := newParams(2)
+			paramsLater.TimeInterval = TimeInterval{
+				TimeStart: now + oneHour,
+				TimeEnd:   now + 2*oneHour,
+			}
+
+			optionsB, errGetB := locationB.GetSchedulingOptions(paramsLater)
+			require.NoError(t, errGetB)
+			require.EqualValues(t,
+				now+oneHour,
+				optionsB[0].WhenCanStart,
+			)
+
+			tokenB, errHoldB := locationB.Hold(optionsB[0], 2*time.Minute)
+			require.NoError(t, errHoldB)
+			require.NotEqual(t,
+				tokenA,
+				tokenB,
+			)
+
+			require.NoError(t,
+				locationA.Release(tokenA),
+			)
+			require.True(t,
+				shared.schedule.hasRun(tokenB.runID()),
+				"hold of the other location kept",
+			)
+
+			paramsLater.TaskRun.ID = 3
+
+			response, errSchedule := locationA.CanSchedule(paramsLater)
+			require.NoError(t, errSchedule)
+			require.False(t, response.WasScheduled)
+
+			clock = clock.Add(2 * time.Minute)
+
+			_, errAdd := shared.AddRun(
+				context.Background(),
+				&ParamsRun{
+					ID:           4,
+					TimeInterval: paramsLater.TimeInterval,
+				},
+			)
+			require.NoError(t, errAdd,
+				"expired hold does not block before its location is called",
+			)
+			require.False(t,
+				shared.schedule.hasRun(tokenB.runID()),
+				"expired hold removed by the overlapping booking",
+			)
+
+			require.ErrorAs(t,
+				locationB.Confirm(tokenB),
+				&goerrors.ErrEntryNotFound{},
+			)
+			require.True(t,
+				shared.schedule.hasRun(4),
+			)
+		},
+	)
+
+	t.Run(
+		"6. Run held or booked once",
+		func(t *testing.T) {
+			location := newTestLocation(t,
+				newTestResource(1, 1, 1, nil),
+				newTestResource(2, 1, 1, nil),
+			)
+			location.Now = func() time.Time {
+				return clock
+			}
+
+			token, errHold := hold(t, location, 1)
+			require.NoError(t, errHold)
+
+			// resource 1 held, the option is on resource 2
+			options, errGet := location.GetSchedulingOptions(newParams(1))
+			require.NoError(t, errGet)
+			require.NotEmpty(t, options)
+
+			_, errHeldTwice := location.Hold(options[0], time.Minute)
+			require.ErrorAs(t,
+				errHeldTwice,
+				&goerrors.ErrValidation{},
+			)
+
+			response, errSchedule := location.CanSchedule(newParams(1))
+			require.NoError(t, errSchedule)
+			require.True(t, response.WasScheduled)
+
+			require.ErrorAs(t,
+				location.Confirm(token),
+				&goerrors.ErrValidation{},
+				"run booked meanwhile",
+			)
+
+			var bookings int
+
+			for _, resource := range location.Resources {
+				bookings = bookings + len(resource.schedule.getRunIntervals(1))
+			}
+
+			require.Equal(t,
+				1,
+				bookings,
+			)
+
+			require.NoError(t,
+				location.Release(token),
+			)
+
+			optionsBooked, errGetBooked := location.GetSchedulingOptions(newParams(1))
+			require.NoError(t, errGetBooked)
+			require.NotEmpty(t, optionsBooked)
+
+			_, errHoldBooked := location.Hold(optionsBooked[0], time.Minute)
+			require.ErrorAs(t,
+				errHoldBooked,
+				&goerrors.ErrValidation{},
+			)
+		},
+	)
+}
@@ -0,0 +1,483 @@
+package scheduler
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	goerrors "github.com/TudorHulban/go-errors"
+)
+
+// Objective is what ScheduleBatch minimizes, after the number of unscheduled runs.
+type Objective uint8
+
+const (
+	MinimizeCost              Objective = iota + 1 // sum of run costs
+	MinimizeMakespan                               // last finish from the batch window start
+	MinimizeWeightedTardiness                      // sum of weighted seconds finished after due
+)
+
+func (o Objective) String() string {
+	switch o {
+	case MinimizeCost:
+		return "Cost"
+
+	case MinimizeMakespan:
+		return "Makespan"
+
+	case MinimizeWeightedTardiness:
+		return "WeightedTardiness"
+	}
+
+	return fmt.Sprintf("Objective(%d)", o)
+}
+
+// getStrategy returns the strategy placing the runs, cheapest for cost and earliest otherwise.
+func (o Objective) getStrategy() SelectionStrategy {
+	if o == MinimizeCost {
+		return Cheapest{}
+	}
+
+	return Earliest{}
+}
+
+// BatchRun is a run of a batch, with what weighted tardiness needs.
+type BatchRun struct {
+	*Run
+
+	Due    int64   // wanted finish, expressed like the batch window, zero for none
+	Weight float32 // of tardiness seconds, zero counts as one
+}
+
+func (r *BatchRun) getWeight() float32 {
+	return ternary(r.Weight == 0, 1, r.Weight)
+}
+
+type ParamsScheduleBatch struct {
+	TimeInterval // batch window, runs start at or after TimeStart and finish by TimeEnd
+
+	Runs      []*BatchRun
+	Objective Objective
+}
+
+func (params *ParamsScheduleBatch) isValid() error {
+	if len(params.Runs) == 0 {
+		return goerrors.ErrValidation{
+			Caller: "ScheduleBatch",
+			Issue: goerrors.ErrNilInput{
+				InputName: "Runs",
+			},
+		}
+	}
+
+	if params.Objective < MinimizeCost || params.Objective > MinimizeWeightedTardiness {
+		return goerrors.ErrValidation{
+			Caller: "ScheduleBatch",
+			Issue: goerrors.ErrInvalidInput{
+				InputName:  "Objective",
+				InputValue: params.Objective,
+			},
+		}
+	}
+
+	ids := make(map[int64]bool, len(params.Runs))
+
+	for _, run := range params.Runs {
+		if run == nil || run.Run == nil {
+			return goerrors.ErrValidation{
+				Caller: "ScheduleBatch",
+				Issue: goerrors.ErrNilInput{
+					InputName: "Run",
+				},
+			}
+		}
+
+		if run.ID <= 0 {
+			return goerrors.ErrValidation{
+				Caller: "ScheduleBatch",
+				Issue: goerrors.ErrNegativeInput{
+					InputName: "Run.ID",
+				},
+			}
+		}
+
+		if ids[run.ID] {
+			return goerrors.ErrValidation{
+				Caller: "ScheduleBatch",
+				Issue: goerrors.ErrInvalidInput{
+					InputName:  "Run.ID",
+					InputValue: run.ID,
+					Issue:      fmt.Errorf("duplicate run ID %d", run.ID),
+				},
+			}
+		}
+
+		ids[run.ID] = true
+	}
+
+	return nil
+}
+
+// getInitialOrder is the list scheduling priority of the objective, ties as passed.
+// Makespan and cost take longest runs first, weighted tardiness earliest due first.
+func (params *ParamsScheduleBatch) getInitialOrder() []*BatchRun {
+	result := slices.Clone(params.Runs)
+
+	slices.SortStableFunc(
+		result,
+		func(a, b *BatchRun) int {
+			if params.Objective == MinimizeWeightedTardiness {
+				if (a.Due == 0) != (b.Due == 0) {
+					return ternary(a.Due == 0, 1, -1)
+				}
+
+				if a.Due != b.Due {
+					return cmp.Compare(a.Due, b.Due)
+				}
+
+				if a.getWeight() != b.getWeight() {
+					return cmp.Compare(b.getWeight(), a.getWeight())
+				}
+
+				return cmp.Compare(a.EstimatedDuration, b.EstimatedDuration)
+			}
+
+			return cmp.Compare(b.EstimatedDuration, a.EstimatedDuration)
+		},
+	)
+
+	return result
+}
+
+// BatchRunResult times are expressed like the batch window.
+type BatchRunResult struct {
+	TimeInterval // as booked
+
+	RunID     RunID
+	Cost      Money
+	Tardiness int64 // seconds finished after due
+}
+
+func (r *BatchRunResult) String() string {
+	return fmt.Sprintf(
+		"BatchRun{RunID: %d, [%d-%d], Cost: %s, Tardiness: %d}",
+
+		r.RunID,
+		r.TimeStart,
+		r.TimeEnd,
+		r.Cost,
+		r.Tardiness,
+	)
+}
+
+type UnscheduledRun struct {
+	RunID  RunID
+	Reason string
+}
+
+type ResponseScheduleBatch struct {
+	Scheduled   []*BatchRunResult // by start
+	Unscheduled []*UnscheduledRun // in placement order
+
+	TimeInterval // first start and last finish of the scheduled runs
+
+	Cost              Money
+	Makespan          int64 // seconds from the batch window start to the last finish
+	WeightedTardiness float32
+
+	Evaluations int // run orders evaluated
+}
+
+func (r *ResponseScheduleBatch) String() string {
+	var sb strings.Builder
+
+	sb.WriteString("ResponseScheduleBatch{\n")
+
+	for _, run := range r.Scheduled {
+		sb.WriteString("\t" + run.String() + ",\n")
+	}
+
+	for _, run := range r.Unscheduled {
+		sb.WriteString(fmt.Sprintf("\tUnscheduled{RunID: %d, Reason: %q},\n", run.RunID, run.Reason))
+	}
+
+	sb.WriteString(
+		fmt.Sprintf(
+			"\t[%d-%d], Cost: %s, Makespan: %d, WeightedTardiness: %.2f, Evaluations: %d\n}",
+
+			r.TimeStart,
+			r.TimeEnd,
+			r.Cost,
+			r.Makespan,
+			r.WeightedTardiness,
+			r.Evaluations,
+		),
+	)
+
+	return sb.String()
+}
+
+type batchScore struct {
+	unscheduled int
+	value       float64
+}
+
+func (r *ResponseScheduleBatch) getScore(objective Objective) batchScore {
+	result := batchScore{
+		unscheduled: len(r.Unscheduled),
+	}
+
+	switch objective {
+	case MinimizeCost:
+		result.value = float64(r.Cost.Amount)
+
+	case MinimizeMakespan:
+		result.value = float64(r.Makespan)
+
+	case MinimizeWeightedTardiness:
+		result.value = float64(r.WeightedTardiness)
+	}
+
+	return result
+}
+
+func (s batchScore) isBetter(other batchScore) bool {
+	if s.unscheduled != other.unscheduled {
+		return s.unscheduled < other.unscheduled
+	}
+
+	return s.value < other.value
+}
+
+// ScheduleBatch books the runs in the window, minimizing the objective.
+// The run order is built by list scheduling, by the objective priority, and improved by local search
+// swapping neighbour runs, each run placed in that order at its earliest start, or its cheapest for MinimizeCost.
+// The search works on a snapshot of the schedules and stops at a local optimum or when ctx is done,
+// the best order found is then booked. Runs that cannot be booked are reported with the reason.
+func (loc *Engine) ScheduleBatch(ctx context.Context, params *ParamsScheduleBatch) (*ResponseScheduleBatch, error) {
+	if errValidation := params.isValid(); errValidation != nil {
+		return nil,
+			errValidation
+	}
+
+	if errCtx := ctx.Err(); errCtx != nil {
+		return nil,
+			errCtx
+	}
+
+	loc.mu.Lock()
+	loc.expireHolds()
+	snapshot := loc.getSnapshot()
+	loc.mu.Unlock()
+
+	order, evaluations, errSearch := snapshot.searchBatchOrder(ctx, params)
+	if errSearch != nil {
+		return nil,
+			errSearch
+	}
+
+	loc.mu.Lock()
+	defer loc.mu.Unlock()
+
+	loc.expireHolds()
+
+	runIDs := make([]RunID, len(params.Runs))
+
+	for ix, run := range params.Runs {
+		runIDs[ix] = RunID(run.ID)
+	}
+
+	if errBooked := loc.checkNotBooked("ScheduleBatch", runIDs); errBooked != nil {
+		return nil,
+			errBooked
+	}
+
+	result, errPlace := loc.placeBatch(order, params)
+	if errPlace != nil {
+		return nil,
+			errPlace
+	}
+
+	result.Evaluations = evaluations
+
+	return result,
+		nil
+}
+
+// searchBatchOrder should be called on a snapshot, it books and cancels while evaluating.
+func (loc *Engine) searchBatchOrder(ctx context.Context, params *ParamsScheduleBatch) ([]*BatchRun, int, error) {
+	order := params.getInitialOrder()
+
+	best, errEvaluate := loc.evaluateBatch(order, params)
+	if errEvaluate != nil {
+		return nil,
+			0,
+			errEvaluate
+	}
+
+	evaluations := 1
+
+	for improved := true; improved; {
+		improved = false
+
+		for ix := range len(order) - 1 {
+			if ctx.Err() != nil {
+				return order,
+					evaluations,
+					nil
+			}
+
+			candidate := slices.Clone(order)
+			candidate[ix], candidate[ix+1] = candidate[ix+1], candidate[ix]
+
+			score, errEvaluateCandidate := loc.evaluateBatch(candidate, params)
+			if errEvaluateCandidate != nil {
+				return nil,
+					evaluations,
+					errEvaluateCandidate
+			}
+
+			evaluations++
+
+			if score.isBetter(best) {
+				order, best = candidate, score
+				improved = true
+			}
+		}
+	}
+
+	return order,
+		evaluations,
+		nil
+}
+
+// evaluateBatch places the runs in order, scores the placement and cancels it.
+func (loc *Engine) evaluateBatch(order []*BatchRun, params *ParamsScheduleBatch) (batchScore, error) {
+	placement, errPlace := loc.placeBatch(order, params)
+	if errPlace != nil {
+		return batchScore{},
+			errPlace
+	}
+
+	for _, run := range placement.Scheduled {
+		_ = loc.cancelRun(run.RunID)
+	}
+
+	return placement.getScore(params.Objective),
+		nil
+}
+
+// placeBatch books the runs in order, each at the start the objective prefers in the window.
+// Should be called under the location lock.
+func (loc *Engine) placeBatch(order []*BatchRun, params *ParamsScheduleBatch) (*ResponseScheduleBatch, error) {
+	var result ResponseScheduleBatch
+
+	window := &params.TimeInterval
+	strategy := params.Objective.getStrategy()
+
+	windowStartUTC := window.GetUTCTimeStart()
+	startUTC, finishUTC := window.GetUTCTimeEnd(), windowStartUTC
+
+	for _, run := range order {
+		option, conflict, errPlace := loc.placeRun(run.Run, window, windowStartUTC, strategy)
+		if errPlace != nil {
+			for _, scheduled := range result.Scheduled {
+				_ = loc.cancelRun(scheduled.RunID)
+			}
+
+			return nil,
+				errPlace
+		}
+
+		if len(conflict) > 0 {
+			result.Unscheduled = append(
+				result.Unscheduled,
+				&UnscheduledRun{
+					RunID:  RunID(run.ID),
+					Reason: conflict,
+				},
+			)
+
+			continue
+		}
+
+		runStartUTC := window.toUTCTimestamp(option.WhenCanStart)
+		runFinishUTC := runStartUTC + run.EstimatedDuration
+
+		var tardiness int64
+
+		if run.Due != 0 {
+			tardiness = max(0, runFinishUTC-window.toUTCTimestamp(run.Due))
+		}
+
+		result.Scheduled = append(
+			result.Scheduled,
+			&BatchRunResult{
+				TimeInterval: window.fromUTC(runStartUTC, runFinishUTC),
+				RunID:        RunID(run.ID),
+				Cost:         option.Cost,
+				Tardiness:    tardiness,
+			},
+		)
+
+		result.Cost = result.Cost.add(option.Cost)
+		result.WeightedTardiness = result.WeightedTardiness + float32(tardiness)*run.getWeight()
+
+		startUTC = min(startUTC, runStartUTC)
+		finishUTC = max(finishUTC, runFinishUTC)
+	}
+
+	if len(result.Scheduled) > 0 {
+		result.TimeInterval = window.fromUTC(startUTC, finishUTC)
+		result.Makespan = finishUTC - windowStartUTC
+	}
+
+	slices.SortStableFunc(
+		result.Scheduled,
+		func(a, b *BatchRunResult) int {
+			if a.GetUTCTimeStart() != b.GetUTCTimeStart() {
+				return cmp.Compare(a.GetUTCTimeStart(), b.GetUTCTimeStart())
+			}
+
+			return cmp.Compare(a.RunID, b.RunID)
+		},
+	)
+
+	return &result,
+		nil
+}
+
+// getSnapshot returns a copy of the location with copies of its resources,
+// for evaluating bookings without touching the live schedules.
+func (loc *Engine) getSnapshot() *Location {
+	resources := make([]*ResourceScheduled, len(loc.Resources))
+
+	for ix, resource := range loc.Resources {
+		resource.mu.RLock()
+
+		resources[ix] = &ResourceScheduled{
+			ResourceInfo:   resource.ResourceInfo,
+			Shifts:         resource.Shifts,
+			ShiftsTimeZone: resource.ShiftsTimeZone,
+			ShiftsOffset:   resource.ShiftsOffset,
+
+			schedule: resource.schedule.clone(),
+		}
+
+		resource.mu.RUnlock()
+	}
+
+	return &Engine{
+		ID:             loc.ID,
+		Name:           loc.Name,
+		LocationOffset: loc.LocationOffset,
+		TimeZone:       loc.TimeZone,
+		Calendar:       loc.Calendar,
+		Now:            loc.Now,
+		Strategy:       selectionOnly{loc.getStrategy()},
+
+		Resources: resources,
+	}
+}
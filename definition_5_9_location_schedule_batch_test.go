@@ -0,0 +1,236 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleBatch(t *testing.T) {
+	newLocation := func(t *testing.T, costs ...int64) *Location {
+		resources := make([]*ResourceScheduled, len(costs))
+
+		for ix, cost := range costs {
+			resources[ix] = newTestResource(ix+1, 1, cost, nil)
+		}
+
+		return newTestLocation(t, resources...)
+	}
+
+	newRun := func(id int64, duration int64) *BatchRun {
+		return &BatchRun{
+			Run: newTestRun(id, duration, 1),
+		}
+	}
+
+	window := TimeInterval{
+		TimeStart: now,
+		TimeEnd:   now + oneDay,
+	}
+
+	t.Run(
+		"1. Makespan, longest first",
+		func(t *testing.T) {
+			location := newLocation(t, 1, 1)
+
+			response, errSchedule := location.ScheduleBatch(
+				context.Background(),
+				&ParamsScheduleBatch{
+					TimeInterval: window,
+					Runs: []*BatchRun{
+						newRun(1, oneHour),
+						newRun(2, oneHour),
+						newRun(3, 2*oneHour),
+					},
+					Objective: MinimizeMakespan,
+				},
+			)
+			require.NoError(t, errSchedule)
+			require.Empty(t, response.Unscheduled)
+			require.Len(t,
+				response.Scheduled,
+				3,
+			)
+			require.EqualValues(t,
+				2*oneHour,
+				response.Makespan,
+				"submission order would take three hours",
+			)
+			require.Positive(t, response.Evaluations)
+
+			for _, run := range response.Scheduled {
+				booked := location.Resources[0].schedule.hasRun(run.RunID) ||
+					location.Resources[1].schedule.hasRun(run.RunID)
+
+				require.True(t, booked, run.RunID)
+			}
+		},
+	)
+
+	t.Run(
+		"2. Weighted tardiness, earliest due first",
+		func(t *testing.T) {
+			location := newLocation(t, 1)
+
+			relaxed := newRun(1, oneHour)
+			relaxed.Due = now + 3*oneHour
+
+			urgent := newRun(2, oneHour)
+			urgent.Due = now + oneHour
+			urgent.Weight = 2
+
+			response, errSchedule := location.ScheduleBatch(
+				context.Background(),
+				&ParamsScheduleBatch{
+					TimeInterval: window,
+					Runs:         []*BatchRun{relaxed, urgent},
+					Objective:    MinimizeWeightedTardiness,
+				},
+			)
+			require.NoError(t, errSchedule)
+			require.Zero(t, response.WeightedTardiness)
+			require.EqualValues(t,
+				2,
+				response.Scheduled[0].RunID,
+			)
+			require.EqualValues(t,
+				now,
+				response.Scheduled[0].TimeStart,
+			)
+		},
+	)
+
+	t.Run(
+		"3. Cost improved by search, unscheduled with reasons",
+		func(t *testing.T) {
+			location := newLocation(t, 1, 5)
+
+			response, errSchedule := location.ScheduleBatch(
+				context.Background(),
+				&ParamsScheduleBatch{
+					TimeInterval: TimeInterval{
+						TimeStart: now,
+						TimeEnd:   now + 2*oneHour,
+					},
+					Runs: []*BatchRun{
+						newRun(1, oneHour),
+						newRun(2, oneHour),
+						newRun(3, 3*oneHour),
+						newRun(4, 2*oneHour),
+					},
+					Objective: MinimizeCost,
+				},
+			)
+			require.NoError(t, errSchedule)
+
+			fmt.Println(
+				response,
+			)
+
+			require.Len(t,
+				response.Unscheduled,
+				1,
+			)
+			require.EqualValues(t,
+				3,
+				response.Unscheduled[0].RunID,
+			)
+			require.NotEmpty(t, response.Unscheduled[0].Reason)
+			require.EqualValues(t,
+				7,
+				response.Cost.Amount,
+				"longest first costs 11, search leaves the cheap machine to the short runs",
+			)
+		},
+	)
+
+	t.Run(
+		"4. Invalid",
+		func(t *testing.T) {
+			location := newLocation(t, 1)
+
+			_, errEmpty := location.ScheduleBatch(
+				context.Background(),
+				&ParamsScheduleBatch{
+					TimeInterval: window,
+					Objective:    MinimizeCost,
+				},
+			)
+			require.Error(t, errEmpty)
+
+			_, errDuplicate := location.ScheduleBatch(
+				context.Background(),
+				&ParamsScheduleBatch{
+					TimeInterval: window,
+					Runs:         []*BatchRun{newRun(1, oneHour), newRun(1, oneHour)},
+					Objective:    MinimizeCost,
+				},
+			)
+			require.Error(t, errDuplicate)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			_, errCtx := location.ScheduleBatch(
+				ctx,
+				&ParamsScheduleBatch{
+					TimeInterval: window,
+					Runs:         []*BatchRun{newRun(1, oneHour)},
+					Objective:    MinimizeCost,
+				},
+			)
+			require.ErrorIs(t, errCtx, context.Canceled)
+			require.Zero(t,
+				location.Resources[0].schedule.Len(),
+			)
+		},
+	)
+
+	t.Run(
+		"5. Makespan over cheaper resources, booked run rejected",
+		func(t *testing.T) {
+			location := newTestLocation(
+				t,
+
+				newTestResource(1, 1, 1, map[TimeInterval]RunID{
+					{TimeStart: now, TimeEnd: now + 3*oneHour}: 7,
+				}),
+				newTestResource(2, 1, 100, nil),
+			)
+
+			response, errSchedule := location.ScheduleBatch(
+				context.Background(),
+				&ParamsScheduleBatch{
+					TimeInterval: window,
+					Runs:         []*BatchRun{newRun(1, oneHour)},
+					Objective:    MinimizeMakespan,
+				},
+			)
+			require.NoError(t, errSchedule)
+			require.EqualValues(t,
+				oneHour,
+				response.Makespan,
+			)
+			require.EqualValues(t,
+				100,
+				response.Cost.Amount,
+			)
+
+			_, errBooked := location.ScheduleBatch(
+				context.Background(),
+				&ParamsScheduleBatch{
+					TimeInterval: window,
+					Runs:         []*BatchRun{newRun(7, oneHour)},
+					Objective:    MinimizeCost,
+				},
+			)
+			require.Error(t, errBooked)
+			require.Equal(t,
+				1,
+				location.Resources[1].schedule.Len(),
+			)
+		},
+	)
+}
@@ -17,7 +17,7 @@ func TestFindEarliestSlot(t *testing.T) {
 		offsetDifference       int64
 		expectedTime           int64
 		expectedResourcesCount int
-		expectedCost           float32
+		expectedCost           int64
 	}{
 		{
 			name: "1. Busy now, cheapest later",
@@ -26,7 +26,7 @@ func TestFindEarliestSlot(t *testing.T) {
 					&ResourceScheduled{
 						ResourceInfo: ResourceInfo{
 							ID:              2,
-							CostPerLoadUnit: map[uint8]float32{1: 3.0}, // High-cost now
+							CostPerLoadUnit: map[uint8]Money{1: {Amount: 3}}, // High-cost now
 						},
 					},
 				},
@@ -34,14 +34,14 @@ func TestFindEarliestSlot(t *testing.T) {
 					&ResourceScheduled{
 						ResourceInfo: ResourceInfo{
 							ID:              1,
-							CostPerLoadUnit: map[uint8]float32{1: 2.0}, // Cheaper later
+							CostPerLoadUnit: map[uint8]Money{1: {Amount: 2}}, // Cheaper later
 						},
 					},
 
 					&ResourceScheduled{
 						ResourceInfo: ResourceInfo{
 							ID:              2,
-							CostPerLoadUnit: map[uint8]float32{1: 3.0},
+							CostPerLoadUnit: map[uint8]Money{1: {Amount: 3}},
 						},
 					},
 				},
@@ -51,7 +51,7 @@ func TestFindEarliestSlot(t *testing.T) {
 
 			expectedTime:           now + oneHour,
 			expectedResourcesCount: 1,
-			expectedCost:           2.0,
+			expectedCost:           2,
 		},
 		{
 			name: "2. Busy now, cheaper later, multiple resources",
@@ -60,14 +60,14 @@ func TestFindEarliestSlot(t *testing.T) {
 					&ResourceScheduled{
 						ResourceInfo: ResourceInfo{
 							ID:              2,
-							CostPerLoadUnit: map[uint8]float32{1: 3.0},
+							CostPerLoadUnit: map[uint8]Money{1: {Amount: 3}},
 						},
 					},
 
 					&ResourceScheduled{
 						ResourceInfo: ResourceInfo{
 							ID:              3,
-							CostPerLoadUnit: map[uint8]float32{1: 3.0},
+							CostPerLoadUnit: map[uint8]Money{1: {Amount: 3}},
 						},
 					},
 				},
@@ -75,14 +75,14 @@ func TestFindEarliestSlot(t *testing.T) {
 					&ResourceScheduled{
 						ResourceInfo: ResourceInfo{
 							ID:              1,
-							CostPerLoadUnit: map[uint8]float32{1: 2.0},
+							CostPerLoadUnit: map[uint8]Money{1: {Amount: 2}},
 						},
 					},
 
 					&ResourceScheduled{
 						ResourceInfo: ResourceInfo{
 							ID:              2,
-							CostPerLoadUnit: map[uint8]float32{1: 3.0},
+							CostPerLoadUnit: map[uint8]Money{1: {Amount: 3}},
 						},
 					},
 				},
@@ -92,7 +92,7 @@ func TestFindEarliestSlot(t *testing.T) {
 
 			expectedTime:           now + oneHour,
 			expectedResourcesCount: 2,
-			expectedCost:           5.0,
+			expectedCost:           5,
 		},
 		{
 			name: "3. Busy now, available next hour",
@@ -100,13 +100,13 @@ func TestFindEarliestSlot(t *testing.T) {
 				{TimeStart: now + oneHour, TimeEnd: now + 2*oneHour}: {
 					&ResourceScheduled{
 						ResourceInfo: ResourceInfo{
-							CostPerLoadUnit: map[uint8]float32{1: 2.0},
+							CostPerLoadUnit: map[uint8]Money{1: {Amount: 2}},
 						},
 					},
 
 					&ResourceScheduled{
 						ResourceInfo: ResourceInfo{
-							CostPerLoadUnit: map[uint8]float32{1: 3.0},
+							CostPerLoadUnit: map[uint8]Money{1: {Amount: 3}},
 						},
 					},
 				},
@@ -116,7 +116,7 @@ func TestFindEarliestSlot(t *testing.T) {
 
 			expectedTime:           now + oneHour,
 			expectedResourcesCount: 1,
-			expectedCost:           2.0, // Cheaper resource
+			expectedCost:           2, // Cheaper resource
 		},
 		{
 			name: "4. Busy now, available next hour, multiple resources",
@@ -124,13 +124,13 @@ func TestFindEarliestSlot(t *testing.T) {
 				{TimeStart: now + oneHour, TimeEnd: now + 2*oneHour}: {
 					&ResourceScheduled{
 						ResourceInfo: ResourceInfo{
-							CostPerLoadUnit: map[uint8]float32{1: 2.0},
+							CostPerLoadUnit: map[uint8]Money{1: {Amount: 2}},
 						},
 					},
 
 					&ResourceScheduled{
 						ResourceInfo: ResourceInfo{
-							CostPerLoadUnit: map[uint8]float32{1: 3.0},
+							CostPerLoadUnit: map[uint8]Money{1: {Amount: 3}},
 						},
 					},
 				},
@@ -140,7 +140,7 @@ func TestFindEarliestSlot(t *testing.T) {
 
 			expectedTime:           now + oneHour,
 			expectedResourcesCount: 2,
-			expectedCost:           5.0,
+			expectedCost:           5,
 		},
 	}
 
@@ -151,6 +151,7 @@ func TestFindEarliestSlot(t *testing.T) {
 				earliest, resources := findEarliestSlot(
 					&paramsFindEarliestSlot{
 						Possibilities:    tt.possibilities,
+						Run:              &Run{RunLoad: RunLoad{Load: 1, LoadUnit: 1}},
 						NeededCount:      tt.neededCount,
 						OffsetDifference: tt.offsetDifference,
 					},
@@ -178,10 +179,10 @@ func TestFindEarliestSlot(t *testing.T) {
 					),
 				)
 
-				var totalCost float32
+				var totalCost int64
 
 				for _, resource := range resources {
-					totalCost = totalCost + resource.CostPerLoadUnit[1]
+					totalCost = totalCost + resource.CostPerLoadUnit[1].Amount
 				}
 
 				require.Equal(t,
@@ -189,7 +190,7 @@ func TestFindEarliestSlot(t *testing.T) {
 					totalCost,
 
 					fmt.Sprintf(
-						"expected cost: %f, got cost: %f",
+						"expected cost: %d, got cost: %d",
 						tt.expectedCost,
 						totalCost,
 					),
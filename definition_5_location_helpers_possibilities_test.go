@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestPopulatePossibilities(t *testing.T) {
@@ -21,11 +22,11 @@ func TestPopulatePossibilities(t *testing.T) {
 						&ResourceScheduled{
 							ResourceInfo: ResourceInfo{
 								ID:              1,
-								CostPerLoadUnit: map[uint8]float32{1: 2.0},
+								CostPerLoadUnit: map[uint8]Money{1: {Amount: 2}},
 								ResourceType:    1,
 							},
 
-							schedule: map[TimeInterval]RunID{},
+							schedule: newTestSchedule(map[TimeInterval]RunID{}),
 						},
 					},
 				},
@@ -39,11 +40,11 @@ func TestPopulatePossibilities(t *testing.T) {
 					&ResourceScheduled{
 						ResourceInfo: ResourceInfo{
 							ID:              1,
-							CostPerLoadUnit: map[uint8]float32{1: 2.0},
+							CostPerLoadUnit: map[uint8]Money{1: {Amount: 2}},
 							ResourceType:    1,
 						},
 
-						schedule: map[TimeInterval]RunID{},
+						schedule: newTestSchedule(map[TimeInterval]RunID{}),
 					},
 				},
 			},
@@ -56,22 +57,22 @@ func TestPopulatePossibilities(t *testing.T) {
 						&ResourceScheduled{
 							ResourceInfo: ResourceInfo{
 								ID:              1,
-								CostPerLoadUnit: map[uint8]float32{1: 2.0},
+								CostPerLoadUnit: map[uint8]Money{1: {Amount: 2}},
 								ResourceType:    1,
 							},
 
-							schedule: map[TimeInterval]RunID{},
+							schedule: newTestSchedule(map[TimeInterval]RunID{}),
 						},
 						&ResourceScheduled{
 							ResourceInfo: ResourceInfo{
 								ID:              2,
-								CostPerLoadUnit: map[uint8]float32{1: 3.0},
+								CostPerLoadUnit: map[uint8]Money{1: {Amount: 3}},
 								ResourceType:    1,
 							},
 
-							schedule: map[TimeInterval]RunID{
+							schedule: newTestSchedule(map[TimeInterval]RunID{
 								{TimeStart: now, TimeEnd: now + oneHour}: Maintenance,
-							},
+							}),
 						},
 					},
 				},
@@ -85,11 +86,11 @@ func TestPopulatePossibilities(t *testing.T) {
 					&ResourceScheduled{
 						ResourceInfo: ResourceInfo{
 							ID:              1,
-							CostPerLoadUnit: map[uint8]float32{1: 2.0},
+							CostPerLoadUnit: map[uint8]Money{1: {Amount: 2}},
 							ResourceType:    1,
 						},
 
-						schedule: map[TimeInterval]RunID{},
+						schedule: newTestSchedule(map[TimeInterval]RunID{}),
 					},
 				},
 			},
@@ -102,14 +103,14 @@ func TestPopulatePossibilities(t *testing.T) {
 						&ResourceScheduled{
 							ResourceInfo: ResourceInfo{
 								ID:              1,
-								CostPerLoadUnit: map[uint8]float32{1: 2.0},
+								CostPerLoadUnit: map[uint8]Money{1: {Amount: 2}},
 								ResourceType:    1,
 							},
 
-							schedule: map[TimeInterval]RunID{
+							schedule: newTestSchedule(map[TimeInterval]RunID{
 								{TimeStart: now + 3*oneHour, TimeEnd: now + 4*oneHour}: Maintenance,
 								{TimeStart: now + 5*oneHour, TimeEnd: now + 6*oneHour}: Maintenance,
-							},
+							}),
 						},
 					},
 				},
@@ -123,14 +124,14 @@ func TestPopulatePossibilities(t *testing.T) {
 					&ResourceScheduled{
 						ResourceInfo: ResourceInfo{
 							ID:              1,
-							CostPerLoadUnit: map[uint8]float32{1: 2.0},
+							CostPerLoadUnit: map[uint8]Money{1: {Amount: 2}},
 							ResourceType:    1,
 						},
 
-						schedule: map[TimeInterval]RunID{
+						schedule: newTestSchedule(map[TimeInterval]RunID{
 							{TimeStart: now + 3*oneHour, TimeEnd: now + 4*oneHour}: Maintenance,
 							{TimeStart: now + 5*oneHour, TimeEnd: now + 6*oneHour}: Maintenance,
-						},
+						}),
 					},
 				},
 			},
@@ -143,25 +144,25 @@ func TestPopulatePossibilities(t *testing.T) {
 						&ResourceScheduled{
 							ResourceInfo: ResourceInfo{
 								ID:              1,
-								CostPerLoadUnit: map[uint8]float32{1: 2.0},
+								CostPerLoadUnit: map[uint8]Money{1: {Amount: 2}},
 								ResourceType:    1,
 							},
 
-							schedule: map[TimeInterval]RunID{
+							schedule: newTestSchedule(map[TimeInterval]RunID{
 								{TimeStart: now, TimeEnd: now + oneHour}: Maintenance,
-							},
+							}),
 						},
 
 						&ResourceScheduled{
 							ResourceInfo: ResourceInfo{
 								ID:              2,
-								CostPerLoadUnit: map[uint8]float32{1: 3.0},
+								CostPerLoadUnit: map[uint8]Money{1: {Amount: 3}},
 								ResourceType:    1,
 							},
 
-							schedule: map[TimeInterval]RunID{
+							schedule: newTestSchedule(map[TimeInterval]RunID{
 								{TimeStart: now, TimeEnd: now + oneHour}: Maintenance,
-							},
+							}),
 						},
 					},
 				},
@@ -175,13 +176,13 @@ func TestPopulatePossibilities(t *testing.T) {
 					&ResourceScheduled{
 						ResourceInfo: ResourceInfo{
 							ID:              1,
-							CostPerLoadUnit: map[uint8]float32{1: 2.0},
+							CostPerLoadUnit: map[uint8]Money{1: {Amount: 2}},
 							ResourceType:    1,
 						},
 
-						schedule: map[TimeInterval]RunID{
+						schedule: newTestSchedule(map[TimeInterval]RunID{
 							{TimeStart: now, TimeEnd: now + oneHour}: Maintenance,
-						},
+						}),
 					},
 				},
 			},
@@ -205,13 +206,13 @@ func TestPopulatePossibilities(t *testing.T) {
 						&ResourceScheduled{
 							ResourceInfo: ResourceInfo{
 								ID:              1,
-								CostPerLoadUnit: map[uint8]float32{1: 2.0},
+								CostPerLoadUnit: map[uint8]Money{1: {Amount: 2}},
 								ResourceType:    1,
 							},
 
-							schedule: map[TimeInterval]RunID{
+							schedule: newTestSchedule(map[TimeInterval]RunID{
 								{TimeStart: now, TimeEnd: now + oneHour}: Maintenance,
-							},
+							}),
 						},
 					},
 				},
@@ -230,13 +231,13 @@ func TestPopulatePossibilities(t *testing.T) {
 						&ResourceScheduled{
 							ResourceInfo: ResourceInfo{
 								ID:              1,
-								CostPerLoadUnit: map[uint8]float32{1: 2.0},
+								CostPerLoadUnit: map[uint8]Money{1: {Amount: 2}},
 								ResourceType:    1,
 							},
 
-							schedule: map[TimeInterval]RunID{
+							schedule: newTestSchedule(map[TimeInterval]RunID{
 								{TimeStart: now, TimeEnd: now + halfHour}: Maintenance,
-							},
+							}),
 						},
 					},
 				},
@@ -250,13 +251,13 @@ func TestPopulatePossibilities(t *testing.T) {
 					&ResourceScheduled{
 						ResourceInfo: ResourceInfo{
 							ID:              1,
-							CostPerLoadUnit: map[uint8]float32{1: 2.0},
+							CostPerLoadUnit: map[uint8]Money{1: {Amount: 2}},
 							ResourceType:    1,
 						},
 
-						schedule: map[TimeInterval]RunID{
+						schedule: newTestSchedule(map[TimeInterval]RunID{
 							{TimeStart: now, TimeEnd: now + halfHour}: Maintenance,
-						},
+						}),
 					},
 				},
 			},
@@ -269,23 +270,23 @@ func TestPopulatePossibilities(t *testing.T) {
 						&ResourceScheduled{
 							ResourceInfo: ResourceInfo{
 								ID:              1,
-								CostPerLoadUnit: map[uint8]float32{1: 2.0},
+								CostPerLoadUnit: map[uint8]Money{1: {Amount: 2}},
 								ResourceType:    1,
 							},
 
-							schedule: map[TimeInterval]RunID{
+							schedule: newTestSchedule(map[TimeInterval]RunID{
 								{TimeStart: now, TimeEnd: now + oneHour}:               Maintenance,
 								{TimeStart: now + 2*oneHour, TimeEnd: now + 3*oneHour}: Maintenance,
-							},
+							}),
 						},
 						&ResourceScheduled{
 							ResourceInfo: ResourceInfo{
 								ID:              2,
-								CostPerLoadUnit: map[uint8]float32{1: 3.0},
+								CostPerLoadUnit: map[uint8]Money{1: {Amount: 3}},
 								ResourceType:    1,
 							},
 
-							schedule: map[TimeInterval]RunID{},
+							schedule: newTestSchedule(map[TimeInterval]RunID{}),
 						},
 					},
 				},
@@ -302,44 +303,44 @@ func TestPopulatePossibilities(t *testing.T) {
 					&ResourceScheduled{
 						ResourceInfo: ResourceInfo{
 							ID:              2,
-							CostPerLoadUnit: map[uint8]float32{1: 3.0},
+							CostPerLoadUnit: map[uint8]Money{1: {Amount: 3}},
 							ResourceType:    1,
 						},
 
-						schedule: map[TimeInterval]RunID{},
+						schedule: newTestSchedule(map[TimeInterval]RunID{}),
 					},
 				},
 				{TimeStart: now + halfHour, TimeEnd: now + oneHour}: {
 					&ResourceScheduled{
 						ResourceInfo: ResourceInfo{
 							ID:              2,
-							CostPerLoadUnit: map[uint8]float32{1: 3.0},
+							CostPerLoadUnit: map[uint8]Money{1: {Amount: 3}},
 							ResourceType:    1,
 						},
 
-						schedule: map[TimeInterval]RunID{},
+						schedule: newTestSchedule(map[TimeInterval]RunID{}),
 					},
 				},
 				{TimeStart: now + oneHour, TimeEnd: now + oneHour + halfHour}: {
 					&ResourceScheduled{
 						ResourceInfo: ResourceInfo{
 							ID:              1,
-							CostPerLoadUnit: map[uint8]float32{1: 2.0},
+							CostPerLoadUnit: map[uint8]Money{1: {Amount: 2}},
 							ResourceType:    1,
 						},
 
-						schedule: map[TimeInterval]RunID{},
+						schedule: newTestSchedule(map[TimeInterval]RunID{}),
 					},
 				},
 				{TimeStart: now + oneHour + halfHour, TimeEnd: now + 2*oneHour}: {
 					&ResourceScheduled{
 						ResourceInfo: ResourceInfo{
 							ID:              1,
-							CostPerLoadUnit: map[uint8]float32{1: 2.0},
+							CostPerLoadUnit: map[uint8]Money{1: {Amount: 2}},
 							ResourceType:    1,
 						},
 
-						schedule: map[TimeInterval]RunID{},
+						schedule: newTestSchedule(map[TimeInterval]RunID{}),
 					},
 				},
 			},
@@ -353,38 +354,38 @@ func TestPopulatePossibilities(t *testing.T) {
 						&ResourceScheduled{
 							ResourceInfo: ResourceInfo{
 								ID:              1,
-								CostPerLoadUnit: map[uint8]float32{1: 2.0},
+								CostPerLoadUnit: map[uint8]Money{1: {Amount: 2}},
 								ResourceType:    1,
 							},
 
-							schedule: map[TimeInterval]RunID{
+							schedule: newTestSchedule(map[TimeInterval]RunID{
 								{TimeStart: now, TimeEnd: now + oneHour}:               Maintenance,
 								{TimeStart: now + 2*oneHour, TimeEnd: now + 3*oneHour}: Maintenance,
-							},
+							}),
 						},
 						&ResourceScheduled{
 							ResourceInfo: ResourceInfo{
 								ID:              2,
-								CostPerLoadUnit: map[uint8]float32{1: 3.0},
+								CostPerLoadUnit: map[uint8]Money{1: {Amount: 3}},
 								ResourceType:    1,
 							},
 
-							schedule: map[TimeInterval]RunID{
+							schedule: newTestSchedule(map[TimeInterval]RunID{
 								{TimeStart: now + oneHour, TimeEnd: now + oneHour + halfHour}: Maintenance,
-							},
+							}),
 						},
 					},
 					2: {
 						&ResourceScheduled{
 							ResourceInfo: ResourceInfo{
 								ID:              3,
-								CostPerLoadUnit: map[uint8]float32{1: 1.0},
+								CostPerLoadUnit: map[uint8]Money{1: {Amount: 1}},
 								ResourceType:    2,
 							},
 
-							schedule: map[TimeInterval]RunID{
+							schedule: newTestSchedule(map[TimeInterval]RunID{
 								{TimeStart: now + oneHour, TimeEnd: now + oneHour + halfHour}: Maintenance,
-							},
+							}),
 						},
 					},
 				},
@@ -404,83 +405,110 @@ func TestPopulatePossibilities(t *testing.T) {
 					&ResourceScheduled{
 						ResourceInfo: ResourceInfo{
 							ID:              2,
-							CostPerLoadUnit: map[uint8]float32{1: 3.0},
+							CostPerLoadUnit: map[uint8]Money{1: {Amount: 3}},
 							ResourceType:    1,
 						},
 
-						schedule: map[TimeInterval]RunID{
+						schedule: newTestSchedule(map[TimeInterval]RunID{
 							{TimeStart: now + oneHour, TimeEnd: now + oneHour + halfHour}: Maintenance,
-						},
+						}),
 					},
 					&ResourceScheduled{
 						ResourceInfo: ResourceInfo{
 							ID:              3,
-							CostPerLoadUnit: map[uint8]float32{1: 1.0},
+							CostPerLoadUnit: map[uint8]Money{1: {Amount: 1}},
 							ResourceType:    1,
 						},
 
-						schedule: map[TimeInterval]RunID{
+						schedule: newTestSchedule(map[TimeInterval]RunID{
 							{TimeStart: now + oneHour, TimeEnd: now + oneHour + halfHour}: Maintenance,
-						},
+						}),
 					},
 				},
 				{TimeStart: now + halfHour, TimeEnd: now + oneHour}: {
 					&ResourceScheduled{
 						ResourceInfo: ResourceInfo{
 							ID:              2,
-							CostPerLoadUnit: map[uint8]float32{1: 3.0},
+							CostPerLoadUnit: map[uint8]Money{1: {Amount: 3}},
 							ResourceType:    1,
 						},
 
-						schedule: map[TimeInterval]RunID{
+						schedule: newTestSchedule(map[TimeInterval]RunID{
 							{TimeStart: now + oneHour, TimeEnd: now + oneHour + halfHour}: Maintenance,
-						},
+						}),
 					},
 					&ResourceScheduled{
 						ResourceInfo: ResourceInfo{
 							ID:              3,
-							CostPerLoadUnit: map[uint8]float32{1: 1.0},
+							CostPerLoadUnit: map[uint8]Money{1: {Amount: 1}},
 							ResourceType:    1,
 						},
 
-						schedule: map[TimeInterval]RunID{
+						schedule: newTestSchedule(map[TimeInterval]RunID{
 							{TimeStart: now + oneHour, TimeEnd: now + oneHour + halfHour}: Maintenance,
-						},
+						}),
 					},
 				},
 				{TimeStart: now + oneHour + halfHour, TimeEnd: now + 2*oneHour}: {
 					&ResourceScheduled{
 						ResourceInfo: ResourceInfo{
 							ID:              1,
-							CostPerLoadUnit: map[uint8]float32{1: 2.0},
+							CostPerLoadUnit: map[uint8]Money{1: {Amount: 2}},
 							ResourceType:    1,
 						},
 
-						schedule: map[TimeInterval]RunID{
+						schedule: newTestSchedule(map[TimeInterval]RunID{
 							{TimeStart: now, TimeEnd: now + oneHour}:               Maintenance,
 							{TimeStart: now + 2*oneHour, TimeEnd: now + 3*oneHour}: Maintenance,
-						},
+						}),
 					},
 					&ResourceScheduled{
 						ResourceInfo: ResourceInfo{
 							ID:              3,
-							CostPerLoadUnit: map[uint8]float32{1: 1.0},
+							CostPerLoadUnit: map[uint8]Money{1: {Amount: 1}},
 							ResourceType:    1,
 						},
 
-						schedule: map[TimeInterval]RunID{
+						schedule: newTestSchedule(map[TimeInterval]RunID{
 							{TimeStart: now + oneHour, TimeEnd: now + oneHour + halfHour}: Maintenance,
-						},
+						}),
 					},
 				},
 			},
 		},
+		{
+			// a start in the free half hour would overlap the booking after it
+			name: "10. free time shorter than the run is not a slot",
+			params: paramsPopulatePossibilities{
+				Candidates: map[uint8][]*ResourceScheduled{
+					1: {
+						newTestResource(1, 1, 2, map[TimeInterval]RunID{
+							{TimeStart: now + halfHour, TimeEnd: now + oneHour + halfHour}: Maintenance,
+						}),
+					},
+				},
+				ResourcesNeededPerType: map[uint8]uint16{1: 1},
+				TimeInterval: TimeInterval{
+					TimeStart: now,
+					TimeEnd:   now + 2*oneHour + halfHour,
+				},
+				Duration: oneHour,
+			},
+
+			expected: map[TimeInterval][]*ResourceScheduled{
+				{TimeStart: now + oneHour + halfHour, TimeEnd: now + 2*oneHour + halfHour}: {
+					newTestResource(1, 1, 2, nil),
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(
 			tt.name,
 			func(t *testing.T) {
+				tt.params.Run = &Run{RunLoad: RunLoad{Load: 1, LoadUnit: 1}}
+
 				result := populatePossibilities(&tt.params)
 
 				assert.Equal(t,
@@ -529,7 +557,7 @@ func TestPopulatePossibilities(t *testing.T) {
 
 						if resources[i].CostPerLoadUnit[1] != expectedResources[i].CostPerLoadUnit[1] {
 							t.Errorf(
-								"for interval %v, resource %d has wrong cost (expected %f, got %f)",
+								"for interval %v, resource %d has wrong cost (expected %s, got %s)",
 								interval,
 								i,
 								expectedResources[i].CostPerLoadUnit[1],
@@ -542,3 +570,108 @@ func TestPopulatePossibilities(t *testing.T) {
 		)
 	}
 }
+
+func TestPopulatePossibilitiesContinuousStart(t *testing.T) {
+	// first free after an hour and a half, second free from half an hour to four hours
+	candidates := map[uint8][]*ResourceScheduled{
+		1: {
+			newTestResource(1, 1, 1, map[TimeInterval]RunID{
+				{TimeStart: now, TimeEnd: now + oneHour + halfHour}: Maintenance,
+			}),
+		},
+		2: {
+			newTestResource(2, 2, 1, map[TimeInterval]RunID{
+				{TimeStart: now, TimeEnd: now + halfHour}:              Maintenance,
+				{TimeStart: now + 4*oneHour, TimeEnd: now + 5*oneHour}: Maintenance,
+			}),
+		},
+	}
+
+	getStarts := func(params paramsPopulatePossibilities) []int64 {
+		params.Candidates = candidates
+		params.ResourcesNeededPerType = map[uint8]uint16{1: 1, 2: 1}
+		params.TimeInterval = TimeInterval{
+			TimeStart: now,
+			TimeEnd:   now + 5*oneHour,
+		}
+		params.Duration = 2 * oneHour
+		params.Run = &Run{RunLoad: RunLoad{Load: 1, LoadUnit: 1}}
+
+		result := make([]int64, 0)
+
+		for slot := range populatePossibilities(&params) {
+			result = append(result, slot.TimeStart)
+		}
+
+		return result
+	}
+
+	assert.Empty(t,
+		getStarts(paramsPopulatePossibilities{}),
+		"grids of the duration do not meet",
+	)
+
+	assert.Equal(t,
+		[]int64{now + oneHour + halfHour},
+		getStarts(
+			paramsPopulatePossibilities{
+				ContinuousStart: true,
+			},
+		),
+	)
+
+	// now is 100 seconds past the quarter
+	assert.ElementsMatch(t,
+		[]int64{now + oneHour + halfHour + 800, now + oneHour + halfHour + 1700},
+		getStarts(
+			paramsPopulatePossibilities{
+				ContinuousStart: true,
+				StartStep:       900,
+			},
+		),
+	)
+
+	engine, errCr := NewEngine(
+		&ParamsNewEngine{
+			ID:        1,
+			Name:      t.Name(),
+			Resources: []*ResourceScheduled{candidates[1][0], candidates[2][0]},
+		},
+	)
+	require.NoError(t, errCr)
+
+	options, errGet := engine.GetSchedulingOptions(
+		&ParamsCanRun{
+			TimeInterval: TimeInterval{
+				TimeStart: now,
+				TimeEnd:   now + 5*oneHour,
+			},
+
+			TaskRun: &Run{
+				ID:                1,
+				EstimatedDuration: 2 * oneHour,
+
+				Dependencies: []RunDependency{
+					{ResourceType: 1, ResourceQuantity: 1},
+					{ResourceType: 2, ResourceQuantity: 1},
+				},
+
+				RunLoad: RunLoad{
+					Load:     1,
+					LoadUnit: 1,
+				},
+			},
+
+			ContinuousStart: true,
+		},
+	)
+	require.NoError(t, errGet)
+	require.Len(t,
+		options,
+		1,
+	)
+	require.EqualValues(t,
+		now+oneHour+halfHour,
+		options[0].WhenCanStart,
+	)
+}
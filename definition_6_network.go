@@ -0,0 +1,257 @@
+package scheduler
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	goerrors "github.com/TudorHulban/go-errors"
+	"github.com/asaskevich/govalidator"
+)
+
+// Network groups locations so a task can be moved from its home location
+// to another one when home cannot run it or another location is cheaper.
+// Costs are compared across locations, all share a currency.
+type Network struct {
+	Name      string
+	Locations []*Location
+
+	ID int64
+}
+
+type ParamsNewNetwork struct {
+	Name      string      `valid:"required"`
+	Locations []*Location `valid:"required"`
+
+	ID int64 `valid:"required"`
+}
+
+func NewNetwork(params *ParamsNewNetwork) (*Network, error) {
+	if _, errValidation := govalidator.ValidateStruct(params); errValidation != nil {
+		return nil,
+			goerrors.ErrServiceValidation{
+				ServiceName: "Network",
+				Caller:      "NewNetwork",
+				Issue:       errValidation,
+			}
+	}
+
+	seen := make(map[int64]bool, len(params.Locations))
+
+	var currency Currency
+
+	for _, location := range params.Locations {
+		if location == nil {
+			return nil,
+				goerrors.ErrValidation{
+					Caller: "NewNetwork",
+					Issue: goerrors.ErrNilInput{
+						InputName: "Locations",
+					},
+				}
+		}
+
+		if seen[location.ID] {
+			return nil,
+				goerrors.ErrValidation{
+					Caller: "NewNetwork",
+					Issue: goerrors.ErrInvalidInput{
+						InputName:  "Locations",
+						InputValue: location.ID,
+						Issue:      fmt.Errorf("duplicate location ID %d", location.ID),
+					},
+				}
+		}
+
+		seen[location.ID] = true
+
+		locationCurrency, errCurrency := getCurrency(currency, Money{Currency: location.Currency})
+		if errCurrency != nil {
+			return nil,
+				goerrors.ErrValidation{
+					Caller: "NewNetwork",
+					Issue:  errCurrency,
+				}
+		}
+
+		currency = locationCurrency
+	}
+
+	return &Network{
+			ID:   params.ID,
+			Name: params.Name,
+
+			Locations: params.Locations,
+		},
+		nil
+}
+
+func (n *Network) GetLocation(locationID int64) (*Location, error) {
+	for _, location := range n.Locations {
+		if location.ID == locationID {
+			return location,
+				nil
+		}
+	}
+
+	return nil,
+		goerrors.ErrEntryNotFound{
+			Key: locationID,
+		}
+}
+
+// LocationOption is the evaluation of a task in one location.
+// WhenCanStart is expressed in task time, whatever the location offset.
+type LocationOption struct {
+	Location          *Location
+	SelectedResources []*ResourceScheduled
+
+	possibilities *ResponseGetPossibilities
+
+	WhenCanStart     int64
+	Cost             Money
+	CanRunInInterval bool // task fits in the requested interval
+	CanRunAtStart    bool // task can start at the requested time start
+}
+
+func (lo *LocationOption) String() string {
+	return fmt.Sprintf(
+		"LocationOption{Location: %d (%q), WhenCanStart: %d, Cost: %s, CanRunInInterval: %t, CanRunAtStart: %t}",
+
+		lo.Location.ID,
+		lo.Location.Name,
+		lo.WhenCanStart,
+		lo.Cost,
+		lo.CanRunInInterval,
+		lo.CanRunAtStart,
+	)
+}
+
+type ParamsSuggestLocation struct {
+	ParamsCanRun
+
+	HomeLocationID int64
+}
+
+type ResponseSuggestLocation struct {
+	Home *LocationOption
+
+	// Alternatives holds the other locations that can run the task
+	// in the requested interval, cheapest first, then earliest.
+	Alternatives []*LocationOption
+
+	// ShouldMove is true when home cannot run the task in the requested interval
+	// or an alternative runs it cheaper.
+	ShouldMove bool
+}
+
+// GetSuggested returns the home option unless moving is advised, in which case
+// the best ranked alternative. Nil if no location can run the task.
+func (r *ResponseSuggestLocation) GetSuggested() *LocationOption {
+	if !r.ShouldMove {
+		return r.Home
+	}
+
+	if len(r.Alternatives) == 0 {
+		return nil
+	}
+
+	return r.Alternatives[0]
+}
+
+func (r *ResponseSuggestLocation) String() string {
+	var sb strings.Builder
+
+	sb.WriteString("ResponseSuggestLocation{\n")
+	sb.WriteString(fmt.Sprintf("\tHome: %s,\n", r.Home.String()))
+	sb.WriteString("\tAlternatives: [\n")
+
+	for _, alternative := range r.Alternatives {
+		sb.WriteString(fmt.Sprintf("\t\t%s,\n", alternative.String()))
+	}
+
+	sb.WriteString("\t],\n")
+	sb.WriteString(fmt.Sprintf("\tShouldMove: %t\n", r.ShouldMove))
+	sb.WriteString("}")
+
+	return sb.String()
+}
+
+// SuggestLocation evaluates the task in every location of the network, without booking.
+// Each location is evaluated under its lock, safe alongside bookings on it.
+// Times in the response are in task time, offsets of each location are accounted for.
+func (n *Network) SuggestLocation(params *ParamsSuggestLocation) (*ResponseSuggestLocation, error) {
+	if params.TaskRun == nil {
+		return nil,
+			goerrors.ErrValidation{
+				Caller: "SuggestLocation",
+				Issue: goerrors.ErrNilInput{
+					InputName: "TaskRun",
+				},
+			}
+	}
+
+	home, errGetHome := n.GetLocation(params.HomeLocationID)
+	if errGetHome != nil {
+		return nil,
+			errGetHome
+	}
+
+	homeOption, errEvaluateHome := home.evaluateLocked(&params.ParamsCanRun)
+	if errEvaluateHome != nil {
+		return nil,
+			errEvaluateHome
+	}
+
+	alternatives := make([]*LocationOption, 0)
+
+	for _, location := range n.Locations {
+		if location.ID == home.ID {
+			continue
+		}
+
+		option, errEvaluate := location.evaluateLocked(&params.ParamsCanRun)
+		if errEvaluate != nil {
+			return nil,
+				errEvaluate
+		}
+
+		if option.CanRunInInterval {
+			alternatives = append(alternatives, option)
+		}
+	}
+
+	slices.SortStableFunc(
+		alternatives,
+		compareLocationOptions,
+	)
+
+	result := ResponseSuggestLocation{
+		Home:         homeOption,
+		Alternatives: alternatives,
+	}
+
+	if len(alternatives) > 0 {
+		result.ShouldMove = !homeOption.CanRunInInterval ||
+			alternatives[0].Cost.Amount < homeOption.Cost.Amount
+	}
+
+	return &result,
+		nil
+}
+
+func compareLocationOptions(a, b *LocationOption) int {
+	if a.Cost.Amount != b.Cost.Amount {
+		return compareMoney(a.Cost, b.Cost)
+	}
+
+	if a.WhenCanStart != b.WhenCanStart {
+		return ternary(a.WhenCanStart < b.WhenCanStart, -1, 1)
+	}
+
+	if a.Location.ID != b.Location.ID {
+		return ternary(a.Location.ID < b.Location.ID, -1, 1)
+	}
+
+	return 0
+}
@@ -0,0 +1,317 @@
+package scheduler
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuggestLocation(t *testing.T) {
+	newLocationWResource := func(id int64, offset int64, cost int64, schedule map[TimeInterval]RunID) *Location {
+		location, errCr := NewLocation(
+			&ParamsNewLocation{
+				ID:             id,
+				Name:           fmt.Sprintf("Location %d", id),
+				LocationOffset: offset,
+
+				Resources: []*ResourceScheduled{
+					newTestResource(int(id), 1, cost, schedule),
+				},
+			},
+		)
+		require.NoError(t, errCr)
+
+		return location
+	}
+
+	taskRun := Run{
+		ID:                1,
+		EstimatedDuration: oneHour,
+
+		Dependencies: []RunDependency{
+			{
+				ResourceType:     1,
+				ResourceQuantity: 1,
+			},
+		},
+
+		RunLoad: RunLoad{
+			Load:     1,
+			LoadUnit: 1,
+		},
+	}
+
+	paramsCanRun := ParamsCanRun{
+		TimeInterval: TimeInterval{
+			TimeStart: now,
+			TimeEnd:   now + oneHour,
+		},
+
+		TaskRun: &taskRun,
+	}
+
+	t.Run(
+		"1. Home busy, move to cheapest alternative",
+		func(t *testing.T) {
+			network, errCr := NewNetwork(
+				&ParamsNewNetwork{
+					ID:   1,
+					Name: t.Name(),
+
+					Locations: []*Location{
+						newLocationWResource(
+							1,
+							0,
+							1,
+							map[TimeInterval]RunID{
+								{TimeStart: now, TimeEnd: now + oneDay}: Maintenance,
+							},
+						),
+						newLocationWResource(
+							2,
+							oneHour, // UTC+1, busy in location time before the task interval in UTC
+							3,
+							map[TimeInterval]RunID{
+								{TimeStart: now, TimeEnd: now + oneHour, SecondsOffset: oneHour}: Maintenance,
+							},
+						),
+						newLocationWResource(3, 0, 2, map[TimeInterval]RunID{}),
+					},
+				},
+			)
+			require.NoError(t, errCr)
+
+			response, errSuggest := network.SuggestLocation(
+				&ParamsSuggestLocation{
+					ParamsCanRun:   paramsCanRun,
+					HomeLocationID: 1,
+				},
+			)
+			require.NoError(t, errSuggest)
+			require.False(t, response.Home.CanRunInInterval)
+			require.True(t, response.ShouldMove)
+			require.Len(t,
+				response.Alternatives,
+				2,
+			)
+			require.EqualValues(t,
+				3,
+				response.GetSuggested().Location.ID,
+			)
+			require.EqualValues(t,
+				2,
+				response.GetSuggested().Cost.Amount,
+			)
+			require.EqualValues(t,
+				now,
+				response.GetSuggested().WhenCanStart,
+			)
+
+			fmt.Println(
+				response,
+			)
+		},
+	)
+
+	t.Run(
+		"2. Home available and cheapest, stay",
+		func(t *testing.T) {
+			network, errCr := NewNetwork(
+				&ParamsNewNetwork{
+					ID:   1,
+					Name: t.Name(),
+
+					Locations: []*Location{
+						newLocationWResource(1, 0, 1, map[TimeInterval]RunID{}),
+						newLocationWResource(2, 0, 2, map[TimeInterval]RunID{}),
+					},
+				},
+			)
+			require.NoError(t, errCr)
+
+			response, errSuggest := network.SuggestLocation(
+				&ParamsSuggestLocation{
+					ParamsCanRun:   paramsCanRun,
+					HomeLocationID: 1,
+				},
+			)
+			require.NoError(t, errSuggest)
+			require.True(t, response.Home.CanRunAtStart)
+			require.False(t, response.ShouldMove)
+			require.Len(t,
+				response.Alternatives,
+				1,
+			)
+			require.EqualValues(t,
+				1,
+				response.GetSuggested().Location.ID,
+			)
+		},
+	)
+
+	t.Run(
+		"3. Home available, alternative cheaper",
+		func(t *testing.T) {
+			network, errCr := NewNetwork(
+				&ParamsNewNetwork{
+					ID:   1,
+					Name: t.Name(),
+
+					Locations: []*Location{
+						newLocationWResource(1, 0, 3, map[TimeInterval]RunID{}),
+						newLocationWResource(2, 0, 2, map[TimeInterval]RunID{}),
+					},
+				},
+			)
+			require.NoError(t, errCr)
+
+			response, errSuggest := network.SuggestLocation(
+				&ParamsSuggestLocation{
+					ParamsCanRun:   paramsCanRun,
+					HomeLocationID: 1,
+				},
+			)
+			require.NoError(t, errSuggest)
+			require.True(t, response.Home.CanRunInInterval)
+			require.True(t, response.ShouldMove)
+			require.EqualValues(t,
+				2,
+				response.GetSuggested().Location.ID,
+			)
+		},
+	)
+
+	t.Run(
+		"4. Unknown home location",
+		func(t *testing.T) {
+			network, errCr := NewNetwork(
+				&ParamsNewNetwork{
+					ID:   1,
+					Name: t.Name(),
+
+					Locations: []*Location{
+						newLocationWResource(1, 0, 1, map[TimeInterval]RunID{}),
+					},
+				},
+			)
+			require.NoError(t, errCr)
+
+			response, errSuggest := network.SuggestLocation(
+				&ParamsSuggestLocation{
+					ParamsCanRun:   paramsCanRun,
+					HomeLocationID: 7,
+				},
+			)
+			require.Error(t, errSuggest)
+			require.Nil(t, response)
+		},
+	)
+
+	t.Run(
+		"5. Locations in different currencies",
+		func(t *testing.T) {
+			locationEUR := newLocationWResource(1, 0, 1, map[TimeInterval]RunID{})
+			locationEUR.Currency = "EUR"
+
+			locationUSD := newLocationWResource(2, 0, 1, map[TimeInterval]RunID{})
+			locationUSD.Currency = "USD"
+
+			_, errCr := NewNetwork(
+				&ParamsNewNetwork{
+					ID:   1,
+					Name: t.Name(),
+
+					Locations: []*Location{locationEUR, locationUSD},
+				},
+			)
+			require.Error(t, errCr)
+		},
+	)
+
+	t.Run(
+		"6. Bookings made meanwhile, expired holds released",
+		func(t *testing.T) {
+			clock := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+			home := newLocationWResource(1, 0, 1, map[TimeInterval]RunID{})
+			home.Now = func() time.Time {
+				return clock
+			}
+
+			alternative := newLocationWResource(2, 0, 2, map[TimeInterval]RunID{})
+
+			network, errCr := NewNetwork(
+				&ParamsNewNetwork{
+					ID:   1,
+					Name: t.Name(),
+
+					Locations: []*Location{home, alternative},
+				},
+			)
+			require.NoError(t, errCr)
+
+			options, errGet := home.GetSchedulingOptions(&paramsCanRun)
+			require.NoError(t, errGet)
+
+			token, errHold := home.Hold(options[0], time.Minute)
+			require.NoError(t, errHold)
+
+			clock = clock.Add(time.Minute)
+
+			_, errSuggestExpired := network.SuggestLocation(
+				&ParamsSuggestLocation{
+					ParamsCanRun:   paramsCanRun,
+					HomeLocationID: 1,
+				},
+			)
+			require.NoError(t, errSuggestExpired)
+			require.False(t,
+				home.Resources[0].schedule.hasRun(token.runID()),
+				"expired hold released",
+			)
+
+			var wg sync.WaitGroup
+
+			for routine := range 4 {
+				wg.Add(1)
+
+				go func() {
+					defer wg.Done()
+
+					location := ternary(routine%2 == 0, home, alternative)
+
+					for ix := range 10 {
+						start := now + int64(ix)*oneHour
+						run := taskRun
+						run.ID = int64(routine*100 + ix + 1)
+
+						_, _ = location.CanSchedule(
+							&ParamsCanRun{
+								TimeInterval: TimeInterval{
+									TimeStart: start,
+									TimeEnd:   start + oneHour,
+								},
+								TaskRun: &run,
+							},
+						)
+					}
+				}()
+			}
+
+			for range 20 {
+				_, errSuggest := network.SuggestLocation(
+					&ParamsSuggestLocation{
+						ParamsCanRun:   paramsCanRun,
+						HomeLocationID: 1,
+					},
+				)
+				require.NoError(t, errSuggest)
+			}
+
+			wg.Wait()
+		},
+	)
+}
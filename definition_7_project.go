@@ -0,0 +1,494 @@
+package scheduler
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	goerrors "github.com/TudorHulban/go-errors"
+)
+
+type LinkType uint8
+
+const (
+	FinishToStart  LinkType = iota + 1 // successor starts after predecessor finishes
+	StartToStart                       // successor starts after predecessor starts
+	FinishToFinish                     // successor finishes after predecessor finishes
+)
+
+func (t LinkType) String() string {
+	switch t {
+	case FinishToStart:
+		return "FS"
+
+	case StartToStart:
+		return "SS"
+
+	case FinishToFinish:
+		return "FF"
+	}
+
+	return fmt.Sprintf("LinkType(%d)", t)
+}
+
+// Link is a precedence constraint between two runs of a project.
+// Lag is in seconds added to the constraint, negative for lead.
+type Link struct {
+	PredecessorID int64
+	SuccessorID   int64
+	Type          LinkType
+	Lag           int64
+}
+
+// Project is a graph of runs with precedence links, without cycles.
+type Project struct {
+	Name string
+
+	runs  map[int64]*Run
+	order []int64 // run IDs as added
+	links []Link
+}
+
+func NewProject(name string) *Project {
+	return &Project{
+		Name: name,
+
+		runs: make(map[int64]*Run),
+	}
+}
+
+func (p *Project) AddRun(run *Run) error {
+	if run == nil {
+		return goerrors.ErrValidation{
+			Caller: "AddRun - Project",
+			Issue: goerrors.ErrNilInput{
+				InputName: "run",
+			},
+		}
+	}
+
+	if run.ID <= 0 {
+		return goerrors.ErrValidation{
+			Caller: "AddRun - Project",
+			Issue: goerrors.ErrNegativeInput{
+				InputName: "ID",
+			},
+		}
+	}
+
+	if _, exists := p.runs[run.ID]; exists {
+		return goerrors.ErrValidation{
+			Caller: "AddRun - Project",
+			Issue: goerrors.ErrInvalidInput{
+				InputName:  "ID",
+				InputValue: run.ID,
+				Issue:      fmt.Errorf("duplicate run ID %d", run.ID),
+			},
+		}
+	}
+
+	p.runs[run.ID] = run
+	p.order = append(p.order, run.ID)
+
+	return nil
+}
+
+// AddLink rejects links to unknown runs and links that would create a cycle.
+func (p *Project) AddLink(link Link) error {
+	for _, id := range []int64{link.PredecessorID, link.SuccessorID} {
+		if _, exists := p.runs[id]; !exists {
+			return goerrors.ErrEntryNotFound{
+				Key: id,
+			}
+		}
+	}
+
+	if link.Type < FinishToStart || link.Type > FinishToFinish {
+		return goerrors.ErrValidation{
+			Caller: "AddLink",
+			Issue: goerrors.ErrInvalidInput{
+				InputName:  "Type",
+				InputValue: link.Type,
+			},
+		}
+	}
+
+	if link.PredecessorID == link.SuccessorID || p.isReachable(link.SuccessorID, link.PredecessorID) {
+		return goerrors.ErrValidation{
+			Caller: "AddLink",
+			Issue: goerrors.ErrInvalidInput{
+				InputName:  "Link",
+				InputValue: link,
+				Issue:      errors.New("link creates a cycle"),
+			},
+		}
+	}
+
+	p.links = append(p.links, link)
+
+	return nil
+}
+
+func (p *Project) isReachable(fromID, toID int64) bool {
+	visited := make(map[int64]bool)
+	toVisit := []int64{fromID}
+
+	for len(toVisit) > 0 {
+		current := toVisit[len(toVisit)-1]
+		toVisit = toVisit[:len(toVisit)-1]
+
+		if current == toID {
+			return true
+		}
+
+		if visited[current] {
+			continue
+		}
+
+		visited[current] = true
+
+		for _, link := range p.links {
+			if link.PredecessorID == current {
+				toVisit = append(toVisit, link.SuccessorID)
+			}
+		}
+	}
+
+	return false
+}
+
+// getTopologicalOrder returns the run IDs with predecessors first, ties as added.
+func (p *Project) getTopologicalOrder() []int64 {
+	predecessors := make(map[int64]int, len(p.runs))
+
+	for _, link := range p.links {
+		predecessors[link.SuccessorID]++
+	}
+
+	result := make([]int64, 0, len(p.runs))
+	placed := make(map[int64]bool, len(p.runs))
+
+	for len(result) < len(p.order) {
+		for _, id := range p.order {
+			if placed[id] || predecessors[id] > 0 {
+				continue
+			}
+
+			placed[id] = true
+			result = append(result, id)
+
+			for _, link := range p.links {
+				if link.PredecessorID == id {
+					predecessors[link.SuccessorID]--
+				}
+			}
+
+			break
+		}
+	}
+
+	return result
+}
+
+type ParamsScheduleProject struct {
+	TimeInterval // project window, runs start at or after TimeStart and finish by TimeEnd
+}
+
+// ProjectRunResult times are expressed like the requested interval.
+// Latest start only accounts for precedence, not for resource availability.
+type ProjectRunResult struct {
+	TimeInterval // as booked
+
+	RunID         RunID
+	EarliestStart int64 // as booked, earliest given precedence and resources
+	LatestStart   int64 // without delaying the project finish
+	Slack         int64 // seconds
+	Cost          Money
+	IsCritical    bool
+}
+
+func (r *ProjectRunResult) String() string {
+	return fmt.Sprintf(
+		"ProjectRun{RunID: %d, [%d-%d], LatestStart: %d, Slack: %d, Cost: %s, IsCritical: %t}",
+
+		r.RunID,
+		r.TimeStart,
+		r.TimeEnd,
+		r.LatestStart,
+		r.Slack,
+		r.Cost,
+		r.IsCritical,
+	)
+}
+
+type ResponseScheduleProject struct {
+	Runs         []*ProjectRunResult // topological order
+	CriticalPath []RunID             // zero slack runs by start
+
+	TimeInterval // project start and finish
+
+	Cost         Money
+	WasScheduled bool
+	Conflict     string // why the project was not scheduled, nothing is booked then
+}
+
+func (r *ResponseScheduleProject) String() string {
+	var sb strings.Builder
+
+	sb.WriteString("ResponseScheduleProject{\n")
+
+	for _, run := range r.Runs {
+		sb.WriteString("\t" + run.String() + ",\n")
+	}
+
+	sb.WriteString(fmt.Sprintf("\tCriticalPath: %v,\n", r.CriticalPath))
+	sb.WriteString(fmt.Sprintf("\t[%d-%d], Cost: %s, WasScheduled: %t", r.TimeStart, r.TimeEnd, r.Cost, r.WasScheduled))
+
+	if len(r.Conflict) > 0 {
+		sb.WriteString(fmt.Sprintf(", Conflict: %q", r.Conflict))
+	}
+
+	sb.WriteString("\n}")
+
+	return sb.String()
+}
+
+// getEarliestStartUTC returns the earliest start allowed by the links to the already placed predecessors.
+func (p *Project) getEarliestStartUTC(runID int64, placed map[int64]*TimeInterval, minimum int64) int64 {
+	result := minimum
+
+	for _, link := range p.links {
+		if link.SuccessorID != runID {
+			continue
+		}
+
+		predecessor := placed[link.PredecessorID]
+
+		switch link.Type {
+		case FinishToStart:
+			result = max(result, predecessor.TimeEnd+link.Lag)
+
+		case StartToStart:
+			result = max(result, predecessor.TimeStart+link.Lag)
+
+		case FinishToFinish:
+			result = max(result, predecessor.TimeEnd+link.Lag-p.runs[runID].EstimatedDuration)
+		}
+	}
+
+	return result
+}
+
+// getLatestStartsUTC is the backward pass from the project finish.
+func (p *Project) getLatestStartsUTC(order []int64, finish int64) map[int64]int64 {
+	result := make(map[int64]int64, len(order))
+
+	for _, id := range slices.Backward(order) {
+		duration := p.runs[id].EstimatedDuration
+		latest := finish - duration
+
+		for _, link := range p.links {
+			if link.PredecessorID != id {
+				continue
+			}
+
+			successorLatest := result[link.SuccessorID]
+
+			switch link.Type {
+			case FinishToStart:
+				latest = min(latest, successorLatest-link.Lag-duration)
+
+			case StartToStart:
+				latest = min(latest, successorLatest-link.Lag)
+
+			case FinishToFinish:
+				latest = min(latest, successorLatest+p.runs[link.SuccessorID].EstimatedDuration-link.Lag-duration)
+			}
+		}
+
+		result[id] = latest
+	}
+
+	return result
+}
+
+// ScheduleProject books the project runs in the location in precedence order, each at its earliest
+// start allowed by links and resources. All or nothing, on conflict already booked runs are cancelled.
+func (loc *Engine) ScheduleProject(project *Project, params *ParamsScheduleProject) (*ResponseScheduleProject, error) {
+	if project == nil || len(project.runs) == 0 {
+		return nil,
+			goerrors.ErrValidation{
+				Caller: "ScheduleProject",
+				Issue: goerrors.ErrNilInput{
+					InputName: "project",
+				},
+			}
+	}
+
+	loc.mu.Lock()
+	defer loc.mu.Unlock()
+
+	loc.expireHolds()
+
+	order := project.getTopologicalOrder()
+
+	placed := make(map[int64]*TimeInterval, len(order)) // UTC
+	booked := make([]RunID, 0, len(order))
+	costs := make(map[int64]Money, len(order))
+
+	cancelBooked := func() {
+		for _, runID := range booked {
+			_ = loc.cancelRun(runID)
+		}
+	}
+
+	var result ResponseScheduleProject
+
+	for _, id := range order {
+		run := project.runs[id]
+
+		earliestUTC := project.getEarliestStartUTC(id, placed, params.GetUTCTimeStart())
+
+		option, conflict, errPlace := loc.placeRun(run, &params.TimeInterval, earliestUTC, Earliest{})
+		if errPlace != nil {
+			cancelBooked()
+
+			return nil,
+				errPlace
+		}
+
+		if len(conflict) > 0 {
+			cancelBooked()
+
+			result.Conflict = fmt.Sprintf("run %d: %s", id, conflict)
+
+			return &result,
+				nil
+		}
+
+		booked = append(booked, RunID(id))
+		costs[id] = option.Cost
+
+		startUTC := params.TimeInterval.toUTCTimestamp(option.WhenCanStart)
+
+		placed[id] = &TimeInterval{
+			TimeStart: startUTC,
+			TimeEnd:   startUTC + run.EstimatedDuration,
+		}
+	}
+
+	startUTC, finishUTC := placed[order[0]].TimeStart, placed[order[0]].TimeEnd
+
+	for _, interval := range placed {
+		startUTC = min(startUTC, interval.TimeStart)
+		finishUTC = max(finishUTC, interval.TimeEnd)
+	}
+
+	latestStarts := project.getLatestStartsUTC(order, finishUTC)
+
+	result.Runs = make([]*ProjectRunResult, len(order))
+
+	for ix, id := range order {
+		interval := placed[id]
+
+		result.Runs[ix] = &ProjectRunResult{
+			TimeInterval:  params.fromUTC(interval.TimeStart, interval.TimeEnd),
+			RunID:         RunID(id),
+			EarliestStart: params.fromUTCTimestamp(interval.TimeStart),
+			LatestStart:   params.fromUTCTimestamp(latestStarts[id]),
+			Slack:         latestStarts[id] - interval.TimeStart,
+			Cost:          costs[id],
+			IsCritical:    latestStarts[id] == interval.TimeStart,
+		}
+
+		result.Cost = result.Cost.add(costs[id])
+	}
+
+	critical := slices.Clone(result.Runs)
+
+	slices.SortStableFunc(
+		critical,
+		func(a, b *ProjectRunResult) int {
+			return cmp.Compare(a.EarliestStart, b.EarliestStart)
+		},
+	)
+
+	for _, run := range critical {
+		if run.IsCritical {
+			result.CriticalPath = append(result.CriticalPath, run.RunID)
+		}
+	}
+
+	result.TimeInterval = params.fromUTC(startUTC, finishUTC)
+	result.WasScheduled = true
+
+	return &result,
+		nil
+}
+
+// placeRun books the run at the start the strategy selects in the window, not before earliestUTC.
+// Returns the reason it could not be booked as conflict.
+func (loc *Engine) placeRun(run *Run, window *TimeInterval, earliestUTC int64, strategy SelectionStrategy) (*LocationOption, string, error) {
+	search := window.fromUTC(earliestUTC, window.GetUTCTimeEnd())
+
+	if search.Duration() < run.EstimatedDuration {
+		return nil,
+			"window too short",
+			nil
+	}
+
+	option, errEvaluate := loc.evaluate(
+		&ParamsCanRun{
+			TimeInterval: search,
+			TaskRun:      run,
+
+			strategy: strategy,
+		},
+	)
+	if errEvaluate != nil {
+		return nil,
+			"",
+			errEvaluate
+	}
+
+	if !option.CanRunInInterval {
+		return nil,
+			"no resources available in window",
+			nil
+	}
+
+	paramsAtStart := ParamsCanRun{
+		TimeInterval: window.fromUTC(
+			window.toUTCTimestamp(option.WhenCanStart),
+			window.GetUTCTimeEnd(),
+		),
+		TaskRun: run,
+
+		strategy: strategy,
+	}
+
+	optionAtStart, errEvaluateAtStart := loc.evaluate(&paramsAtStart)
+	if errEvaluateAtStart != nil {
+		return nil,
+			"",
+			errEvaluateAtStart
+	}
+
+	if !optionAtStart.CanRunAtStart {
+		return nil,
+			fmt.Sprintf("resources busy, earliest start %d", optionAtStart.WhenCanStart),
+			nil
+	}
+
+	if errBook := loc.bookAtStart(optionAtStart.possibilities, &paramsAtStart, optionAtStart.SelectedResources); errBook != nil {
+		return nil,
+			errBook.Error(),
+			nil
+	}
+
+	return optionAtStart,
+		"",
+		nil
+}
@@ -0,0 +1,198 @@
+package scheduler
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleProject(t *testing.T) {
+	newLocation := func(t *testing.T) *Location {
+		return newTestLocation(
+			t,
+
+			newTestResource(1, 1, 1, map[TimeInterval]RunID{
+				{TimeStart: now + oneHour, TimeEnd: now + oneHour + halfHour}: Maintenance,
+			}),
+			newTestResource(2, 2, 2, nil),
+			newTestResource(3, 3, 3, nil),
+		)
+	}
+
+	project := NewProject("Batch")
+
+	for _, run := range []*Run{
+		newTestRun(1, oneHour, 2),   // prep
+		newTestRun(2, 2*oneHour, 1), // processing
+		newTestRun(3, oneHour, 2),   // cleanup
+		newTestRun(4, oneHour, 3),   // labels
+	} {
+		require.NoError(t,
+			project.AddRun(run),
+		)
+	}
+
+	for _, link := range []Link{
+		{PredecessorID: 2, SuccessorID: 3, Type: FinishToStart, Lag: halfHour},
+		{PredecessorID: 1, SuccessorID: 2, Type: FinishToStart},
+		{PredecessorID: 2, SuccessorID: 4, Type: StartToStart, Lag: halfHour},
+	} {
+		require.NoError(t,
+			project.AddLink(link),
+		)
+	}
+
+	t.Run(
+		"1. Invalid links",
+		func(t *testing.T) {
+			require.Error(t,
+				project.AddLink(Link{PredecessorID: 3, SuccessorID: 1, Type: FinishToStart}),
+				"cycle",
+			)
+			require.Error(t,
+				project.AddLink(Link{PredecessorID: 1, SuccessorID: 1, Type: StartToStart}),
+				"self link",
+			)
+			require.Error(t,
+				project.AddLink(Link{PredecessorID: 1, SuccessorID: 5, Type: FinishToStart}),
+				"unknown run",
+			)
+		},
+	)
+
+	t.Run(
+		"2. Schedule with critical path",
+		func(t *testing.T) {
+			location := newLocation(t)
+
+			response, errSchedule := location.ScheduleProject(
+				project,
+				&ParamsScheduleProject{
+					TimeInterval: TimeInterval{
+						TimeStart: now,
+						TimeEnd:   now + oneDay,
+					},
+				},
+			)
+			require.NoError(t, errSchedule)
+			require.True(t, response.WasScheduled)
+
+			fmt.Println(
+				response,
+			)
+
+			expected := map[RunID]struct {
+				start int64
+				slack int64
+			}{
+				1: {start: now, slack: halfHour}, // processing waits for maintenance
+				2: {start: now + oneHour + halfHour},
+				3: {start: now + 4*oneHour},
+				4: {start: now + 2*oneHour, slack: 2 * oneHour},
+			}
+
+			for _, run := range response.Runs {
+				require.Equal(t,
+					expected[run.RunID].start,
+					run.TimeStart,
+					run.RunID,
+				)
+				require.Equal(t,
+					expected[run.RunID].slack,
+					run.Slack,
+					run.RunID,
+				)
+			}
+
+			require.Equal(t,
+				[]RunID{2, 3},
+				response.CriticalPath,
+			)
+			require.EqualValues(t,
+				now+5*oneHour,
+				response.TimeEnd,
+			)
+			require.EqualValues(t,
+				8,
+				response.Cost.Amount,
+			)
+
+			run, errGet := location.Resources[0].GetRun(now+2*oneHour, 0)
+			require.NoError(t, errGet)
+			require.EqualValues(t,
+				2,
+				run.ID,
+			)
+		},
+	)
+
+	t.Run(
+		"3. Window too short books nothing",
+		func(t *testing.T) {
+			location := newLocation(t)
+
+			response, errSchedule := location.ScheduleProject(
+				project,
+				&ParamsScheduleProject{
+					TimeInterval: TimeInterval{
+						TimeStart: now,
+						TimeEnd:   now + 4*oneHour,
+					},
+				},
+			)
+			require.NoError(t, errSchedule)
+			require.False(t, response.WasScheduled)
+			require.NotEmpty(t, response.Conflict)
+
+			require.Equal(t,
+				1,
+				location.Resources[0].schedule.Len(),
+				"only maintenance",
+			)
+			require.Zero(t,
+				location.Resources[1].schedule.Len(),
+			)
+		},
+	)
+
+	t.Run(
+		"4. Earliest start over cheaper resources",
+		func(t *testing.T) {
+			location := newTestLocation(
+				t,
+
+				newTestResource(1, 1, 1, map[TimeInterval]RunID{
+					{TimeStart: now, TimeEnd: now + 3*oneHour}: Maintenance,
+				}),
+				newTestResource(2, 1, 100, nil),
+			)
+
+			single := NewProject("Single")
+
+			require.NoError(t,
+				single.AddRun(newTestRun(1, oneHour, 1)),
+			)
+
+			response, errSchedule := location.ScheduleProject(
+				single,
+				&ParamsScheduleProject{
+					TimeInterval: TimeInterval{
+						TimeStart: now,
+						TimeEnd:   now + oneDay,
+					},
+				},
+			)
+			require.NoError(t, errSchedule)
+			require.True(t, response.WasScheduled)
+			require.Equal(t,
+				now,
+				response.Runs[0].EarliestStart,
+			)
+			require.EqualValues(t,
+				100,
+				response.Cost.Amount,
+			)
+		},
+	)
+}
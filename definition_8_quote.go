@@ -0,0 +1,515 @@
+package scheduler
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"text/tabwriter"
+
+	goerrors "github.com/TudorHulban/go-errors"
+)
+
+// QuoteID identifies a quote by its content, quoting the same terms twice gives the same ID.
+type QuoteID string
+
+// Discount reduces the subtotal of the lines of a resource type.
+type Discount struct {
+	Name         string
+	ResourceType uint8 // zero applies to all resources
+	BasisPoints  int64 // of the line subtotal, 100 is one percent
+}
+
+// Tax is charged on the line subtotal after discounts.
+type Tax struct {
+	Name        string
+	BasisPoints int64 // 100 is one percent
+}
+
+// QuoteTerms are the discounts and taxes of a quote. Each applies to the line alone,
+// discounts do not compound and taxes are not charged on taxes.
+type QuoteTerms struct {
+	Discounts []Discount
+	Taxes     []Tax
+}
+
+func (t *QuoteTerms) IsValid() error {
+	if t == nil {
+		return nil
+	}
+
+	for _, discount := range t.Discounts {
+		if discount.BasisPoints < 0 || discount.BasisPoints > 10000 {
+			return goerrors.ErrInvalidInput{
+				Caller:     "IsValid - QuoteTerms",
+				InputName:  "Discount " + discount.Name,
+				InputValue: discount.BasisPoints,
+				Issue:      errors.New("basis points between 0 and 10000"),
+			}
+		}
+	}
+
+	for _, tax := range t.Taxes {
+		if tax.BasisPoints < 0 {
+			return goerrors.ErrValidation{
+				Caller: "IsValid - QuoteTerms",
+				Issue: goerrors.ErrNegativeInput{
+					InputName: "Tax " + tax.Name,
+				},
+			}
+		}
+	}
+
+	return nil
+}
+
+// QuoteAdjustment is a discount, negative, or a tax on a line.
+type QuoteAdjustment struct {
+	Name        string `json:"name"`
+	BasisPoints int64  `json:"basisPoints"`
+	Amount      Money  `json:"amount"`
+}
+
+// QuoteLine is the price of the run on one resource.
+type QuoteLine struct {
+	ResourceID   int    `json:"resourceID"`
+	ResourceName string `json:"resourceName"`
+	ResourceType uint8  `json:"resourceType"`
+
+	LoadUnit   uint8   `json:"loadUnit"`
+	Quantity   float32 `json:"quantity"` // load, in the load unit
+	Rate       Money   `json:"rate"`     // per load unit
+	LoadCharge Money   `json:"loadCharge"`
+
+	Seconds    int64 `json:"seconds"`
+	HourlyRate Money `json:"hourlyRate"`
+	TimeCharge Money `json:"timeCharge"`
+
+	Factor float32 `json:"factor"` // pricing calendar, applied exactly to load and time charges, shown rounded
+	Fee    Money   `json:"fee"`
+
+	Subtotal  Money              `json:"subtotal"` // charges and fee
+	Discounts []*QuoteAdjustment `json:"discounts,omitempty"`
+	Taxes     []*QuoteAdjustment `json:"taxes,omitempty"`
+	Total     Money              `json:"total"`
+}
+
+// Quote is the itemized price of a run on the resources of an option, a document:
+// it keeps what was quoted when prices change later.
+type Quote struct {
+	ID QuoteID `json:"id"`
+
+	LocationID    int64    `json:"locationID"`
+	RunID         int64    `json:"runID"`
+	TimeStart     int64    `json:"timeStart"` // priced, location time
+	TimeEnd       int64    `json:"timeEnd"`
+	SecondsOffset int64    `json:"secondsOffset"`
+	Currency      Currency `json:"currency"`
+
+	Lines []*QuoteLine `json:"lines"`
+
+	Subtotal  Money `json:"subtotal"`
+	Discounts Money `json:"discounts"` // negative
+	Taxes     Money `json:"taxes"`
+	Total     Money `json:"total"`
+}
+
+type paramsNewQuote struct {
+	LocationID int64
+	Run        *Run
+	Resources  []*ResourceScheduled
+	Interval   TimeInterval
+	Pricing    *runPricing // of the location, nil prices without calendars
+	Terms      *QuoteTerms // nil without discounts and taxes
+}
+
+func newQuote(params *paramsNewQuote) (*Quote, error) {
+	if params.Run == nil {
+		return nil,
+			goerrors.ErrValidation{
+				Caller: "Quote",
+				Issue: goerrors.ErrNilInput{
+					InputName: "Run",
+				},
+			}
+	}
+
+	if len(params.Resources) == 0 {
+		return nil,
+			goerrors.ErrValidation{
+				Caller: "Quote",
+				Issue:  errors.New("no resources selected, nothing to quote"),
+			}
+	}
+
+	if errValidation := params.Terms.IsValid(); errValidation != nil {
+		return nil,
+			errValidation
+	}
+
+	terms := ternary(params.Terms != nil, params.Terms, &QuoteTerms{})
+	pricing := params.Pricing.at(params.Run, &params.Interval)
+
+	cost, errCost := pricing.getCost(params.Resources)
+	if errCost != nil {
+		return nil,
+			errCost
+	}
+
+	result := Quote{
+		LocationID:    params.LocationID,
+		RunID:         params.Run.ID,
+		TimeStart:     params.Interval.TimeStart,
+		TimeEnd:       params.Interval.TimeEnd,
+		SecondsOffset: params.Interval.SecondsOffset,
+		Currency:      cost.Total.Currency,
+
+		Lines: make([]*QuoteLine, len(cost.Lines)),
+	}
+
+	for ix, costLine := range cost.Lines {
+		factor, _ := costLine.Factor.Float32()
+
+		line := QuoteLine{
+			ResourceID:   costLine.Resource.ID,
+			ResourceName: costLine.Resource.Name,
+			ResourceType: costLine.Resource.ResourceType,
+
+			LoadUnit:   costLine.LoadUnit,
+			Quantity:   costLine.Load,
+			Rate:       costLine.CostPerUnit,
+			LoadCharge: costLine.CostLoad,
+
+			Seconds:    costLine.Seconds,
+			HourlyRate: costLine.CostPerHour,
+			TimeCharge: costLine.CostTime,
+
+			Factor: factor,
+			Fee:    costLine.CostPerRun,
+
+			Subtotal: costLine.Cost,
+		}
+
+		discounted := line.Subtotal
+
+		for _, discount := range terms.Discounts {
+			if discount.ResourceType != 0 && discount.ResourceType != line.ResourceType {
+				continue
+			}
+
+			// rounded as a charge, then taken off
+			amount := line.Subtotal.scale(big.NewRat(int64(discount.BasisPoints), 10000), pricing.rounding)
+			amount.Amount = -amount.Amount
+
+			adjustment := QuoteAdjustment{
+				Name:        discount.Name,
+				BasisPoints: discount.BasisPoints,
+				Amount:      amount,
+			}
+
+			line.Discounts = append(line.Discounts, &adjustment)
+			discounted = discounted.add(adjustment.Amount)
+			result.Discounts = result.Discounts.add(adjustment.Amount)
+		}
+
+		line.Total = discounted
+
+		for _, tax := range terms.Taxes {
+			adjustment := QuoteAdjustment{
+				Name:        tax.Name,
+				BasisPoints: tax.BasisPoints,
+				Amount:      discounted.scale(big.NewRat(int64(tax.BasisPoints), 10000), pricing.rounding),
+			}
+
+			line.Taxes = append(line.Taxes, &adjustment)
+			line.Total = line.Total.add(adjustment.Amount)
+			result.Taxes = result.Taxes.add(adjustment.Amount)
+		}
+
+		result.Lines[ix] = &line
+		result.Subtotal = result.Subtotal.add(line.Subtotal)
+		result.Total = result.Total.add(line.Total)
+	}
+
+	result.Subtotal = result.Subtotal.in(result.Currency)
+	result.Discounts = result.Discounts.in(result.Currency)
+	result.Taxes = result.Taxes.in(result.Currency)
+	result.Total = result.Total.in(result.Currency)
+
+	encoded, errEncode := json.Marshal(result)
+	if errEncode != nil {
+		return nil,
+			errEncode
+	}
+
+	result.ID = QuoteID(fmt.Sprintf("Q-%x", sha256.Sum256(encoded))[:18])
+
+	return &result,
+		nil
+}
+
+// JSON returns the quote as indented JSON, amounts as exact decimals.
+func (q *Quote) JSON() ([]byte, error) {
+	return json.MarshalIndent(q, "", "  ")
+}
+
+// String renders the quote as plain text, a block per resource.
+func (q *Quote) String() string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("Quote %s\n", q.ID))
+	sb.WriteString(
+		fmt.Sprintf(
+			"Location %d, Run %d, [%d-%d] Offset %.1fh\n\n",
+
+			q.LocationID,
+			q.RunID,
+			q.TimeStart,
+			q.TimeEnd,
+			float64(q.SecondsOffset)/3600,
+		),
+	)
+
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+
+	writeRow := func(label, detail string, amount Money) {
+		fmt.Fprintf(w, "%s\t%s\t%14s\n", label, detail, amount.String())
+	}
+
+	for _, line := range q.Lines {
+		fmt.Fprintf(w, "Resource %d %q, type %d\n", line.ResourceID, line.ResourceName, line.ResourceType)
+
+		writeRow(
+			"  load",
+			fmt.Sprintf("%g x unit %d at %s", line.Quantity, line.LoadUnit, line.Rate),
+			line.LoadCharge,
+		)
+		writeRow(
+			"  time",
+			fmt.Sprintf("%ds at %s/h", line.Seconds, line.HourlyRate),
+			line.TimeCharge,
+		)
+
+		if line.Factor != 1 {
+			fmt.Fprintf(w, "  pricing factor\t%.4g\t\n", line.Factor)
+		}
+
+		writeRow("  fee", "", line.Fee)
+		writeRow("  subtotal", "", line.Subtotal)
+
+		for _, discount := range line.Discounts {
+			writeRow("  discount", fmt.Sprintf("%s %.2f%%", discount.Name, float64(discount.BasisPoints)/100), discount.Amount)
+		}
+
+		for _, tax := range line.Taxes {
+			writeRow("  tax", fmt.Sprintf("%s %.2f%%", tax.Name, float64(tax.BasisPoints)/100), tax.Amount)
+		}
+
+		writeRow("  total", "", line.Total)
+	}
+
+	writeRow("Subtotal", "", q.Subtotal)
+	writeRow("Discounts", "", q.Discounts)
+	writeRow("Taxes", "", q.Taxes)
+	writeRow("Total", "", q.Total)
+
+	w.Flush()
+
+	return sb.String()
+}
+
+// quoteBook keeps the quotes issued, encoded, so they are reproduced as quoted.
+// Quotes are not evicted, the book grows with every distinct quote for the life of its owner.
+type quoteBook map[QuoteID][]byte
+
+// add should be called under the lock of the owner.
+func (b *quoteBook) add(quote *Quote) error {
+	encoded, errEncode := json.Marshal(quote)
+	if errEncode != nil {
+		return errEncode
+	}
+
+	if *b == nil {
+		*b = make(quoteBook)
+	}
+
+	(*b)[quote.ID] = encoded
+
+	return nil
+}
+
+// get should be called under the lock of the owner.
+func (b quoteBook) get(id QuoteID) (*Quote, error) {
+	encoded, exists := b[id]
+	if !exists {
+		return nil,
+			goerrors.ErrEntryNotFound{
+				Key: id,
+			}
+	}
+
+	var result Quote
+
+	if errDecode := json.Unmarshal(encoded, &result); errDecode != nil {
+		return nil,
+			errDecode
+	}
+
+	return &result,
+		nil
+}
+
+// issueQuote prices and keeps the quote.
+func (loc *Engine) issueQuote(params *paramsNewQuote) (*Quote, error) {
+	params.LocationID = loc.ID
+	params.Pricing = loc.getPricing(nil, nil)
+
+	result, errQuote := newQuote(params)
+	if errQuote != nil {
+		return nil,
+			errQuote
+	}
+
+	loc.mu.Lock()
+	defer loc.mu.Unlock()
+
+	if errAdd := loc.quotes.add(result); errAdd != nil {
+		return nil,
+			errAdd
+	}
+
+	return result,
+		nil
+}
+
+// QuoteOption itemizes the option of the run, obtained with GetSchedulingOptions, priced as the option.
+func (loc *Engine) QuoteOption(run *Run, option *SchedulingOption, terms *QuoteTerms) (*Quote, error) {
+	if option == nil || run == nil {
+		return nil,
+			goerrors.ErrValidation{
+				Caller: "QuoteOption",
+				Issue: goerrors.ErrNilInput{
+					InputName: "option or run",
+				},
+			}
+	}
+
+	if option.runID != RunID(run.ID) {
+		return nil,
+			goerrors.ErrInvalidInput{
+				Caller:     "QuoteOption",
+				InputName:  "run",
+				InputValue: run.ID,
+				Issue:      errors.New("option not obtained for the run"),
+			}
+	}
+
+	return loc.issueQuote(
+		&paramsNewQuote{
+			Run:       run,
+			Resources: option.SelectedResources,
+			Interval:  option.interval,
+			Terms:     terms,
+		},
+	)
+}
+
+// QuoteResponse itemizes the response of CanSchedule for the run, priced as the response.
+func (loc *Engine) QuoteResponse(run *Run, response *ResponseCanRun, terms *QuoteTerms) (*Quote, error) {
+	if response == nil || run == nil {
+		return nil,
+			goerrors.ErrValidation{
+				Caller: "QuoteResponse",
+				Issue: goerrors.ErrNilInput{
+					InputName: "response or run",
+				},
+			}
+	}
+
+	if len(response.resources) > 0 && response.runID != RunID(run.ID) {
+		return nil,
+			goerrors.ErrInvalidInput{
+				Caller:     "QuoteResponse",
+				InputName:  "run",
+				InputValue: run.ID,
+				Issue:      errors.New("response not obtained for the run"),
+			}
+	}
+
+	return loc.issueQuote(
+		&paramsNewQuote{
+			Run:       run,
+			Resources: response.resources,
+			Interval:  response.interval,
+			Terms:     terms,
+		},
+	)
+}
+
+// GetQuote returns the quote issued by the location, as it was quoted.
+// The location keeps all quotes it issued in memory, long lived callers issuing many
+// should store Quote.JSON themselves.
+func (loc *Engine) GetQuote(id QuoteID) (*Quote, error) {
+	loc.mu.Lock()
+	defer loc.mu.Unlock()
+
+	return loc.quotes.get(id)
+}
+
+// QuoteOption itemizes the option of the run, priced as the option.
+func (loc *Loco) QuoteOption(run *Run, option *OptionSchedule, terms *QuoteTerms) (*Quote, error) {
+	if option == nil || run == nil {
+		return nil,
+			goerrors.ErrValidation{
+				Caller: "QuoteOption",
+				Issue: goerrors.ErrNilInput{
+					InputName: "option or run",
+				},
+			}
+	}
+
+	params := paramsNewQuote{
+		LocationID: loc.ID,
+		Run:        run,
+		Resources:  option.Resources.getResources(),
+		Pricing:    option.pricing,
+		Terms:      terms,
+	}
+
+	if option.interval != nil {
+		params.Interval = TimeInterval{
+			TimeStart:     option.interval.TimeStart,
+			TimeEnd:       option.interval.TimeStart + run.EstimatedDuration,
+			SecondsOffset: option.interval.SecondsOffset,
+			TimeZone:      option.interval.TimeZone,
+		}
+	}
+
+	result, errQuote := newQuote(&params)
+	if errQuote != nil {
+		return nil,
+			errQuote
+	}
+
+	loc.mu.Lock()
+	defer loc.mu.Unlock()
+
+	if errAdd := loc.quotes.add(result); errAdd != nil {
+		return nil,
+			errAdd
+	}
+
+	return result,
+		nil
+}
+
+// GetQuote returns the quote issued by the loco, as it was quoted.
+// Kept in memory as for Engine.GetQuote.
+func (loc *Loco) GetQuote(id QuoteID) (*Quote, error) {
+	loc.mu.Lock()
+	defer loc.mu.Unlock()
+
+	return loc.quotes.get(id)
+}
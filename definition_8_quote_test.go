@@ -0,0 +1,370 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuote(t *testing.T) {
+	newResource := func(id int, resourceType uint8) *ResourceScheduled {
+		result := newTestResource(id, resourceType, 250, nil)
+		result.CostPerHour = Money{Amount: 1200}
+		result.CostPerRun = Money{Amount: 333}
+
+		return result
+	}
+
+	newEngine := func(t *testing.T) *Engine {
+		engine, errCr := NewEngine(
+			&ParamsNewEngine{
+				ID:       1,
+				Name:     t.Name(),
+				Currency: "EUR",
+				Resources: []*ResourceScheduled{
+					newResource(1, 1),
+					newResource(2, 2),
+				},
+			},
+		)
+		require.NoError(t, errCr)
+
+		return engine
+	}
+
+	run := *newTestRun(1, oneHour/2, 1, 2)
+	run.Load = 2
+
+	params := ParamsCanRun{
+		TimeInterval: TimeInterval{
+			TimeStart: now,
+			TimeEnd:   now + oneHour,
+		},
+		TaskRun: &run,
+	}
+
+	// per resource: load 2 x 2.50, time 0.5h x 12.00, fee 3.33
+	lineSubtotal := int64(500 + 600 + 333)
+
+	t.Run(
+		"1. Option, without terms totals the option cost",
+		func(t *testing.T) {
+			engine := newEngine(t)
+
+			options, errGet := engine.GetSchedulingOptions(&params)
+			require.NoError(t, errGet)
+			require.NotEmpty(t, options)
+
+			quote, errQuote := engine.QuoteOption(&run, options[0], nil)
+			require.NoError(t, errQuote)
+			require.NotEmpty(t, quote.ID)
+			require.Len(t,
+				quote.Lines,
+				2,
+			)
+			require.Equal(t,
+				options[0].Cost,
+				quote.Total,
+			)
+			require.Equal(t,
+				quote.Subtotal,
+				quote.Total,
+			)
+
+			line := quote.Lines[0]
+
+			require.Equal(t,
+				NewMoney(500, "EUR"),
+				line.LoadCharge,
+			)
+			require.Equal(t,
+				NewMoney(600, "EUR"),
+				line.TimeCharge,
+			)
+			require.Equal(t,
+				NewMoney(lineSubtotal, "EUR"),
+				line.Subtotal,
+			)
+
+			_, errOtherRun := engine.QuoteOption(&Run{ID: 2}, options[0], nil)
+			require.Error(t, errOtherRun)
+		},
+	)
+
+	t.Run(
+		"2. Discounts and taxes",
+		func(t *testing.T) {
+			engine := newEngine(t)
+
+			options, errGet := engine.GetSchedulingOptions(&params)
+			require.NoError(t, errGet)
+
+			quote, errQuote := engine.QuoteOption(
+				&run,
+				options[0],
+				&QuoteTerms{
+					Discounts: []Discount{
+						{Name: "Loyalty", BasisPoints: 1000},
+						{Name: "Type 2", ResourceType: 2, BasisPoints: 500},
+					},
+					Taxes: []Tax{
+						{Name: "VAT", BasisPoints: 1900},
+					},
+				},
+			)
+			require.NoError(t, errQuote)
+
+			// type 1: 14.33 - 1.43 = 12.90, VAT 2.45 (2.451)
+			// type 2: 14.33 - 1.43 - 0.72 (0.7165) = 12.18, VAT 2.31 (2.3142)
+			for _, line := range quote.Lines {
+				switch line.ResourceType {
+				case 1:
+					require.Len(t, line.Discounts, 1)
+					require.Equal(t,
+						NewMoney(1290+245, "EUR"),
+						line.Total,
+					)
+
+				case 2:
+					require.Len(t, line.Discounts, 2)
+					require.Equal(t,
+						NewMoney(-72, "EUR"),
+						line.Discounts[1].Amount,
+					)
+					require.Equal(t,
+						NewMoney(1218+231, "EUR"),
+						line.Total,
+					)
+				}
+			}
+
+			require.Equal(t,
+				NewMoney(2*lineSubtotal, "EUR"),
+				quote.Subtotal,
+			)
+			require.Equal(t,
+				NewMoney(-143-143-72, "EUR"),
+				quote.Discounts,
+			)
+			require.Equal(t,
+				NewMoney(245+231, "EUR"),
+				quote.Taxes,
+			)
+			require.Equal(t,
+				quote.Subtotal.Amount+quote.Discounts.Amount+quote.Taxes.Amount,
+				quote.Total.Amount,
+			)
+
+			_, errInvalid := engine.QuoteOption(
+				&run,
+				options[0],
+				&QuoteTerms{
+					Discounts: []Discount{
+						{Name: "Too much", BasisPoints: 10001},
+					},
+				},
+			)
+			require.Error(t, errInvalid)
+		},
+	)
+
+	t.Run(
+		"3. JSON, text and reproduced by ID",
+		func(t *testing.T) {
+			engine := newEngine(t)
+
+			options, errGet := engine.GetSchedulingOptions(&params)
+			require.NoError(t, errGet)
+
+			terms := QuoteTerms{
+				Taxes: []Tax{
+					{Name: "VAT", BasisPoints: 1900},
+				},
+			}
+
+			quote, errQuote := engine.QuoteOption(&run, options[0], &terms)
+			require.NoError(t, errQuote)
+
+			encoded, errEncode := quote.JSON()
+			require.NoError(t, errEncode)
+			require.Contains(t,
+				string(encoded),
+				`"amount": "14.33"`,
+			)
+
+			var decoded Quote
+
+			require.NoError(t,
+				json.Unmarshal(encoded, &decoded),
+			)
+			require.Equal(t,
+				*quote,
+				decoded,
+			)
+
+			text := quote.String()
+			require.Contains(t, text, string(quote.ID))
+			require.Contains(t, text, "Resource 2")
+			require.Contains(t, text, "VAT 19.00%")
+			require.Contains(t, text, quote.Total.String())
+
+			fmt.Println(
+				text,
+			)
+
+			// prices change, the quote is kept as issued
+			engine.Resources[0].CostPerHour = Money{Amount: 9900}
+
+			reproduced, errGetQuote := engine.GetQuote(quote.ID)
+			require.NoError(t, errGetQuote)
+			require.Equal(t,
+				quote,
+				reproduced,
+			)
+
+			_, errUnknown := engine.GetQuote("Q-unknown")
+			require.Error(t, errUnknown)
+		},
+	)
+
+	t.Run(
+		"4. Same terms, same ID",
+		func(t *testing.T) {
+			engine := newEngine(t)
+
+			options, errGet := engine.GetSchedulingOptions(&params)
+			require.NoError(t, errGet)
+
+			first, errFirst := engine.QuoteOption(&run, options[0], nil)
+			require.NoError(t, errFirst)
+
+			second, errSecond := engine.QuoteOption(&run, options[0], nil)
+			require.NoError(t, errSecond)
+			require.Equal(t,
+				first.ID,
+				second.ID,
+			)
+
+			taxed, errTaxed := engine.QuoteOption(
+				&run,
+				options[0],
+				&QuoteTerms{
+					Taxes: []Tax{
+						{Name: "VAT", BasisPoints: 1900},
+					},
+				},
+			)
+			require.NoError(t, errTaxed)
+			require.NotEqual(t,
+				first.ID,
+				taxed.ID,
+			)
+		},
+	)
+
+	t.Run(
+		"5. Response of CanSchedule",
+		func(t *testing.T) {
+			engine := newEngine(t)
+
+			response, errSchedule := engine.CanSchedule(&params)
+			require.NoError(t, errSchedule)
+			require.True(t, response.WasScheduled)
+
+			quote, errQuote := engine.QuoteResponse(&run, response, nil)
+			require.NoError(t, errQuote)
+			require.Equal(t,
+				response.Cost,
+				quote.Total,
+			)
+			require.Equal(t,
+				now,
+				quote.TimeStart,
+			)
+
+			_, errEmpty := engine.QuoteResponse(&run, &ResponseCanRun{}, nil)
+			require.Error(t, errEmpty)
+
+			_, errOtherRun := engine.QuoteResponse(&Run{ID: 2}, response, nil)
+			require.Error(t, errOtherRun)
+		},
+	)
+
+	t.Run(
+		"6. Loco option",
+		func(t *testing.T) {
+			loco := Loco{
+				ID:       1,
+				Name:     t.Name(),
+				Currency: "EUR",
+
+				Resources: ResourcesPerType{
+					1: {newResource(1, 1)},
+					2: {newResource(2, 2)},
+				},
+			}
+
+			options, errGet := loco.GetSchedulingOptions(&params)
+			require.NoError(t, errGet)
+			require.NotEmpty(t, options)
+
+			cost, errCost := options[0].GetCostFor(&run)
+			require.NoError(t, errCost)
+
+			quote, errQuote := loco.QuoteOption(&run, options[0], nil)
+			require.NoError(t, errQuote)
+			require.Equal(t,
+				cost,
+				quote.Total,
+			)
+
+			reproduced, errGetQuote := loco.GetQuote(quote.ID)
+			require.NoError(t, errGetQuote)
+			require.Equal(t,
+				quote,
+				reproduced,
+			)
+		},
+	)
+
+	t.Run(
+		"7. Option and response quoted in location time",
+		func(t *testing.T) {
+			engine := newEngine(t)
+			engine.LocationOffset = 2 * oneHour
+
+			options, errGet := engine.GetSchedulingOptions(&params)
+			require.NoError(t, errGet)
+			require.NotEmpty(t, options)
+
+			optionQuote, errOption := engine.QuoteOption(&run, options[0], nil)
+			require.NoError(t, errOption)
+
+			response, errSchedule := engine.CanSchedule(&params)
+			require.NoError(t, errSchedule)
+			require.True(t, response.WasScheduled)
+
+			responseQuote, errResponse := engine.QuoteResponse(&run, response, nil)
+			require.NoError(t, errResponse)
+
+			require.Equal(t,
+				now+2*oneHour,
+				responseQuote.TimeStart,
+			)
+			require.Equal(t,
+				optionQuote.TimeStart,
+				responseQuote.TimeStart,
+			)
+			require.Equal(t,
+				optionQuote.SecondsOffset,
+				responseQuote.SecondsOffset,
+			)
+			require.Equal(t,
+				optionQuote.Total,
+				responseQuote.Total,
+			)
+		},
+	)
+}
@@ -0,0 +1,81 @@
+package scheduler
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newTestResource returns a resource serving one, priced at cost per load unit 1.
+func newTestResource(id int, resourceType uint8, cost int64, schedule map[TimeInterval]RunID) *ResourceScheduled {
+	return &ResourceScheduled{
+		ResourceInfo: ResourceInfo{
+			ID:              id,
+			Name:            fmt.Sprintf("Resource %d", id),
+			CostPerLoadUnit: map[uint8]Money{1: {Amount: cost}},
+			ResourceType:    resourceType,
+			ServedQuantity:  1,
+		},
+
+		schedule: newTestSchedule(schedule),
+	}
+}
+
+// newTestRun returns the run needing one resource of each type, with load 1 in load unit 1.
+func newTestRun(id int64, duration int64, resourceTypes ...uint8) *Run {
+	dependencies := make([]RunDependency, len(resourceTypes))
+
+	for ix, resourceType := range resourceTypes {
+		dependencies[ix] = RunDependency{
+			ResourceType:     resourceType,
+			ResourceQuantity: 1,
+		}
+	}
+
+	return &Run{
+		ID:                id,
+		EstimatedDuration: duration,
+
+		Dependencies: dependencies,
+
+		RunLoad: RunLoad{
+			Load:     1,
+			LoadUnit: 1,
+		},
+	}
+}
+
+// newTestLocation returns location 1 over the resources, named after the test.
+func newTestLocation(t *testing.T, resources ...*ResourceScheduled) *Location {
+	location, errCr := NewLocation(
+		&ParamsNewLocation{
+			ID:        1,
+			Name:      t.Name(),
+			Resources: resources,
+		},
+	)
+	require.NoError(t, errCr)
+
+	return location
+}
+
+// newTestRunPreferring returns the run of an hour needing one resource of type 1,
+// with the preference of the passed dependency.
+func newTestRunPreferring(id int64, preference RunDependency) *Run {
+	result := newTestRun(id, oneHour, 1)
+
+	result.Dependencies[0].PreferredResourceID = preference.PreferredResourceID
+	result.Dependencies[0].PreferenceRequired = preference.PreferenceRequired
+	result.Dependencies[0].PreferencePenalty = preference.PreferencePenalty
+
+	return result
+}
+
+// newTestResourceServing returns a resource of type 1 serving the quantity, zero serves one.
+func newTestResourceServing(id int, served uint16, cost int64) *ResourceScheduled {
+	result := newTestResource(id, 1, cost, nil)
+	result.ServedQuantity = served
+
+	return result
+}
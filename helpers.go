@@ -1,8 +1,10 @@
 package scheduler
 
 import (
+	"cmp"
 	"fmt"
 	"runtime"
+	"slices"
 )
 
 type maxIntegerTypes interface {
@@ -33,6 +35,18 @@ func ternary[T any](condition bool, value1, value2 T) T {
 	return value2
 }
 
+func sortedKeys[K cmp.Ordered, V any](m map[K]V) []K {
+	result := make([]K, 0, len(m))
+
+	for key := range m {
+		result = append(result, key)
+	}
+
+	slices.Sort(result)
+
+	return result
+}
+
 // Use as defer traceExit().
 func traceExit() {
 	pc, _, line, ok := runtime.Caller(1) // Get the caller of this function